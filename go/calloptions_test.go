@@ -0,0 +1,104 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// singleRegionProvider is a mock provider that can't override regions and
+// records the deadline of CreateDB calls.
+type singleRegionProvider struct {
+	*mock.MockProvider
+	deadline time.Duration
+}
+
+func (p *singleRegionProvider) Capabilities() cloudsdk.Capabilities {
+	caps := p.MockProvider.Capabilities()
+	caps.RegionOverride = false
+	return caps
+}
+
+func (p *singleRegionProvider) Database() services.Database {
+	return &deadlineDatabase{Database: p.MockProvider.Database(), provider: p}
+}
+
+type deadlineDatabase struct {
+	services.Database
+	provider *singleRegionProvider
+}
+
+func (d *deadlineDatabase) CreateDB(ctx context.Context, config *services.DBConfig) (*services.DBInstance, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		d.provider.deadline = time.Until(deadline).Round(time.Minute)
+	}
+	return d.Database.CreateDB(ctx, config)
+}
+
+func TestCallOptions(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		DefaultTags: map[string]string{"team": "platform", "env": "dev"},
+	})
+
+	// Call tags override default tags, and config tags override both
+	ctx := services.ContextWithCallOptions(context.Background(),
+		services.WithTags(map[string]string{"env": "staging", "request": "r-1"}),
+	)
+	ctx = services.ContextWithCallOptions(ctx, services.WithTags(map[string]string{"request": "r-2"}))
+	vmConfig := cloudsdktesting.GenerateVMConfig("web-server")
+	vmConfig.Tags = map[string]string{"request": "r-3"}
+	_, err := client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+	sent := provider.LastCallArgs("CreateVM")[0].(*services.VMConfig)
+	helper.AssertEqual("platform", sent.Tags["team"])
+	helper.AssertEqual("staging", sent.Tags["env"])
+	helper.AssertEqual("r-3", sent.Tags["request"])
+	helper.AssertEqual(1, len(vmConfig.Tags))
+
+	// Later options win; options don't leak into the parent context
+	helper.AssertEqual("r-2", services.CallOptionsFromContext(ctx).Tags["request"])
+	helper.AssertEqual(0, len(services.CallOptionsFromContext(context.Background()).Tags))
+
+	// The provider places resources in the call's region
+	ctx = services.ContextWithCallOptions(context.Background(), services.WithRegion("eu-west-1"))
+	db, err := client.Database().CreateDB(ctx, cloudsdktesting.GenerateDBConfig("app-db"))
+	helper.AssertNoError(err)
+	helper.AssertContains(db.Endpoint, "eu-west-1")
+}
+
+func TestCallOptionsTimeoutAndRegion(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	provider := &singleRegionProvider{MockProvider: mock.New("us-east-1")}
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		DefaultTimeout: time.Minute,
+	})
+
+	// The call's timeout takes precedence over the configured one
+	_, err := client.Database().CreateDB(context.Background(), cloudsdktesting.GenerateDBConfig("app-db"))
+	helper.AssertNoError(err)
+	helper.AssertEqual(time.Minute, provider.deadline)
+
+	ctx := services.ContextWithCallOptions(context.Background(), services.WithTimeout(30*time.Minute))
+	_, err = client.Database().CreateDB(ctx, cloudsdktesting.GenerateDBConfig("reporting-db"))
+	helper.AssertNoError(err)
+	helper.AssertEqual(30*time.Minute, provider.deadline)
+
+	// Providers that can't override regions reject other regions
+	ctx = services.ContextWithCallOptions(context.Background(), services.WithRegion("eu-west-1"))
+	err = client.Storage().CreateBucket(ctx, cloudsdktesting.GenerateBucketConfig("assets-2024"))
+	helper.AssertErrorCode(err, cloudsdk.ErrOperationNotSupported)
+	helper.AssertEqual(0, provider.CallCount("CreateBucket"))
+
+	// but accept their own
+	ctx = services.ContextWithCallOptions(context.Background(), services.WithRegion("us-east-1"))
+	helper.AssertNoError(client.Storage().CreateBucket(ctx, cloudsdktesting.GenerateBucketConfig("assets-2024")))
+}
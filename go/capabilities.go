@@ -0,0 +1,186 @@
+package cloudsdk
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// Capabilities reports which operations a provider supports and which
+// configuration fields it can't apply. Providers that support the same
+// services can still differ in sub-features such as spot instances,
+// placement groups, bucket replication or multi-AZ databases.
+//
+// The Client checks every call against its provider's Capabilities and fails
+// with ErrOperationNotSupported before anything is sent to the provider,
+// instead of letting the provider reject or silently ignore the request.
+//
+// Example:
+//
+//	caps := client.Capabilities()
+//	if caps.SupportsField(cloudsdk.OpCreateDB, "MultiAZ") {
+//	    config.MultiAZ = &multiAZ
+//	}
+type Capabilities struct {
+	// Operations lists the supported operations by name, such as OpCreateVM.
+	// If nil, every operation of the provider's supported services is
+	// assumed to be supported.
+	Operations []string
+
+	// UnsupportedFields lists, by operation name, the configuration fields
+	// the provider can't apply. Fields are named as in the config struct,
+	// with nested fields separated by dots, e.g. "ReplicationConfig" or
+	// "Encryption.KMSKeyID". Fields that aren't listed are supported.
+	UnsupportedFields map[string][]string
+
+	// DryRun reports whether the provider plans mutating operations itself
+	// when IsDryRun is true for their context, including permission checks
+	// where the cloud API supports them. For other providers, the Client
+	// plans dry runs by validating configs without calling the provider.
+	DryRun bool
+
+	// RegionOverride reports whether the provider can send a single call to
+	// another region when the call's services.CallOptions set Region. The
+	// Client rejects such calls for other providers.
+	RegionOverride bool
+}
+
+// SupportsOperation reports whether the provider supports the named operation.
+func (c Capabilities) SupportsOperation(operation string) bool {
+	if c.Operations == nil {
+		return true
+	}
+	for _, supported := range c.Operations {
+		if supported == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsField reports whether the provider applies a configuration field
+// of the named operation. A field is unsupported if it, or a field that
+// contains it, is listed in UnsupportedFields.
+func (c Capabilities) SupportsField(operation string, field string) bool {
+	if !c.SupportsOperation(operation) {
+		return false
+	}
+	for _, unsupported := range c.UnsupportedFields[operation] {
+		if field == unsupported || strings.HasPrefix(field, unsupported+".") {
+			return false
+		}
+	}
+	return true
+}
+
+// UnsupportedFieldsSet returns the unsupported fields of the named operation
+// that are set in config, a config struct or a pointer to one. Fields holding
+// their zero value, or an empty slice or map, are not set.
+func (c Capabilities) UnsupportedFieldsSet(operation string, config interface{}) []string {
+	var set []string
+	for _, field := range c.UnsupportedFields[operation] {
+		if fieldIsSet(reflect.ValueOf(config), field) {
+			set = append(set, field)
+		}
+	}
+	return set
+}
+
+// fieldIsSet reports whether the field at the dotted path in v holds a
+// non-zero value. Pointers along the path, including a pointer to the field's
+// value, are followed.
+func fieldIsSet(v reflect.Value, path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		v = indirect(v)
+		if v.Kind() != reflect.Struct {
+			return false
+		}
+		v = v.FieldByName(name)
+	}
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Slice, reflect.Map:
+		return v.Len() > 0
+	default:
+		return !v.IsZero()
+	}
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// Capabilities returns the operations and configuration fields supported by
+// the Client's provider.
+//
+// Example:
+//
+//	if client.Capabilities().SupportsOperation(cloudsdk.OpRequestSpotInstances) {
+//	    req, err := client.Compute().SpotInstances().Request(ctx, spotConfig)
+//	}
+func (c *Client) Capabilities() Capabilities {
+	return c.provider.Capabilities()
+}
+
+// checkCapabilities fails a call that the provider's Capabilities say it
+// can't carry out: an unsupported operation, an argument that sets an
+// unsupported configuration field, or a region override the provider can't
+// apply.
+func (c *Client) checkCapabilities(ctx context.Context, caps Capabilities, call *Call) error {
+	if !caps.SupportsOperation(call.Operation) {
+		return NewOperationNotSupportedError(call.Provider, string(call.Service), call.Operation)
+	}
+	if region := services.CallOptionsFromContext(ctx).Region; region != "" && region != c.provider.Region() && !caps.RegionOverride {
+		err := NewCloudError(ErrOperationNotSupported,
+			fmt.Sprintf("Provider '%s' does not support sending operation '%s' to region '%s'", call.Provider, call.Operation, region),
+			call.Provider, string(call.Service), call.Operation).
+			WithSuggestions(
+				"Create a provider for the other region instead of using services.WithRegion",
+				"Use Client.Capabilities().RegionOverride to check support before use",
+			)
+		err.Field = "Region"
+		return err
+	}
+	for _, arg := range call.Args {
+		if fields := caps.UnsupportedFieldsSet(call.Operation, arg); len(fields) > 0 {
+			return NewFieldNotSupportedError(call.Provider, string(call.Service), call.Operation, fields[0])
+		}
+	}
+	return nil
+}
+
+// NewOperationNotSupportedError creates an error for an operation that the
+// provider doesn't support, although it supports the operation's service.
+func NewOperationNotSupportedError(provider string, service string, operation string) *CloudError {
+	message := fmt.Sprintf("Provider '%s' does not support operation '%s'", provider, operation)
+	return NewCloudError(ErrOperationNotSupported, message, provider, service, operation).
+		WithSuggestions(
+			"Use Client.Capabilities() to check operation support before use",
+			"Switch to a provider that supports this operation",
+		)
+}
+
+// NewFieldNotSupportedError creates an error for a configuration field that
+// the provider can't apply to an operation. The error's Field is set to field.
+func NewFieldNotSupportedError(provider string, service string, operation string, field string) *CloudError {
+	message := fmt.Sprintf("Provider '%s' does not support field '%s' for operation '%s'", provider, field, operation)
+	err := NewCloudError(ErrOperationNotSupported, message, provider, service, operation).
+		WithSuggestions(
+			fmt.Sprintf("Leave '%s' unset for this provider", field),
+			"Use Client.Capabilities().SupportsField() to check field support before use",
+		)
+	err.Field = field
+	return err
+}
@@ -0,0 +1,77 @@
+package cloudsdk_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestClientCapabilities(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1").
+		WithUnsupportedOperations(cloudsdk.OpRequestSpotInstances).
+		WithUnsupportedFields(cloudsdk.OpCreateDB, "MultiAZ").
+		WithUnsupportedFields(cloudsdk.OpCreateBucket, "Tags")
+	var logs bytes.Buffer
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		DefaultTags: map[string]string{"team": "platform"},
+		Logger:      slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	caps := client.Capabilities()
+	helper.AssertEqual(true, caps.SupportsOperation(cloudsdk.OpCreateVM))
+	helper.AssertEqual(false, caps.SupportsOperation(cloudsdk.OpRequestSpotInstances))
+	helper.AssertEqual(false, caps.SupportsField(cloudsdk.OpCreateDB, "MultiAZ"))
+	helper.AssertEqual(true, caps.SupportsField(cloudsdk.OpCreateDB, "StorageEncrypted"))
+
+	// Unsupported operations fail before reaching the provider
+	_, err := client.Compute().SpotInstances().Request(ctx, nil)
+	helper.AssertErrorCode(err, cloudsdk.ErrOperationNotSupported)
+	helper.AssertEqual(0, provider.CallCount("RequestSpotInstances"))
+
+	// So do configs that set an unsupported field
+	multiAZ := true
+	dbConfig := cloudsdktesting.GenerateDBConfig("app-db")
+	dbConfig.MultiAZ = &multiAZ
+	_, err = client.Database().CreateDB(ctx, dbConfig)
+	helper.AssertErrorCode(err, cloudsdk.ErrOperationNotSupported)
+	helper.AssertEqual("MultiAZ", err.(*cloudsdk.CloudError).Field)
+	helper.AssertEqual(0, provider.CallCount("CreateDB"))
+
+	dbConfig.MultiAZ = nil
+	_, err = client.Database().CreateDB(ctx, dbConfig)
+	helper.AssertNoError(err)
+
+	// Default tags are left out for resources the provider can't tag, with a
+	// warning
+	bucketConfig := cloudsdktesting.GenerateBucketConfig("my-bucket-2024")
+	bucketConfig.Tags = nil
+	helper.AssertNoError(client.Storage().CreateBucket(ctx, bucketConfig))
+	helper.AssertContains(logs.String(), "cloudsdk tags not applied")
+	helper.AssertContains(logs.String(), "tags=[team]")
+
+	bucketConfig = cloudsdktesting.GenerateBucketConfig("my-other-bucket")
+	err = client.Storage().CreateBucket(ctx, bucketConfig)
+	helper.AssertErrorCode(err, cloudsdk.ErrOperationNotSupported)
+}
+
+func TestProviderContractSkipsUnsupported(t *testing.T) {
+	provider := mock.New("us-east-1").
+		WithSupportedServices(cloudsdk.ServiceCompute, cloudsdk.ServiceDatabase).
+		WithUnsupportedOperations(cloudsdk.OpStartVM, cloudsdk.OpStopVM, cloudsdk.OpRequestSpotInstances).
+		WithUnsupportedFields(cloudsdk.OpCreateDB, "MultiAZ", "Tags")
+
+	caps := provider.Capabilities()
+	if caps.SupportsOperation(cloudsdk.OpCreateBucket) {
+		t.Errorf("expected storage operations to be unsupported without the storage service")
+	}
+
+	cloudsdktesting.RunProviderContractTests(t, provider)
+}
@@ -0,0 +1,272 @@
+package cloudsdk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets calls through and counts consecutive failures.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails calls fast with ErrCircuitOpen until OpenTimeout passes.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of trial calls through. A success
+	// closes the circuit; a failure opens it again.
+	CircuitHalfOpen
+)
+
+// String returns the state's name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitStateChange describes a circuit breaker changing state.
+type CircuitStateChange struct {
+	Provider string
+	Service  ServiceType
+	From     CircuitState
+	To       CircuitState
+
+	// Err is the error that opened the circuit, or nil.
+	Err error
+}
+
+// CircuitBreakerConfig enables a circuit breaker for each service of a Client.
+//
+// When a provider's service keeps failing with transient errors, the breaker
+// opens and calls to that service fail fast with ErrCircuitOpen instead of
+// piling up behind timeouts and retries. After OpenTimeout it lets a trial
+// call through to find out whether the service has recovered.
+//
+// Example:
+//
+//	client := cloudsdk.New(provider, &cloudsdk.Config{
+//	    CircuitBreaker: &cloudsdk.CircuitBreakerConfig{
+//	        FailureThreshold: 5,
+//	        OpenTimeout:      30 * time.Second,
+//	        OnStateChange: func(change cloudsdk.CircuitStateChange) {
+//	            log.Printf("%s %s circuit %s -> %s", change.Provider, change.Service, change.From, change.To)
+//	        },
+//	    },
+//	})
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. Defaults to 5.
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before letting trial
+	// calls through. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+
+	// HalfOpenMaxCalls is the number of trial calls allowed at once while
+	// half-open. Defaults to 1.
+	HalfOpenMaxCalls int
+
+	// FailureCodes are the CloudError codes that count as failures.
+	// Defaults to ErrRateLimit, ErrNetworkTimeout and ErrProviderError.
+	// Other errors, such as ErrResourceNotFound, show the service is
+	// responding and count as successes.
+	FailureCodes []ErrorCode
+
+	// OnStateChange, if set, is called whenever a breaker changes state.
+	// It runs synchronously on the goroutine making the call.
+	OnStateChange func(change CircuitStateChange)
+}
+
+// defaultFailureCodes are the error codes that count as breaker failures by default.
+var defaultFailureCodes = []ErrorCode{ErrRateLimit, ErrNetworkTimeout, ErrProviderError}
+
+// circuitBreaker tracks the health of one provider service.
+type circuitBreaker struct {
+	provider string
+	service  ServiceType
+	config   CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func newCircuitBreaker(provider string, service ServiceType, config CircuitBreakerConfig) *circuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+	if len(config.FailureCodes) == 0 {
+		config.FailureCodes = defaultFailureCodes
+	}
+	return &circuitBreaker{provider: provider, service: service, config: config}
+}
+
+// allow reports whether a call may proceed. When it returns nil the caller
+// must report the outcome with done.
+func (b *circuitBreaker) allow(operation string) error {
+	b.mu.Lock()
+	var change *CircuitStateChange
+	defer func() {
+		b.mu.Unlock()
+		b.notify(change)
+	}()
+
+	if b.state == CircuitOpen {
+		remaining := b.config.OpenTimeout - time.Since(b.openedAt)
+		if remaining > 0 {
+			return NewCircuitOpenError(b.provider, string(b.service), operation, remaining)
+		}
+		change = b.transition(CircuitHalfOpen, nil)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.trials >= b.config.HalfOpenMaxCalls {
+			return NewCircuitOpenError(b.provider, string(b.service), operation, 0)
+		}
+		b.trials++
+	}
+	return nil
+}
+
+// done records the outcome of a call that allow let through.
+func (b *circuitBreaker) done(err error) {
+	failed := b.isFailure(err)
+
+	b.mu.Lock()
+	var change *CircuitStateChange
+	defer func() {
+		b.mu.Unlock()
+		b.notify(change)
+	}()
+
+	switch b.state {
+	case CircuitHalfOpen:
+		b.trials--
+		if failed {
+			change = b.transition(CircuitOpen, err)
+		} else {
+			change = b.transition(CircuitClosed, nil)
+		}
+	case CircuitClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			change = b.transition(CircuitOpen, err)
+		}
+	}
+}
+
+// release gives back a half-open trial without recording an outcome, for
+// calls abandoned by the caller.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+}
+
+// transition moves the breaker to state and returns the change to report.
+// It must be called with b.mu held.
+func (b *circuitBreaker) transition(state CircuitState, err error) *CircuitStateChange {
+	change := &CircuitStateChange{Provider: b.provider, Service: b.service, From: b.state, To: state, Err: err}
+	b.state = state
+	b.failures = 0
+	b.trials = 0
+	if state == CircuitOpen {
+		b.openedAt = time.Now()
+	}
+	return change
+}
+
+func (b *circuitBreaker) notify(change *CircuitStateChange) {
+	if change != nil && b.config.OnStateChange != nil {
+		b.config.OnStateChange(*change)
+	}
+}
+
+// isFailure reports whether err counts against the breaker.
+func (b *circuitBreaker) isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var cloudErr *CloudError
+	if !errors.As(err, &cloudErr) {
+		// Errors providers didn't classify are treated as provider errors
+		return b.countsCode(ErrProviderError)
+	}
+	return b.countsCode(cloudErr.Code)
+}
+
+func (b *circuitBreaker) countsCode(code ErrorCode) bool {
+	for _, failureCode := range b.config.FailureCodes {
+		if failureCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// breakerFor returns the circuit breaker for service, or nil when the Client
+// has no CircuitBreakerConfig.
+func (c *Client) breakerFor(service ServiceType) *circuitBreaker {
+	if c.config.CircuitBreaker == nil {
+		return nil
+	}
+	c.mu.RLock()
+	breaker, ok := c.breakers[service]
+	c.mu.RUnlock()
+	if ok {
+		return breaker
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.breakers == nil {
+		c.breakers = make(map[ServiceType]*circuitBreaker)
+	}
+	breaker, ok = c.breakers[service]
+	if !ok {
+		breaker = newCircuitBreaker(c.provider.Name(), service, *c.config.CircuitBreaker)
+		c.breakers[service] = breaker
+	}
+	return breaker
+}
+
+// CircuitState returns the state of the circuit breaker for service.
+// It returns CircuitClosed when the Client has no CircuitBreakerConfig.
+func (c *Client) CircuitState(service ServiceType) CircuitState {
+	breaker := c.breakerFor(service)
+	if breaker == nil {
+		return CircuitClosed
+	}
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	if breaker.state == CircuitOpen && time.Since(breaker.openedAt) >= breaker.config.OpenTimeout {
+		// The next call will be let through as a trial
+		return CircuitHalfOpen
+	}
+	return breaker.state
+}
@@ -0,0 +1,83 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestClientCircuitBreaker(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	var changes []cloudsdk.CircuitStateChange
+	provider := mock.New("us-east-1").
+		WithError("ListVMs", cloudsdk.NewCloudError(cloudsdk.ErrProviderError, "Service unavailable", "mock", "compute", "ListVMs"))
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		CircuitBreaker: &cloudsdk.CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenTimeout:      30 * time.Millisecond,
+			OnStateChange: func(change cloudsdk.CircuitStateChange) {
+				changes = append(changes, change)
+			},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Compute().ListVMs(ctx)
+		helper.AssertErrorCode(err, cloudsdk.ErrProviderError)
+	}
+	helper.AssertEqual(cloudsdk.CircuitOpen, client.CircuitState(cloudsdk.ServiceCompute))
+
+	// Open circuits fail fast without calling the provider
+	_, err := client.Compute().ListVMs(ctx)
+	helper.AssertErrorCode(err, cloudsdk.ErrCircuitOpen)
+	helper.AssertEqual(2, provider.CallCount("ListVMs"))
+	if hint := cloudsdk.RetryAfterHint(err); hint <= 0 || hint > 30*time.Millisecond {
+		t.Errorf("expected a retry-after hint up to 30ms, got %v", hint)
+	}
+
+	// Other services have their own breaker
+	_, err = client.Storage().ListBuckets(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(cloudsdk.CircuitClosed, client.CircuitState(cloudsdk.ServiceStorage))
+
+	// After OpenTimeout a successful trial call closes the circuit
+	provider.WithError("ListVMs", nil)
+	time.Sleep(40 * time.Millisecond)
+	helper.AssertEqual(cloudsdk.CircuitHalfOpen, client.CircuitState(cloudsdk.ServiceCompute))
+	_, err = client.Compute().ListVMs(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(cloudsdk.CircuitClosed, client.CircuitState(cloudsdk.ServiceCompute))
+
+	helper.AssertEqual(3, len(changes))
+	helper.AssertEqual(cloudsdk.CircuitClosed, changes[0].From)
+	helper.AssertEqual(cloudsdk.CircuitOpen, changes[0].To)
+	helper.AssertEqual(cloudsdk.ServiceCompute, changes[0].Service)
+	helper.AssertEqual("mock", changes[0].Provider)
+	helper.AssertErrorCode(changes[0].Err, cloudsdk.ErrProviderError)
+	helper.AssertEqual(cloudsdk.CircuitHalfOpen, changes[1].To)
+	helper.AssertEqual(cloudsdk.CircuitClosed, changes[2].To)
+}
+
+func TestClientCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		CircuitBreaker: &cloudsdk.CircuitBreakerConfig{FailureThreshold: 1},
+	})
+
+	// A missing resource means the service is up
+	for i := 0; i < 3; i++ {
+		_, err := client.Compute().GetVM(ctx, "i-missing")
+		helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	}
+	helper.AssertEqual(cloudsdk.CircuitClosed, client.CircuitState(cloudsdk.ServiceCompute))
+	helper.AssertEqual(3, provider.CallCount("GetVM"))
+}
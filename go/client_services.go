@@ -0,0 +1,644 @@
+package cloudsdk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// The types in this file wrap the services returned by a Provider so that every
+// call made through a Client goes through Client.invoke. They only add Client
+// behavior; all resource handling stays in the provider implementation.
+
+func (c *Client) wrapCompute(svc services.Compute) services.Compute {
+	if svc == nil {
+		return nil
+	}
+	return &clientCompute{client: c, svc: svc}
+}
+
+func (c *Client) wrapStorage(svc services.Storage) services.Storage {
+	if svc == nil {
+		return nil
+	}
+	return &clientStorage{client: c, svc: svc}
+}
+
+func (c *Client) wrapDatabase(svc services.Database) services.Database {
+	if svc == nil {
+		return nil
+	}
+	return &clientDatabase{client: c, svc: svc}
+}
+
+// copyTags returns a copy of tags, or nil if tags is empty.
+func copyTags(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(tags))
+	for key, value := range tags {
+		copied[key] = value
+	}
+	return copied
+}
+
+// mergeTags returns a copy of the Client's default tags overlaid with the
+// tags of the call's services.CallOptions, then with tags. Explicit tags win
+// over call tags, which win over defaults. The input map is never modified,
+// so middleware can add tags to the result without affecting the caller.
+// Default and call tags are left out if the provider can't tag resources
+// created by the operation, with a warning to the configured logger: they
+// apply to every create, so failing would make the operation unusable.
+// Explicit tags are still rejected by the capability check.
+func (c *Client) mergeTags(ctx context.Context, operation string, tags map[string]string) map[string]string {
+	callTags := services.CallOptionsFromContext(ctx).Tags
+	if len(c.config.DefaultTags)+len(callTags) == 0 {
+		return copyTags(tags)
+	}
+	if !c.provider.Capabilities().SupportsField(operation, "Tags") {
+		c.logDroppedTags(ctx, operation, callTags)
+		return copyTags(tags)
+	}
+	merged := copyTags(c.config.DefaultTags)
+	if merged == nil {
+		merged = make(map[string]string, len(callTags)+len(tags))
+	}
+	for key, value := range callTags {
+		merged[key] = value
+	}
+	for key, value := range tags {
+		merged[key] = value
+	}
+	return merged
+}
+
+// logDroppedTags warns that the default and call tags of an operation were
+// left out because the provider can't tag the resources it creates.
+func (c *Client) logDroppedTags(ctx context.Context, operation string, callTags map[string]string) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	var keys []string
+	for key := range c.config.DefaultTags {
+		keys = append(keys, key)
+	}
+	for key := range callTags {
+		if _, ok := c.config.DefaultTags[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "cloudsdk tags not applied",
+		slog.String("provider", c.provider.Name()),
+		slog.String("operation", operation),
+		slog.String("reason", "provider does not support tags for this operation"),
+		slog.Any("tags", keys),
+	)
+}
+
+// clientCompute wraps a provider's compute service.
+type clientCompute struct {
+	client *Client
+	svc    services.Compute
+}
+
+func (s *clientCompute) CreateVM(ctx context.Context, config *services.VMConfig) (*services.VM, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateVM, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpCreateVM, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.CreateVM(ctx, config)
+	})
+	vm, _ := result.(*services.VM)
+	return vm, err
+}
+
+func (s *clientCompute) ListVMs(ctx context.Context) ([]*services.VM, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListVMs, []interface{}{}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.ListVMs(ctx)
+	})
+	vms, _ := result.([]*services.VM)
+	return vms, err
+}
+
+func (s *clientCompute) ListVMsWithOptions(ctx context.Context, opts *services.ListVMsOptions) (*services.VMPage, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListVMsWithOptions, []interface{}{opts}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.ListVMsWithOptions(ctx, opts)
+	})
+	page, _ := result.(*services.VMPage)
+	return page, err
+}
+
+func (s *clientCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpGetVM, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.GetVM(ctx, id)
+	})
+	vm, _ := result.(*services.VM)
+	return vm, err
+}
+
+func (s *clientCompute) StartVM(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpStartVM, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.StartVM(ctx, id)
+	})
+	return err
+}
+
+func (s *clientCompute) StopVM(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpStopVM, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.StopVM(ctx, id)
+	})
+	return err
+}
+
+func (s *clientCompute) DeleteVM(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDeleteVM, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.DeleteVM(ctx, id)
+	})
+	return err
+}
+
+func (s *clientCompute) RebootVM(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpRebootVM, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.RebootVM(ctx, id)
+	})
+	return err
+}
+
+func (s *clientCompute) ResizeVM(ctx context.Context, id string, instanceType string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpResizeVM, []interface{}{id, instanceType}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.ResizeVM(ctx, id, instanceType)
+	})
+	return err
+}
+
+func (s *clientCompute) InstanceTypes() services.InstanceTypesService {
+	svc := s.svc.InstanceTypes()
+	if svc == nil {
+		return nil
+	}
+	return &clientInstanceTypes{client: s.client, svc: svc}
+}
+
+func (s *clientCompute) PlacementGroups() services.PlacementGroupsService {
+	svc := s.svc.PlacementGroups()
+	if svc == nil {
+		return nil
+	}
+	return &clientPlacementGroups{client: s.client, svc: svc}
+}
+
+func (s *clientCompute) SpotInstances() services.SpotInstancesService {
+	svc := s.svc.SpotInstances()
+	if svc == nil {
+		return nil
+	}
+	return &clientSpotInstances{client: s.client, svc: svc}
+}
+
+func (s *clientCompute) Volumes() services.VolumesService {
+	svc := s.svc.Volumes()
+	if svc == nil {
+		return nil
+	}
+	return &clientVolumes{client: s.client, svc: svc}
+}
+
+func (s *clientCompute) Images() services.ImagesService {
+	svc := s.svc.Images()
+	if svc == nil {
+		return nil
+	}
+	return &clientImages{client: s.client, svc: svc}
+}
+
+func (s *clientCompute) KeyPairs() services.KeyPairsService {
+	svc := s.svc.KeyPairs()
+	if svc == nil {
+		return nil
+	}
+	return &clientKeyPairs{client: s.client, svc: svc}
+}
+
+func (s *clientCompute) SecurityGroups() services.SecurityGroupsService {
+	svc := s.svc.SecurityGroups()
+	if svc == nil {
+		return nil
+	}
+	return &clientSecurityGroups{client: s.client, svc: svc}
+}
+
+// clientInstanceTypes wraps a provider's instance types service.
+type clientInstanceTypes struct {
+	client *Client
+	svc    services.InstanceTypesService
+}
+
+func (s *clientInstanceTypes) List(ctx context.Context, filter *services.InstanceTypeFilter) ([]*services.InstanceType, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListInstanceTypes, []interface{}{filter}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.List(ctx, filter)
+	})
+	types, _ := result.([]*services.InstanceType)
+	return types, err
+}
+
+// clientPlacementGroups wraps a provider's placement groups service.
+type clientPlacementGroups struct {
+	client *Client
+	svc    services.PlacementGroupsService
+}
+
+func (s *clientPlacementGroups) Create(ctx context.Context, config *services.PlacementGroupConfig) (*services.PlacementGroup, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpCreatePlacementGroup, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Create(ctx, config)
+	})
+	group, _ := result.(*services.PlacementGroup)
+	return group, err
+}
+
+func (s *clientPlacementGroups) Delete(ctx context.Context, groupName string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDeletePlacementGroup, []interface{}{groupName}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Delete(ctx, groupName)
+	})
+	return err
+}
+
+func (s *clientPlacementGroups) List(ctx context.Context) ([]*services.PlacementGroup, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListPlacementGroups, []interface{}{}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.List(ctx)
+	})
+	groups, _ := result.([]*services.PlacementGroup)
+	return groups, err
+}
+
+// clientSpotInstances wraps a provider's spot instances service.
+type clientSpotInstances struct {
+	client *Client
+	svc    services.SpotInstancesService
+}
+
+func (s *clientSpotInstances) Request(ctx context.Context, config *services.SpotInstanceConfig) (*services.SpotInstanceRequest, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpRequestSpotInstances, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Request(ctx, config)
+	})
+	request, _ := result.(*services.SpotInstanceRequest)
+	return request, err
+}
+
+func (s *clientSpotInstances) Describe(ctx context.Context, requestIds []string) ([]*services.SpotInstanceRequest, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpDescribeSpotInstanceRequests, []interface{}{requestIds}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Describe(ctx, requestIds)
+	})
+	requests, _ := result.([]*services.SpotInstanceRequest)
+	return requests, err
+}
+
+func (s *clientSpotInstances) Cancel(ctx context.Context, requestId string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpCancelSpotInstanceRequests, []interface{}{requestId}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Cancel(ctx, requestId)
+	})
+	return err
+}
+
+// clientVolumes wraps a provider's volumes service.
+type clientVolumes struct {
+	client *Client
+	svc    services.VolumesService
+}
+
+func (s *clientVolumes) Create(ctx context.Context, config *services.VolumeConfig) (*services.Volume, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateVolume, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpCreateVolume, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Create(ctx, config)
+	})
+	volume, _ := result.(*services.Volume)
+	return volume, err
+}
+
+func (s *clientVolumes) List(ctx context.Context, filter *services.VolumeFilter) ([]*services.Volume, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListVolumes, []interface{}{filter}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.List(ctx, filter)
+	})
+	volumes, _ := result.([]*services.Volume)
+	return volumes, err
+}
+
+func (s *clientVolumes) Get(ctx context.Context, id string) (*services.Volume, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpGetVolume, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Get(ctx, id)
+	})
+	volume, _ := result.(*services.Volume)
+	return volume, err
+}
+
+func (s *clientVolumes) Attach(ctx context.Context, volumeID, vmID, device string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpAttachVolume, []interface{}{volumeID, vmID, device}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Attach(ctx, volumeID, vmID, device)
+	})
+	return err
+}
+
+func (s *clientVolumes) Detach(ctx context.Context, volumeID string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDetachVolume, []interface{}{volumeID}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Detach(ctx, volumeID)
+	})
+	return err
+}
+
+func (s *clientVolumes) Resize(ctx context.Context, volumeID string, sizeGiB int32) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpResizeVolume, []interface{}{volumeID, sizeGiB}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Resize(ctx, volumeID, sizeGiB)
+	})
+	return err
+}
+
+func (s *clientVolumes) Snapshot(ctx context.Context, config *services.VolumeSnapshotConfig) (*services.VolumeSnapshot, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpSnapshotVolume, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpSnapshotVolume, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Snapshot(ctx, config)
+	})
+	snapshot, _ := result.(*services.VolumeSnapshot)
+	return snapshot, err
+}
+
+func (s *clientVolumes) Delete(ctx context.Context, volumeID string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDeleteVolume, []interface{}{volumeID}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Delete(ctx, volumeID)
+	})
+	return err
+}
+
+// clientImages wraps a provider's images service.
+type clientImages struct {
+	client *Client
+	svc    services.ImagesService
+}
+
+func (s *clientImages) List(ctx context.Context, filter *services.ImageFilter) ([]*services.Image, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListImages, []interface{}{filter}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.List(ctx, filter)
+	})
+	images, _ := result.([]*services.Image)
+	return images, err
+}
+
+func (s *clientImages) Get(ctx context.Context, id string) (*services.Image, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpGetImage, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Get(ctx, id)
+	})
+	image, _ := result.(*services.Image)
+	return image, err
+}
+
+func (s *clientImages) Latest(ctx context.Context, filter *services.ImageFilter) (*services.Image, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpLatestImage, []interface{}{filter}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Latest(ctx, filter)
+	})
+	image, _ := result.(*services.Image)
+	return image, err
+}
+
+func (s *clientImages) Create(ctx context.Context, config *services.CreateImageConfig) (*services.Image, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateImage, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpCreateImage, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Create(ctx, config)
+	})
+	image, _ := result.(*services.Image)
+	return image, err
+}
+
+func (s *clientImages) Deregister(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDeregisterImage, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Deregister(ctx, id)
+	})
+	return err
+}
+
+// clientKeyPairs wraps a provider's key pairs service.
+type clientKeyPairs struct {
+	client *Client
+	svc    services.KeyPairsService
+}
+
+func (s *clientKeyPairs) Create(ctx context.Context, config *services.KeyPairConfig) (*services.KeyPair, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateKeyPair, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpCreateKeyPair, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Create(ctx, config)
+	})
+	keyPair, _ := result.(*services.KeyPair)
+	return keyPair, err
+}
+
+func (s *clientKeyPairs) Import(ctx context.Context, config *services.ImportKeyPairConfig) (*services.KeyPair, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpImportKeyPair, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpImportKeyPair, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Import(ctx, config)
+	})
+	keyPair, _ := result.(*services.KeyPair)
+	return keyPair, err
+}
+
+func (s *clientKeyPairs) List(ctx context.Context) ([]*services.KeyPair, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListKeyPairs, []interface{}{}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.List(ctx)
+	})
+	keyPairs, _ := result.([]*services.KeyPair)
+	return keyPairs, err
+}
+
+func (s *clientKeyPairs) Delete(ctx context.Context, name string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDeleteKeyPair, []interface{}{name}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Delete(ctx, name)
+	})
+	return err
+}
+
+// clientSecurityGroups wraps a provider's security groups service.
+type clientSecurityGroups struct {
+	client *Client
+	svc    services.SecurityGroupsService
+}
+
+func (s *clientSecurityGroups) Create(ctx context.Context, config *services.SecurityGroupConfig) (*services.SecurityGroup, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateSecurityGroup, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceCompute, OpCreateSecurityGroup, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Create(ctx, config)
+	})
+	group, _ := result.(*services.SecurityGroup)
+	return group, err
+}
+
+func (s *clientSecurityGroups) List(ctx context.Context, filter *services.SecurityGroupFilter) ([]*services.SecurityGroup, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpListSecurityGroups, []interface{}{filter}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.List(ctx, filter)
+	})
+	groups, _ := result.([]*services.SecurityGroup)
+	return groups, err
+}
+
+func (s *clientSecurityGroups) Get(ctx context.Context, id string) (*services.SecurityGroup, error) {
+	result, err := s.client.invoke(ctx, ServiceCompute, OpGetSecurityGroup, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.Get(ctx, id)
+	})
+	group, _ := result.(*services.SecurityGroup)
+	return group, err
+}
+
+func (s *clientSecurityGroups) AddRules(ctx context.Context, groupID string, rules []services.FirewallRule) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpAddSecurityGroupRules, []interface{}{groupID, rules}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.AddRules(ctx, groupID, rules)
+	})
+	return err
+}
+
+func (s *clientSecurityGroups) RevokeRules(ctx context.Context, groupID string, rules []services.FirewallRule) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpRevokeSecurityGroupRules, []interface{}{groupID, rules}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.RevokeRules(ctx, groupID, rules)
+	})
+	return err
+}
+
+func (s *clientSecurityGroups) Delete(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceCompute, OpDeleteSecurityGroup, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.Delete(ctx, id)
+	})
+	return err
+}
+
+// clientStorage wraps a provider's storage service.
+type clientStorage struct {
+	client *Client
+	svc    services.Storage
+}
+
+func (s *clientStorage) CreateBucket(ctx context.Context, config *services.BucketConfig) error {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateBucket, config.Tags)
+		config = &withDefaults
+	}
+	_, err := s.client.invoke(ctx, ServiceStorage, OpCreateBucket, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.CreateBucket(ctx, config)
+	})
+	return err
+}
+
+func (s *clientStorage) ListBuckets(ctx context.Context) ([]string, error) {
+	result, err := s.client.invoke(ctx, ServiceStorage, OpListBuckets, []interface{}{}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.ListBuckets(ctx)
+	})
+	buckets, _ := result.([]string)
+	return buckets, err
+}
+
+func (s *clientStorage) DeleteBucket(ctx context.Context, name string) error {
+	_, err := s.client.invoke(ctx, ServiceStorage, OpDeleteBucket, []interface{}{name}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.DeleteBucket(ctx, name)
+	})
+	return err
+}
+
+func (s *clientStorage) PutObject(ctx context.Context, bucket, key string, body io.Reader) error {
+	_, err := s.client.invoke(ctx, ServiceStorage, OpPutObject, []interface{}{bucket, key, body}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.PutObject(ctx, bucket, key, body)
+	})
+	return err
+}
+
+func (s *clientStorage) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	result, err := s.client.invoke(ctx, ServiceStorage, OpGetObject, []interface{}{bucket, key}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.GetObject(ctx, bucket, key)
+	})
+	body, _ := result.(io.ReadCloser)
+	return body, err
+}
+
+func (s *clientStorage) DeleteObject(ctx context.Context, bucket, key string) error {
+	_, err := s.client.invoke(ctx, ServiceStorage, OpDeleteObject, []interface{}{bucket, key}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.DeleteObject(ctx, bucket, key)
+	})
+	return err
+}
+
+func (s *clientStorage) ListObjects(ctx context.Context, bucket string) ([]*services.Object, error) {
+	result, err := s.client.invoke(ctx, ServiceStorage, OpListObjects, []interface{}{bucket}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.ListObjects(ctx, bucket)
+	})
+	objects, _ := result.([]*services.Object)
+	return objects, err
+}
+
+// clientDatabase wraps a provider's database service.
+type clientDatabase struct {
+	client *Client
+	svc    services.Database
+}
+
+func (s *clientDatabase) CreateDB(ctx context.Context, config *services.DBConfig) (*services.DBInstance, error) {
+	if config != nil {
+		withDefaults := *config
+		withDefaults.Tags = s.client.mergeTags(ctx, OpCreateDB, config.Tags)
+		config = &withDefaults
+	}
+	result, err := s.client.invoke(ctx, ServiceDatabase, OpCreateDB, []interface{}{config}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.CreateDB(ctx, config)
+	})
+	db, _ := result.(*services.DBInstance)
+	return db, err
+}
+
+func (s *clientDatabase) ListDBs(ctx context.Context) ([]*services.DBInstance, error) {
+	result, err := s.client.invoke(ctx, ServiceDatabase, OpListDBs, []interface{}{}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.ListDBs(ctx)
+	})
+	dbs, _ := result.([]*services.DBInstance)
+	return dbs, err
+}
+
+func (s *clientDatabase) GetDB(ctx context.Context, id string) (*services.DBInstance, error) {
+	result, err := s.client.invoke(ctx, ServiceDatabase, OpGetDB, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return s.svc.GetDB(ctx, id)
+	})
+	db, _ := result.(*services.DBInstance)
+	return db, err
+}
+
+func (s *clientDatabase) DeleteDB(ctx context.Context, id string) error {
+	_, err := s.client.invoke(ctx, ServiceDatabase, OpDeleteDB, []interface{}{id}, func(ctx context.Context) (interface{}, error) {
+		return nil, s.svc.DeleteDB(ctx, id)
+	})
+	return err
+}
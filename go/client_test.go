@@ -0,0 +1,458 @@
+package cloudsdk_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// blockingProvider wraps the mock provider with a compute service whose GetVM
+// blocks until its context is done, to observe Client timeouts.
+type blockingProvider struct {
+	*mock.MockProvider
+}
+
+func (p *blockingProvider) Compute() services.Compute {
+	return &blockingCompute{Compute: p.MockProvider.Compute()}
+}
+
+type blockingCompute struct {
+	services.Compute
+}
+
+func (c *blockingCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestClientDefaultTags(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		DefaultTags: map[string]string{"team": "platform", "env": "dev"},
+	})
+
+	vmConfig := cloudsdktesting.GenerateVMConfig("web-server")
+	vmConfig.Tags = map[string]string{"env": "prod"}
+	_, err := client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+
+	sent := provider.LastCallArgs("CreateVM")[0].(*services.VMConfig)
+	helper.AssertEqual("platform", sent.Tags["team"])
+	helper.AssertEqual("prod", sent.Tags["env"])
+
+	// The caller's config must not be modified
+	helper.AssertEqual(1, len(vmConfig.Tags))
+
+	err = client.Storage().CreateBucket(ctx, cloudsdktesting.GenerateBucketConfig("tagged-bucket"))
+	helper.AssertNoError(err)
+	bucketConfig := provider.LastCallArgs("CreateBucket")[0].(*services.BucketConfig)
+	helper.AssertEqual("platform", bucketConfig.Tags["team"])
+
+	_, err = client.Database().CreateDB(ctx, cloudsdktesting.GenerateDBConfig("tagged-db"))
+	helper.AssertNoError(err)
+	dbConfig := provider.LastCallArgs("CreateDB")[0].(*services.DBConfig)
+	helper.AssertEqual("dev", dbConfig.Tags["env"])
+}
+
+func TestClientTimeouts(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	provider := &blockingProvider{MockProvider: mock.New("us-east-1")}
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		DefaultTimeout: time.Hour,
+		Timeouts:       map[string]time.Duration{cloudsdk.OpGetVM: 10 * time.Millisecond},
+	})
+
+	start := time.Now()
+	_, err := client.Compute().GetVM(context.Background(), "i-123")
+	helper.AssertErrorCode(err, cloudsdk.ErrNetworkTimeout)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected timeout error to wrap context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected per-operation timeout to apply, call took %v", elapsed)
+	}
+
+	// A caller's own cancellation is passed through unchanged
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Compute().GetVM(ctx, "i-123")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientDryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+
+	_, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-server"))
+	helper.AssertErrorCode(err, cloudsdk.ErrDryRun)
+	helper.AssertEqual(false, provider.WasCalled("CreateVM"))
+
+	err = client.Storage().DeleteBucket(ctx, "some-bucket")
+	helper.AssertErrorCode(err, cloudsdk.ErrDryRun)
+
+	_, err = client.Compute().PlacementGroups().Create(ctx, &services.PlacementGroupConfig{GroupName: "pg", Strategy: "cluster"})
+	helper.AssertErrorCode(err, cloudsdk.ErrDryRun)
+	helper.AssertEqual(false, provider.WasCalled("CreatePlacementGroup"))
+
+	// Read-only operations still reach the provider
+	_, err = client.Compute().ListVMs(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, provider.WasCalled("ListVMs"))
+
+	// Skipped operations are recorded in the plan
+	plan := client.Plan()
+	helper.AssertEqual(3, len(plan))
+	helper.AssertEqual(cloudsdk.OpCreateVM, plan[0].Operation)
+	helper.AssertEqual("mock", plan[0].Provider)
+	helper.AssertEqual("some-bucket", plan[1].Input)
+	helper.AssertEqual(cloudsdk.ServiceCompute, plan[2].Service)
+
+	// Invalid configs fail validation instead of being planned
+	_, err = client.Database().CreateDB(ctx, &services.DBConfig{Name: "app-db"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	helper.AssertEqual(3, len(client.Plan()))
+
+	client.ResetPlan()
+	helper.AssertEqual(0, len(client.Plan()))
+}
+
+func TestContextWithPlan(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, nil)
+
+	ctx, plan := cloudsdk.ContextWithPlan(context.Background())
+	err := client.Storage().CreateBucket(ctx, cloudsdktesting.GenerateBucketConfig("my-bucket-2024"))
+	helper.AssertErrorCode(err, cloudsdk.ErrDryRun)
+	helper.AssertEqual(false, provider.WasCalled("CreateBucket"))
+	helper.AssertEqual(1, len(plan.Calls()))
+	helper.AssertEqual(cloudsdk.OpCreateBucket, plan.Calls()[0].Operation)
+
+	// Only the context's operations run as dry runs
+	helper.AssertEqual(0, len(client.Plan()))
+	helper.AssertNoError(client.Storage().CreateBucket(context.Background(), cloudsdktesting.GenerateBucketConfig("my-bucket-2024")))
+	helper.AssertEqual(true, provider.WasCalled("CreateBucket"))
+}
+
+func TestClientLogger(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	provider := mock.New("us-east-1").WithError("GetDB", cloudsdk.NewResourceNotFoundError("mock", "database", "db", "missing"))
+	client := cloudsdk.New(provider, &cloudsdk.Config{Logger: logger})
+
+	_, err := client.Database().ListDBs(ctx)
+	helper.AssertNoError(err)
+	_, err = client.Database().GetDB(ctx, "missing")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	output := buf.String()
+	helper.AssertContains(output, "operation=ListDBs")
+	helper.AssertContains(output, "operation=GetDB")
+	helper.AssertContains(output, "error_code=RESOURCE_NOT_FOUND")
+	if strings.Count(output, "\n") != 2 {
+		t.Errorf("Expected one log record per operation, got:\n%s", output)
+	}
+}
+
+func TestClientMiddleware(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, nil)
+
+	var seen []string
+	record := func(name string) cloudsdk.Middleware {
+		return func(next cloudsdk.Handler) cloudsdk.Handler {
+			return func(ctx context.Context, call *cloudsdk.Call) (interface{}, error) {
+				seen = append(seen, name+":"+call.Operation)
+				return next(ctx, call)
+			}
+		}
+	}
+	tagger := func(next cloudsdk.Handler) cloudsdk.Handler {
+		return func(ctx context.Context, call *cloudsdk.Call) (interface{}, error) {
+			if config, ok := call.Args[0].(*services.VMConfig); ok {
+				if config.Tags == nil {
+					config.Tags = make(map[string]string)
+				}
+				config.Tags["owner"] = "middleware"
+			}
+			result, err := next(ctx, call)
+			if vm, ok := result.(*services.VM); ok && err == nil {
+				seen = append(seen, "result:"+vm.Name)
+			}
+			return result, err
+		}
+	}
+	client.Use(record("outer"), record("inner"))
+	client.Use(tagger)
+
+	vmConfig := cloudsdktesting.GenerateVMConfig("web-server")
+	vmConfig.Tags = nil
+	_, err := client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+	sent := provider.LastCallArgs("CreateVM")[0].(*services.VMConfig)
+	helper.AssertEqual("middleware", sent.Tags["owner"])
+	helper.AssertEqual(0, len(vmConfig.Tags))
+
+	_, err = client.Compute().SpotInstances().Describe(ctx, []string{"sir-1"})
+	helper.AssertNoError(err)
+
+	expected := []string{
+		"outer:CreateVM", "inner:CreateVM", "result:web-server",
+		"outer:DescribeSpotInstanceRequests", "inner:DescribeSpotInstanceRequests",
+	}
+	helper.AssertEqual(strings.Join(expected, ","), strings.Join(seen, ","))
+}
+
+func TestClientMiddlewareShortCircuit(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, nil)
+
+	client.Use(func(next cloudsdk.Handler) cloudsdk.Handler {
+		return func(ctx context.Context, call *cloudsdk.Call) (interface{}, error) {
+			if call.Service == cloudsdk.ServiceStorage && cloudsdk.IsMutatingOperation(call.Operation) {
+				return nil, cloudsdk.NewAuthorizationError(call.Provider, string(call.Service), call.Operation, nil)
+			}
+			return next(ctx, call)
+		}
+	})
+
+	err := client.Storage().PutObject(ctx, "bucket", "key", strings.NewReader("data"))
+	helper.AssertErrorCode(err, cloudsdk.ErrAuthorization)
+	helper.AssertEqual(false, provider.WasCalled("PutObject"))
+
+	_, err = client.Storage().ListBuckets(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, provider.WasCalled("ListBuckets"))
+}
+
+// recordingTracer records the spans started through it.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name   string
+	parent *recordingSpan
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...cloudsdk.Attribute) (context.Context, cloudsdk.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(spanKey{}).(*recordingSpan)
+	span := &recordingSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+	span.SetAttributes(attrs...)
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...cloudsdk.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) { s.err = err }
+func (s *recordingSpan) End()                  { s.ended = true }
+
+// retryingCompute reports one retry and one traced attempt per GetVM call,
+// the way provider retry loops do.
+type retryingCompute struct {
+	services.Compute
+}
+
+func (c *retryingCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
+	for attempt := 1; attempt <= 2; attempt++ {
+		if attempt > 1 {
+			cloudsdk.RecordRetry(ctx)
+		}
+		_, span := cloudsdk.StartSpan(ctx, "mock.compute.DescribeInstances",
+			cloudsdk.IntAttr(cloudsdk.AttrAttempt, attempt))
+		span.End()
+	}
+	return c.Compute.GetVM(ctx, id)
+}
+
+type retryingProvider struct {
+	*mock.MockProvider
+}
+
+func (p *retryingProvider) Compute() services.Compute {
+	return &retryingCompute{Compute: p.MockProvider.Compute()}
+}
+
+func TestClientTracing(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	provider := &retryingProvider{MockProvider: mock.New("us-east-1")}
+	client := cloudsdk.New(provider, &cloudsdk.Config{Tracer: tracer})
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("traced-vm"))
+	helper.AssertNoError(err)
+	_, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	_, err = client.Compute().GetVM(ctx, "i-missing")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	// One span per operation, plus two attempt spans per GetVM
+	helper.AssertEqual(7, len(tracer.spans))
+
+	create := tracer.spans[0]
+	helper.AssertEqual("cloudsdk.compute.CreateVM", create.name)
+	helper.AssertEqual("mock", create.attrs[cloudsdk.AttrProvider])
+	helper.AssertEqual("compute", create.attrs[cloudsdk.AttrService])
+	helper.AssertEqual(vm.ID, create.attrs[cloudsdk.AttrResourceID])
+	helper.AssertEqual(0, create.attrs[cloudsdk.AttrRetryCount])
+	helper.AssertEqual(true, create.ended)
+
+	get := tracer.spans[1]
+	helper.AssertEqual("cloudsdk.compute.GetVM", get.name)
+	helper.AssertEqual(1, get.attrs[cloudsdk.AttrRetryCount])
+	attempt := tracer.spans[3]
+	helper.AssertEqual(get, attempt.parent)
+	helper.AssertEqual(2, attempt.attrs[cloudsdk.AttrAttempt])
+
+	failed := tracer.spans[4]
+	helper.AssertEqual("i-missing", failed.attrs[cloudsdk.AttrResourceID])
+	helper.AssertEqual(string(cloudsdk.ErrResourceNotFound), failed.attrs[cloudsdk.AttrErrorCode])
+	if failed.err == nil {
+		t.Error("Expected failed operation span to record the error")
+	}
+}
+
+func TestClientTracingResourceIDs(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	client := cloudsdk.New(mock.New("us-east-1"), &cloudsdk.Config{Tracer: tracer})
+	compute := client.Compute()
+
+	vm, err := compute.CreateVM(ctx, cloudsdktesting.GenerateVMConfig("traced-vm"))
+	helper.AssertNoError(err)
+	volume, err := compute.Volumes().Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SizeGiB: 8})
+	helper.AssertNoError(err)
+	snapshot, err := compute.Volumes().Snapshot(ctx, &services.VolumeSnapshotConfig{VolumeID: volume.ID})
+	helper.AssertNoError(err)
+	image, err := compute.Images().Create(ctx, &services.CreateImageConfig{VMID: vm.ID, Name: "traced-image"})
+	helper.AssertNoError(err)
+	keyPair, err := compute.KeyPairs().Create(ctx, &services.KeyPairConfig{Name: "traced-key"})
+	helper.AssertNoError(err)
+	group, err := compute.SecurityGroups().Create(ctx, &services.SecurityGroupConfig{Name: "traced-group"})
+	helper.AssertNoError(err)
+	_, err = compute.KeyPairs().Create(ctx, &services.KeyPairConfig{Name: "traced-key"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+
+	// Operations on a resource take its ID first; later arguments aren't
+	// part of it
+	helper.AssertNoError(compute.StopVM(ctx, vm.ID))
+	helper.AssertNoError(compute.ResizeVM(ctx, vm.ID, "t3.medium"))
+	helper.AssertNoError(compute.Volumes().Attach(ctx, volume.ID, vm.ID, "/dev/sdf"))
+	helper.AssertNoError(compute.Volumes().Resize(ctx, volume.ID, 16))
+	helper.AssertNoError(compute.SecurityGroups().AddRules(ctx, group.ID, []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 443, ToPort: 443, CIDR: "0.0.0.0/0"},
+	}))
+
+	want := []string{
+		vm.ID, volume.ID, snapshot.ID, image.ID, keyPair.Name, group.ID, "traced-key",
+		vm.ID, vm.ID, volume.ID, volume.ID, group.ID,
+	}
+	checkSpans(t, tracer, want)
+
+	// Failed creates are named by their config
+	quotaErr := func(operation string) error {
+		return cloudsdk.NewQuotaExceededError("mock", "compute", operation, "limit")
+	}
+	provider := mock.New("us-east-1").
+		WithError("CreateVM", quotaErr("CreateVM")).
+		WithError("CreateVolume", quotaErr("CreateVolume")).
+		WithError("CreateSecurityGroup", quotaErr("CreateSecurityGroup")).
+		WithError("CreateImage", quotaErr("CreateImage"))
+	tracer = &recordingTracer{}
+	compute = cloudsdk.New(provider, &cloudsdk.Config{Tracer: tracer}).Compute()
+
+	_, err = compute.CreateVM(ctx, cloudsdktesting.GenerateVMConfig("failed-vm"))
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+	_, err = compute.Volumes().Create(ctx, &services.VolumeConfig{Name: "failed-volume", AvailabilityZone: "us-east-1a", SizeGiB: 8})
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+	_, err = compute.SecurityGroups().Create(ctx, &services.SecurityGroupConfig{Name: "failed-group"})
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+	_, err = compute.Images().Create(ctx, &services.CreateImageConfig{VMID: vm.ID, Name: "failed-image"})
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+
+	checkSpans(t, tracer, []string{"failed-vm", "failed-volume", "failed-group", "failed-image"})
+}
+
+// checkSpans checks that the spans recorded by tracer name the resources in
+// want, in order.
+func checkSpans(t *testing.T, tracer *recordingTracer, want []string) {
+	t.Helper()
+	if len(tracer.spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(tracer.spans))
+	}
+	for i, span := range tracer.spans {
+		if got := span.attrs[cloudsdk.AttrResourceID]; got != want[i] {
+			t.Errorf("span %s: expected resource ID %q, got %v", span.name, want[i], got)
+		}
+	}
+}
+
+func TestClientPrometheusMetrics(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	metrics := cloudsdk.NewPrometheusMetrics(0.5, 1)
+	provider := &retryingProvider{MockProvider: mock.New("us-east-1")}
+	client := cloudsdk.New(provider, &cloudsdk.Config{Metrics: metrics})
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("metered-vm"))
+	helper.AssertNoError(err)
+	_, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	_, err = client.Compute().GetVM(ctx, "i-missing")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	output := recorder.Body.String()
+
+	helper.AssertContains(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	helper.AssertContains(output, "# TYPE cloudsdk_operations_total counter\n")
+	helper.AssertContains(output, `cloudsdk_operations_total{provider="mock",service="compute",operation="GetVM"} 2`)
+	helper.AssertContains(output, `cloudsdk_operations_total{provider="mock",service="compute",operation="CreateVM"} 1`)
+	helper.AssertContains(output, `cloudsdk_operation_errors_total{provider="mock",service="compute",operation="GetVM",code="RESOURCE_NOT_FOUND"} 1`)
+	helper.AssertContains(output, `cloudsdk_operation_retries_total{provider="mock",service="compute",operation="GetVM"} 2`)
+	helper.AssertContains(output, `cloudsdk_operation_duration_seconds_bucket{provider="mock",service="compute",operation="GetVM",le="0.5"} 2`)
+	helper.AssertContains(output, `cloudsdk_operation_duration_seconds_bucket{provider="mock",service="compute",operation="GetVM",le="+Inf"} 2`)
+	helper.AssertContains(output, `cloudsdk_operation_duration_seconds_count{provider="mock",service="compute",operation="GetVM"} 2`)
+	if strings.Contains(output, `operation="CreateVM",code=`) {
+		t.Errorf("Expected no error series for a successful operation, got:\n%s", output)
+	}
+}
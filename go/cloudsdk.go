@@ -1,16 +1,77 @@
 package cloudsdk
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 )
 
-// Config holds the configuration for the Cloud SDK
+// Config holds the configuration for the Cloud SDK.
+// The Client enforces it by wrapping every service it returns, so the same
+// defaults apply regardless of which provider is in use.
 type Config struct {
 	Region string
-	// Add other global configs like credentials, etc.
+
+	// DefaultTags are merged into the tags of every VM, bucket and database
+	// created through the Client. Tags set on the resource config, or passed
+	// with services.WithTags for a single call, take precedence.
+	// They are left out for resources the provider can't tag, as reported by
+	// its Capabilities.
+	DefaultTags map[string]string
+
+	// DefaultTimeout bounds every operation that has no entry in Timeouts.
+	// Zero means operations are only bounded by the caller's context.
+	DefaultTimeout time.Duration
+
+	// Timeouts sets per-operation timeouts keyed by operation name,
+	// e.g. {cloudsdk.OpCreateDB: 20 * time.Minute}. A services.WithTimeout
+	// call option takes precedence over both timeouts.
+	Timeouts map[string]time.Duration
+
+	// Logger receives a structured record for every operation.
+	// If nil, the Client doesn't log.
+	Logger *slog.Logger
+
+	// Tracer, if set, receives a span for every operation made through the
+	// Client, and is passed to providers so they can trace each API attempt.
+	Tracer Tracer
+
+	// Metrics, if set, receives an observation for every operation made
+	// through the Client. See NewPrometheusMetrics for a built-in exporter.
+	Metrics Metrics
+
+	// RetryPolicy, if set, replaces the provider's retry policy for every
+	// operation that has no entry in RetryPolicies.
+	RetryPolicy *RetryPolicy
+
+	// RetryPolicies sets per-operation retry policies keyed by operation name,
+	// e.g. {cloudsdk.OpCreateVM: cloudsdk.NoRetry}.
+	RetryPolicies map[string]RetryPolicy
+
+	// RetryBudget limits retries across every operation of the Client.
+	// If nil, each Client gets its own budget of DefaultRetryBudgetCapacity.
+	RetryBudget *RetryBudget
+
+	// CircuitBreaker, if set, gives each service of the Client a circuit
+	// breaker that fails calls fast with ErrCircuitOpen while the provider
+	// keeps failing.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// RateLimits, if set, makes the Client wait before calling the provider
+	// so it stays within the configured per-service and per-operation rates.
+	RateLimits *RateLimitConfig
+
+	// DryRun stops mutating operations (create, start, stop, delete, put)
+	// from changing anything. They validate their input, check permissions
+	// where the provider can, record the API calls they would have made in
+	// Client.Plan, and return a CloudError with code ErrDryRun.
+	// Read-only operations run normally.
+	DryRun bool
 }
 
 // ServiceType represents the type of cloud service
@@ -38,6 +99,12 @@ const (
 	ErrResourceNotFound ErrorCode = "RESOURCE_NOT_FOUND"
 	ErrResourceConflict ErrorCode = "RESOURCE_CONFLICT"
 
+	// Resource state and limits
+	ErrQuotaExceeded       ErrorCode = "QUOTA_EXCEEDED"
+	ErrInvalidState        ErrorCode = "INVALID_STATE"
+	ErrPreconditionFailed  ErrorCode = "PRECONDITION_FAILED"
+	ErrDependencyViolation ErrorCode = "DEPENDENCY_VIOLATION"
+
 	// Network and rate limiting
 	ErrRateLimit      ErrorCode = "RATE_LIMIT_EXCEEDED"
 	ErrNetworkTimeout ErrorCode = "NETWORK_TIMEOUT"
@@ -45,14 +112,75 @@ const (
 	// Configuration errors
 	ErrInvalidConfig ErrorCode = "INVALID_CONFIGURATION"
 	ErrProviderError ErrorCode = "PROVIDER_ERROR"
+
+	// Client behavior
+	ErrDryRun      ErrorCode = "DRY_RUN"
+	ErrCircuitOpen ErrorCode = "CIRCUIT_OPEN"
 )
 
+// Error implements the error interface, so codes can be matched directly
+// with errors.Is:
+//
+//	if errors.Is(err, cloudsdk.ErrQuotaExceeded) {
+//	    // ask for a quota increase
+//	}
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// HTTPStatus returns the HTTP status code that best describes errors with
+// this code, for services that pass SDK errors on to their own clients.
+// Unknown codes map to 500 Internal Server Error.
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case ErrInvalidConfig:
+		return http.StatusBadRequest
+	case ErrAuthentication:
+		return http.StatusUnauthorized
+	case ErrAuthorization:
+		return http.StatusForbidden
+	case ErrResourceNotFound:
+		return http.StatusNotFound
+	case ErrResourceConflict, ErrInvalidState, ErrDependencyViolation:
+		return http.StatusConflict
+	case ErrPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case ErrDryRun:
+		// As EC2 does for DryRunOperation
+		return http.StatusPreconditionFailed
+	case ErrRateLimit, ErrQuotaExceeded:
+		return http.StatusTooManyRequests
+	case ErrServiceNotSupported, ErrOperationNotSupported:
+		return http.StatusNotImplemented
+	case ErrProviderError:
+		return http.StatusBadGateway
+	case ErrCircuitOpen:
+		return http.StatusServiceUnavailable
+	case ErrNetworkTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // ErrorContext provides debugging information for troubleshooting
 type ErrorContext struct {
 	RequestID string            `json:"request_id,omitempty"`
 	Timestamp time.Time         `json:"timestamp"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
 	Retryable bool              `json:"retryable"`
+
+	// ProviderCode is the provider's own error code, such as
+	// "InvalidInstanceID.NotFound", when the provider returned one.
+	ProviderCode string `json:"provider_code,omitempty"`
+
+	// StatusCode is the HTTP status of the provider's response, or zero if
+	// the request got no response.
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Attempts is how many times the provider call was attempted, including
+	// retries, or zero if unknown.
+	Attempts int `json:"attempts,omitempty"`
 }
 
 // CloudError provides structured error information with helpful context and suggestions.
@@ -66,12 +194,23 @@ type CloudError struct {
 	Suggestions []string     `json:"suggestions,omitempty"`
 	Cause       error        `json:"-"`
 	Context     ErrorContext `json:"context,omitempty"`
+
+	// Field is the path to the invalid field of a configuration, such as
+	// "Name" or "ReplicationConfig.Rules[0].ID". Set for ErrInvalidConfig errors.
+	Field string `json:"field,omitempty"`
+
+	// RetryAfter is how long the provider asked callers to wait before
+	// retrying, or zero if it gave no hint. Set for ErrRateLimit errors.
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
 }
 
 // Error implements the error interface with rich context
 func (e *CloudError) Error() string {
 	msg := fmt.Sprintf("[%s] %s", e.Code, e.Message)
-	if e.Provider != "" {
+	switch {
+	case e.Provider != "" && e.Context.RequestID != "":
+		msg += fmt.Sprintf(" (provider: %s, request ID: %s)", e.Provider, e.Context.RequestID)
+	case e.Provider != "":
 		msg += fmt.Sprintf(" (provider: %s)", e.Provider)
 	}
 	if len(e.Suggestions) > 0 {
@@ -88,6 +227,54 @@ func (e *CloudError) Unwrap() error {
 	return e.Cause
 }
 
+// NotFound reports whether the error has code ErrResourceNotFound. It lets
+// code that can't import cloudsdk, such as the services waiters, recognize
+// missing resources; see services.IsNotFound.
+func (e *CloudError) NotFound() bool {
+	return e.Code == ErrResourceNotFound
+}
+
+// Temporary reports whether the error is transient, such as throttling or a
+// timeout, so that the failed call may succeed later.
+func (e *CloudError) Temporary() bool {
+	return e.Context.Retryable || isRetryableError(e.Code)
+}
+
+// HTTPStatus returns the HTTP status code for the error's code. See
+// ErrorCode.HTTPStatus.
+func (e *CloudError) HTTPStatus() int {
+	return e.Code.HTTPStatus()
+}
+
+// MarshalJSON encodes the error with its json struct tags plus
+// "http_status", the value of HTTPStatus. Field names are stable, so the
+// output can be returned to API clients as is. Cause is left out because it
+// may hold provider internals; its details are in ProviderCode and Message.
+func (e *CloudError) MarshalJSON() ([]byte, error) {
+	type cloudError CloudError
+	return json.Marshal(struct {
+		*cloudError
+		HTTPStatus int `json:"http_status"`
+	}{(*cloudError)(e), e.HTTPStatus()})
+}
+
+// Is reports whether target is the error's ErrorCode, or a *CloudError with
+// the same code. This lets callers match SDK errors by category using errors.Is:
+//
+//	if errors.Is(err, cloudsdk.ErrServiceNotSupported) {
+//	    // fall back to another provider
+//	}
+func (e *CloudError) Is(target error) bool {
+	if code, ok := target.(ErrorCode); ok {
+		return e.Code == code
+	}
+	t, ok := target.(*CloudError)
+	if !ok || t == nil {
+		return false
+	}
+	return e.Code == t.Code
+}
+
 // ServiceNotSupportedError is returned when a provider doesn't support a requested service.
 // This helps developers understand provider limitations at development time.
 type ServiceNotSupportedError struct {
@@ -102,6 +289,38 @@ func (e *ServiceNotSupportedError) Error() string {
 		e.Provider, e.Service)
 }
 
+// CloudError converts the error into a *CloudError with code ErrServiceNotSupported.
+// The returned error keeps the original ServiceNotSupportedError as its cause.
+func (e *ServiceNotSupportedError) CloudError() *CloudError {
+	message := fmt.Sprintf("Provider '%s' does not support '%s' service", e.Provider, e.Service)
+	return NewCloudError(ErrServiceNotSupported, message, e.Provider, string(e.Service), "").
+		WithCause(e).
+		WithSuggestions(
+			"Check provider documentation for supported services",
+			"Use Client.Supports() to check service availability before use",
+			"Switch to a provider that supports this service",
+		)
+}
+
+// As allows errors.As to extract a *CloudError from a ServiceNotSupportedError,
+// so it can be handled like any other SDK error.
+func (e *ServiceNotSupportedError) As(target interface{}) bool {
+	if t, ok := target.(**CloudError); ok {
+		*t = e.CloudError()
+		return true
+	}
+	return false
+}
+
+// Is reports whether target is ErrServiceNotSupported or a *CloudError with that code.
+func (e *ServiceNotSupportedError) Is(target error) bool {
+	if code, ok := target.(ErrorCode); ok {
+		return code == ErrServiceNotSupported
+	}
+	t, ok := target.(*CloudError)
+	return ok && t != nil && t.Code == ErrServiceNotSupported
+}
+
 // NewServiceNotSupportedError creates a new service not supported error with suggestions
 func NewServiceNotSupportedError(provider string, service ServiceType) *ServiceNotSupportedError {
 	return &ServiceNotSupportedError{
@@ -198,7 +417,9 @@ func NewResourceNotFoundError(provider string, service string, resourceType stri
 // NewInvalidConfigError creates a new invalid configuration error
 func NewInvalidConfigError(provider string, service string, field string, reason string) *CloudError {
 	message := fmt.Sprintf("Invalid configuration for field '%s': %s", field, reason)
-	return NewCloudError(ErrInvalidConfig, message, provider, service, "validate").
+	err := NewCloudError(ErrInvalidConfig, message, provider, service, "validate")
+	err.Field = field
+	return err.
 		WithSuggestions(
 			"Check the field value meets the required format",
 			"Refer to the provider documentation for valid values",
@@ -206,6 +427,90 @@ func NewInvalidConfigError(provider string, service string, field string, reason
 		)
 }
 
+// NewQuotaExceededError creates an error for an operation that would take an
+// account over one of its provider quotas, such as running instances or vCPUs.
+// Unlike ErrRateLimit, retrying does not help until usage drops or the quota
+// is raised.
+func NewQuotaExceededError(provider string, service string, operation string, quota string) *CloudError {
+	message := fmt.Sprintf("Quota exceeded: %s", quota)
+	return NewCloudError(ErrQuotaExceeded, message, provider, service, operation).
+		WithSuggestions(
+			"Delete unused resources to free up quota",
+			"Request a quota increase from your provider",
+			"Check current usage against your account's quotas",
+		)
+}
+
+// NewInvalidStateError creates an error for an operation that the resource's
+// current state doesn't allow, such as stopping an instance that is already
+// stopped. resourceID and state may be empty if the provider didn't report them.
+func NewInvalidStateError(provider string, service string, operation string, resourceID string, state string) *CloudError {
+	subject := "Resource"
+	if resourceID != "" {
+		subject = fmt.Sprintf("'%s'", resourceID)
+	}
+	message := fmt.Sprintf("%s is not in a state that allows operation '%s'", subject, operation)
+	if state != "" {
+		message = fmt.Sprintf("%s is %s, which does not allow operation '%s'", subject, state, operation)
+	}
+	return NewCloudError(ErrInvalidState, message, provider, service, operation).
+		WithSuggestions(
+			"Check the resource's current state before retrying",
+			"Wait for pending state changes to finish",
+		)
+}
+
+// NewPreconditionFailedError creates an error for an operation blocked by a
+// setting or condition on the resource, such as deletion protection.
+func NewPreconditionFailedError(provider string, service string, operation string, reason string) *CloudError {
+	message := fmt.Sprintf("Precondition failed for operation '%s': %s", operation, reason)
+	return NewCloudError(ErrPreconditionFailed, message, provider, service, operation).
+		WithSuggestions(
+			"Change the setting that blocks the operation, such as deletion protection",
+			"Check any conditions sent with the request",
+		)
+}
+
+// NewDependencyViolationError creates an error for an operation on a resource
+// that other resources still depend on or contain, such as deleting a bucket
+// that isn't empty.
+func NewDependencyViolationError(provider string, service string, operation string, reason string) *CloudError {
+	message := fmt.Sprintf("Dependency violation for operation '%s': %s", operation, reason)
+	return NewCloudError(ErrDependencyViolation, message, provider, service, operation).
+		WithSuggestions(
+			"Delete or detach the dependent resources first",
+			"Check which resources still reference this one",
+		)
+}
+
+// NewDryRunError creates the error returned for mutating operations when the
+// Client is in dry-run mode. The operation was not sent to the provider.
+func NewDryRunError(provider string, service string, operation string) *CloudError {
+	message := fmt.Sprintf("Dry run: operation '%s' was not sent to the provider", operation)
+	return NewCloudError(ErrDryRun, message, provider, service, operation).
+		WithSuggestions(
+			"Disable Config.DryRun to apply changes",
+			"Use errors.Is(err, &cloudsdk.CloudError{Code: cloudsdk.ErrDryRun}) to detect skipped operations",
+		)
+}
+
+// NewCircuitOpenError creates the error returned while a service's circuit
+// breaker is open. retryAfter is how long until the breaker lets a trial call
+// through, or zero if unknown.
+func NewCircuitOpenError(provider string, service string, operation string, retryAfter time.Duration) *CloudError {
+	message := fmt.Sprintf("Circuit breaker for %s %s is open: operation '%s' was not sent to the provider", provider, service, operation)
+	suggestions := []string{
+		"The provider has been failing repeatedly; check its service status",
+	}
+	if retryAfter > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("Retry after %v", retryAfter.Round(time.Millisecond)))
+	}
+	err := NewCloudError(ErrCircuitOpen, message, provider, service, operation).
+		WithSuggestions(suggestions...)
+	err.RetryAfter = retryAfter
+	return err
+}
+
 // NewRateLimitError creates a new rate limit error with retry suggestions
 func NewRateLimitError(provider string, service string, operation string, retryAfter time.Duration) *CloudError {
 	message := "Rate limit exceeded"
@@ -217,8 +522,10 @@ func NewRateLimitError(provider string, service string, operation string, retryA
 		suggestions = append(suggestions, fmt.Sprintf("Retry after %v", retryAfter))
 	}
 
-	return NewCloudError(ErrRateLimit, message, provider, service, operation).
+	err := NewCloudError(ErrRateLimit, message, provider, service, operation).
 		WithSuggestions(suggestions...)
+	err.RetryAfter = retryAfter
+	return err
 }
 
 // Provider defines the interface for cloud providers.
@@ -245,32 +552,76 @@ type Provider interface {
 	// SupportedServices returns a list of services supported by this provider.
 	// This allows compile-time checking of service availability.
 	SupportedServices() []ServiceType
+
+	// Capabilities reports which operations of the supported services the
+	// provider implements, and which configuration fields it can't apply.
+	// The Client rejects calls that need a missing capability with
+	// ErrOperationNotSupported.
+	Capabilities() Capabilities
 }
 
 // Client provides a unified interface to cloud providers.
 // It automatically validates service availability at runtime.
 type Client struct {
 	provider Provider
+	config   Config
+
+	mu         sync.RWMutex
+	middleware []Middleware
+
+	retryBudget *RetryBudget
+	breakers    map[ServiceType]*circuitBreaker
+	rateLimiter *rateLimiter
+	plan        *Plan
 }
 
 // New creates a new cloud SDK client with the specified provider.
 // The client will validate service availability when methods are called.
+// A nil config uses the defaults: no default tags, no timeouts, no logging.
 //
 // Example:
 //
-//	provider := aws.New("us-east-1")
-//	client := cloudsdk.New(provider)
+//	provider, _ := aws.New("us-east-1")
+//	client := cloudsdk.New(provider, &cloudsdk.Config{
+//	    DefaultTags:    map[string]string{"team": "platform"},
+//	    DefaultTimeout: 2 * time.Minute,
+//	    Timeouts:       map[string]time.Duration{cloudsdk.OpCreateDB: 30 * time.Minute},
+//	    Logger:         slog.Default(),
+//	})
 func New(provider Provider, config *Config) *Client {
-	return &Client{provider: provider}
+	client := &Client{provider: provider, plan: &Plan{}}
+	if config != nil {
+		client.config = *config
+		client.config.DefaultTags = copyTags(config.DefaultTags)
+		if config.Timeouts != nil {
+			client.config.Timeouts = make(map[string]time.Duration, len(config.Timeouts))
+			for operation, timeout := range config.Timeouts {
+				client.config.Timeouts[operation] = timeout
+			}
+		}
+		if config.RetryPolicies != nil {
+			client.config.RetryPolicies = make(map[string]RetryPolicy, len(config.RetryPolicies))
+			for operation, policy := range config.RetryPolicies {
+				client.config.RetryPolicies[operation] = policy
+			}
+		}
+	}
+	client.rateLimiter = newRateLimiter(client.config.RateLimits)
+	client.retryBudget = client.config.RetryBudget
+	if client.retryBudget == nil {
+		client.retryBudget = NewRetryBudget(DefaultRetryBudgetCapacity)
+	}
+	return client
 }
 
 // NewFromProvider creates a new Cloud SDK client from a provider (auto-configures from provider)
 func NewFromProvider(provider Provider) *Client {
-	return &Client{provider: provider}
+	return New(provider, nil)
 }
 
 // Compute returns the compute service if supported by the provider.
 // Panics with ErrServiceNotSupported if compute is not available.
+// Use TryCompute for a non-panicking variant.
 //
 // Example:
 //
@@ -283,11 +634,12 @@ func (c *Client) Compute() services.Compute {
 	if !c.supportsService(ServiceCompute) {
 		panic(NewServiceNotSupportedError(c.provider.Name(), ServiceCompute))
 	}
-	return c.provider.Compute()
+	return c.wrapCompute(c.provider.Compute())
 }
 
 // Storage returns the storage service if supported by the provider.
 // Panics with ErrServiceNotSupported if storage is not available.
+// Use TryStorage for a non-panicking variant.
 //
 // Example:
 //
@@ -299,11 +651,12 @@ func (c *Client) Storage() services.Storage {
 	if !c.supportsService(ServiceStorage) {
 		panic(NewServiceNotSupportedError(c.provider.Name(), ServiceStorage))
 	}
-	return c.provider.Storage()
+	return c.wrapStorage(c.provider.Storage())
 }
 
 // Database returns the database service if supported by the provider.
 // Panics with ErrServiceNotSupported if database is not available.
+// Use TryDatabase for a non-panicking variant.
 //
 // Example:
 //
@@ -316,7 +669,56 @@ func (c *Client) Database() services.Database {
 	if !c.supportsService(ServiceDatabase) {
 		panic(NewServiceNotSupportedError(c.provider.Name(), ServiceDatabase))
 	}
-	return c.provider.Database()
+	return c.wrapDatabase(c.provider.Database())
+}
+
+// Supports reports whether the provider backing this client supports the given service.
+// Use it to check availability up front instead of relying on the panicking accessors.
+//
+// Example:
+//
+//	if client.Supports(cloudsdk.ServiceDatabase) {
+//	    dbs, err := client.Database().ListDBs(ctx)
+//	}
+func (c *Client) Supports(service ServiceType) bool {
+	return c.supportsService(service)
+}
+
+// TryCompute returns the compute service, or a *CloudError with code
+// ErrServiceNotSupported if the provider doesn't support compute.
+// Unlike Compute, it never panics, which makes it safe for long-running workers
+// that receive their provider from configuration.
+//
+// Example:
+//
+//	compute, err := client.TryCompute()
+//	if err != nil {
+//	    return err
+//	}
+//	vms, err := compute.ListVMs(ctx)
+func (c *Client) TryCompute() (services.Compute, error) {
+	if !c.supportsService(ServiceCompute) {
+		return nil, NewServiceNotSupportedError(c.provider.Name(), ServiceCompute).CloudError()
+	}
+	return c.wrapCompute(c.provider.Compute()), nil
+}
+
+// TryStorage returns the storage service, or a *CloudError with code
+// ErrServiceNotSupported if the provider doesn't support storage.
+func (c *Client) TryStorage() (services.Storage, error) {
+	if !c.supportsService(ServiceStorage) {
+		return nil, NewServiceNotSupportedError(c.provider.Name(), ServiceStorage).CloudError()
+	}
+	return c.wrapStorage(c.provider.Storage()), nil
+}
+
+// TryDatabase returns the database service, or a *CloudError with code
+// ErrServiceNotSupported if the provider doesn't support databases.
+func (c *Client) TryDatabase() (services.Database, error) {
+	if !c.supportsService(ServiceDatabase) {
+		return nil, NewServiceNotSupportedError(c.provider.Name(), ServiceDatabase).CloudError()
+	}
+	return c.wrapDatabase(c.provider.Database()), nil
 }
 
 // supportsService checks if the provider supports the given service type
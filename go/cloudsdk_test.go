@@ -0,0 +1,163 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestClientSupports(t *testing.T) {
+	provider := mock.New("us-east-1").WithSupportedServices(cloudsdk.ServiceCompute)
+	client := cloudsdk.NewFromProvider(provider)
+
+	if !client.Supports(cloudsdk.ServiceCompute) {
+		t.Error("Expected compute to be supported")
+	}
+	if client.Supports(cloudsdk.ServiceStorage) {
+		t.Error("Expected storage to be unsupported")
+	}
+	if client.Supports(cloudsdk.ServiceDatabase) {
+		t.Error("Expected database to be unsupported")
+	}
+}
+
+func TestClientTryAccessors(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	provider := mock.New("us-east-1").WithSupportedServices(cloudsdk.ServiceCompute)
+	client := cloudsdk.NewFromProvider(provider)
+
+	compute, err := client.TryCompute()
+	helper.AssertNoError(err)
+	if compute == nil {
+		t.Fatal("Expected compute service, got nil")
+	}
+
+	storage, err := client.TryStorage()
+	helper.AssertErrorCode(err, cloudsdk.ErrServiceNotSupported)
+	if storage != nil {
+		t.Error("Expected nil storage service for unsupported service")
+	}
+
+	_, err = client.TryDatabase()
+	helper.AssertErrorCode(err, cloudsdk.ErrServiceNotSupported)
+
+	var notSupported *cloudsdk.ServiceNotSupportedError
+	if !errors.As(err, &notSupported) {
+		t.Fatalf("Expected error to unwrap to ServiceNotSupportedError, got %T", err)
+	}
+	helper.AssertEqual(cloudsdk.ServiceDatabase, notSupported.Service)
+	helper.AssertEqual("mock", notSupported.Provider)
+}
+
+func TestServiceNotSupportedErrorInterop(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	var err error = cloudsdk.NewServiceNotSupportedError("mock", cloudsdk.ServiceStorage)
+
+	var cloudErr *cloudsdk.CloudError
+	if !errors.As(err, &cloudErr) {
+		t.Fatal("Expected errors.As to extract a CloudError")
+	}
+	helper.AssertEqual(cloudsdk.ErrServiceNotSupported, cloudErr.Code)
+	helper.AssertEqual("storage", cloudErr.Service)
+
+	if !errors.Is(err, &cloudsdk.CloudError{Code: cloudsdk.ErrServiceNotSupported}) {
+		t.Error("Expected errors.Is to match ErrServiceNotSupported")
+	}
+	if errors.Is(err, &cloudsdk.CloudError{Code: cloudsdk.ErrResourceNotFound}) {
+		t.Error("Expected errors.Is not to match a different code")
+	}
+}
+
+func TestCloudErrorJSON(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	err := cloudsdk.NewResourceNotFoundError("aws", "compute", "instance", "i-123").
+		WithCause(errors.New("InvalidInstanceID.NotFound: internal detail"))
+	err.Context.RequestID = "req-1"
+	err.Context.ProviderCode = "InvalidInstanceID.NotFound"
+	err.Context.StatusCode = http.StatusBadRequest
+	err.Context.Attempts = 1
+	helper.AssertEqual(http.StatusNotFound, err.HTTPStatus())
+
+	data, marshalErr := json.Marshal(err)
+	helper.AssertNoError(marshalErr)
+
+	var decoded map[string]interface{}
+	helper.AssertNoError(json.Unmarshal(data, &decoded))
+	helper.AssertEqual("RESOURCE_NOT_FOUND", decoded["code"])
+	helper.AssertEqual(float64(http.StatusNotFound), decoded["http_status"])
+	errContext := decoded["context"].(map[string]interface{})
+	helper.AssertEqual("req-1", errContext["request_id"])
+	helper.AssertEqual("InvalidInstanceID.NotFound", errContext["provider_code"])
+	helper.AssertEqual(float64(http.StatusBadRequest), errContext["status_code"])
+	helper.AssertEqual(float64(1), errContext["attempts"])
+	if strings.Contains(string(data), "internal detail") {
+		t.Errorf("expected the cause to be left out, got %s", data)
+	}
+
+	// Decoding the output gives back the same error
+	var roundTrip cloudsdk.CloudError
+	helper.AssertNoError(json.Unmarshal(data, &roundTrip))
+	helper.AssertEqual(err.Code, roundTrip.Code)
+	helper.AssertEqual(err.Context.ProviderCode, roundTrip.Context.ProviderCode)
+}
+
+func TestErrorCodes(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1").WithQuota("CreateVM", 1)
+	client := cloudsdk.New(provider, nil)
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-1"))
+	helper.AssertNoError(err)
+	_, err = client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-2"))
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+	if !errors.Is(err, cloudsdk.ErrQuotaExceeded) {
+		t.Errorf("expected errors.Is to match ErrQuotaExceeded, got %v", err)
+	}
+	helper.AssertEqual(http.StatusTooManyRequests, err.(*cloudsdk.CloudError).HTTPStatus())
+
+	err = client.Compute().StartVM(ctx, vm.ID)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidState)
+	helper.AssertEqual(http.StatusConflict, err.(*cloudsdk.CloudError).HTTPStatus())
+
+	helper.AssertNoError(client.Storage().CreateBucket(ctx, cloudsdktesting.GenerateBucketConfig("my-bucket-2024")))
+	helper.AssertNoError(client.Storage().PutObject(ctx, "my-bucket-2024", "key", strings.NewReader("data")))
+	err = client.Storage().DeleteBucket(ctx, "my-bucket-2024")
+	helper.AssertErrorCode(err, cloudsdk.ErrDependencyViolation)
+
+	protected := true
+	dbConfig := cloudsdktesting.GenerateDBConfig("app-db")
+	dbConfig.DeletionProtection = &protected
+	db, err := client.Database().CreateDB(ctx, dbConfig)
+	helper.AssertNoError(err)
+	err = client.Database().DeleteDB(ctx, db.ID)
+	helper.AssertErrorCode(err, cloudsdk.ErrPreconditionFailed)
+	helper.AssertEqual(http.StatusPreconditionFailed, err.(*cloudsdk.CloudError).HTTPStatus())
+
+	// Codes don't match each other
+	if errors.Is(err, cloudsdk.ErrInvalidState) {
+		t.Errorf("expected ErrPreconditionFailed not to match ErrInvalidState")
+	}
+}
+
+func TestClientPanicsWithoutService(t *testing.T) {
+	provider := mock.New("us-east-1").WithSupportedServices(cloudsdk.ServiceCompute)
+	client := cloudsdk.NewFromProvider(provider)
+
+	cloudsdktesting.MustPanic(t, func() {
+		client.Storage()
+	})
+}
+
+func TestProviderContract(t *testing.T) {
+	cloudsdktesting.RunProviderContractTests(t, mock.New("us-east-1"))
+}
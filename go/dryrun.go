@@ -0,0 +1,216 @@
+package cloudsdk
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// PlannedCall is a provider API call that a mutating operation would have
+// made if it hadn't run as a dry run.
+type PlannedCall struct {
+	// Provider, Service and Operation identify the SDK operation, e.g.
+	// "aws", "compute" and OpCreateVM.
+	Provider  string      `json:"provider"`
+	Service   ServiceType `json:"service"`
+	Operation string      `json:"operation"`
+
+	// API is the provider's API call, e.g. "RunInstances". It is empty if
+	// the provider can't plan operations itself, in which case the plan
+	// only records the SDK operation.
+	API string `json:"api,omitempty"`
+
+	// Input is the request the call would have sent, with secrets such as
+	// passwords and user data redacted, or the operation's arguments when
+	// API is empty.
+	Input interface{} `json:"input,omitempty"`
+
+	// PermissionChecked reports whether the provider confirmed that the
+	// caller is allowed to make the call, as EC2 does for DryRun requests.
+	PermissionChecked bool `json:"permission_checked"`
+}
+
+// String describes the call, e.g. "aws compute CreateVM: RunInstances".
+func (c PlannedCall) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Provider, c.Service, c.Operation)
+	if c.API != "" {
+		s += ": " + c.API
+	}
+	if c.PermissionChecked {
+		s += " (permission checked)"
+	}
+	return s
+}
+
+// Plan collects the calls planned by operations that run as dry runs. It is
+// safe for concurrent use.
+type Plan struct {
+	mu    sync.Mutex
+	calls []PlannedCall
+}
+
+// Calls returns the planned calls in the order they were recorded.
+func (p *Plan) Calls() []PlannedCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedCall(nil), p.calls...)
+}
+
+// Reset clears the plan.
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = nil
+}
+
+func (p *Plan) add(calls ...PlannedCall) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, calls...)
+}
+
+type planKey struct{}
+
+// planRecorder collects the calls planned by a single operation.
+type planRecorder struct {
+	mu    sync.Mutex
+	calls []PlannedCall
+}
+
+// ContextWithPlan returns a context in which every mutating operation made
+// through a Client runs as a dry run, as with Config.DryRun, and records the
+// API calls it would have made in the returned Plan.
+//
+// Example:
+//
+//	ctx, plan := cloudsdk.ContextWithPlan(ctx)
+//	_, err := client.Compute().CreateVM(ctx, config) // returns ErrDryRun
+//	for _, call := range plan.Calls() {
+//	    fmt.Println(call)
+//	}
+func ContextWithPlan(ctx context.Context) (context.Context, *Plan) {
+	plan := &Plan{}
+	return context.WithValue(ctx, planKey{}, plan), plan
+}
+
+// IsDryRun reports whether ctx belongs to an operation that runs as a dry
+// run. Providers whose Capabilities report DryRun check it in every mutating
+// operation: they validate their input, check the caller's permissions where
+// the cloud API can, record the calls they would make with
+// RecordPlannedCall, and return without changing anything.
+func IsDryRun(ctx context.Context) bool {
+	switch ctx.Value(planKey{}).(type) {
+	case *planRecorder, *Plan:
+		return true
+	}
+	return false
+}
+
+// RecordPlannedCall records an API call that the dry-run operation ctx
+// belongs to would have made. It does nothing if ctx isn't a dry run.
+func RecordPlannedCall(ctx context.Context, call PlannedCall) {
+	switch target := ctx.Value(planKey{}).(type) {
+	case *planRecorder:
+		target.mu.Lock()
+		defer target.mu.Unlock()
+		target.calls = append(target.calls, call)
+	case *Plan:
+		// The provider is used without a Client
+		target.add(call)
+	}
+}
+
+// Plan returns the calls planned by the Client's dry-run operations since it
+// was created or its plan was last reset. Operations that run as dry runs
+// through a context from ContextWithPlan are recorded in that Plan instead.
+//
+// Example:
+//
+//	client := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+//	runProvisioning(ctx, client)
+//	for _, call := range client.Plan() {
+//	    fmt.Println(call)
+//	}
+func (c *Client) Plan() []PlannedCall {
+	return c.plan.Calls()
+}
+
+// ResetPlan clears the calls returned by Plan.
+func (c *Client) ResetPlan() {
+	c.plan.Reset()
+}
+
+// dryRunPlan returns the Plan that a mutating call should be recorded in,
+// or nil if the call isn't a dry run.
+func (c *Client) dryRunPlan(ctx context.Context, call *Call) *Plan {
+	if !IsMutatingOperation(call.Operation) {
+		return nil
+	}
+	if plan, ok := ctx.Value(planKey{}).(*Plan); ok {
+		return plan
+	}
+	if c.config.DryRun {
+		return c.plan
+	}
+	return nil
+}
+
+// withPlanRecorder returns a context in which the provider records the calls
+// planned by a single dry-run operation.
+func withPlanRecorder(ctx context.Context) (context.Context, *planRecorder) {
+	recorder := &planRecorder{}
+	return context.WithValue(ctx, planKey{}, recorder), recorder
+}
+
+// planLocally plans a call for a provider that can't plan operations
+// itself: it validates the call's configs and records the SDK operation.
+func (c *Client) planLocally(plan *Plan, call *Call) error {
+	for _, arg := range call.Args {
+		value := reflect.ValueOf(arg)
+		if value.Kind() != reflect.Pointer || value.Type().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if err := ValidateConfig(call.Provider, string(call.Service), arg); err != nil {
+			return err
+		}
+	}
+
+	var input interface{} = call.Args
+	if len(call.Args) == 1 {
+		input = call.Args[0]
+	}
+	return c.recordPlan(plan, call, nil, input)
+}
+
+// recordPlan adds the calls planned by a dry-run operation to plan and
+// returns the operation's ErrDryRun error. A provider that planned no calls
+// gets one entry for the SDK operation.
+func (c *Client) recordPlan(plan *Plan, call *Call, planned []PlannedCall, input interface{}) error {
+	if len(planned) == 0 {
+		planned = []PlannedCall{{Input: input}}
+	}
+	apis := make([]string, 0, len(planned))
+	for i := range planned {
+		if planned[i].Provider == "" {
+			planned[i].Provider = call.Provider
+		}
+		if planned[i].Service == "" {
+			planned[i].Service = call.Service
+		}
+		if planned[i].Operation == "" {
+			planned[i].Operation = call.Operation
+		}
+		if planned[i].API != "" {
+			apis = append(apis, planned[i].API)
+		}
+	}
+	plan.add(planned...)
+
+	err := NewDryRunError(call.Provider, string(call.Service), call.Operation)
+	if len(apis) > 0 {
+		err.Message = fmt.Sprintf("Dry run: operation '%s' would call %s", call.Operation, strings.Join(apis, ", "))
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package cloudsdk
+
+import "fmt"
+
+// IdempotencyKeyTag is the tag in which providers without client tokens, such
+// as S3 and RDS on AWS, store the IdempotencyKey of the resource a create
+// operation made. A repeated create with the same key finds the resource by
+// this tag instead of failing because its name is taken.
+const IdempotencyKeyTag = "cloudsdk:idempotency-key"
+
+// NewIdempotencyConflictError creates the error for a create operation whose
+// IdempotencyKey was already used with a different configuration, or for a
+// resource that exists under the same name without the key.
+func NewIdempotencyConflictError(provider string, service string, operation string, key string) *CloudError {
+	message := fmt.Sprintf("Idempotency key '%s' was already used with a different configuration for operation '%s'", key, operation)
+	return NewCloudError(ErrResourceConflict, message, provider, service, operation).
+		WithContext("", map[string]string{"idempotency_key": key}).
+		WithSuggestions(
+			"Use a new idempotency key for a different configuration",
+			"Retry with exactly the same configuration as the first call",
+			"Choose a different resource name if it is taken by another resource",
+		)
+}
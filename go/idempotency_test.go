@@ -0,0 +1,84 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		DefaultTags: map[string]string{"team": "platform"},
+	})
+
+	// Repeating a create with the same key returns the first VM
+	vmConfig := cloudsdktesting.GenerateVMConfig("web-server")
+	vmConfig.IdempotencyKey = "deploy-42-web-1"
+	first, err := client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+	second, err := client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+	helper.AssertEqual(first.ID, second.ID)
+	vms, err := client.Compute().ListVMs(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(vms))
+
+	// Reusing the key with a different configuration is a conflict. The
+	// generated instance type is random, so pick one it never returns
+	vmConfig.InstanceType = "r5.large"
+	_, err = client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+
+	// Without a key, every call creates a VM
+	vmConfig = cloudsdktesting.GenerateVMConfig("worker")
+	_, err = client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+	_, err = client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+	vms, err = client.Compute().ListVMs(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(3, len(vms))
+
+	// A retried bucket create succeeds instead of reporting the name as taken
+	bucketConfig := cloudsdktesting.GenerateBucketConfig("assets-2024")
+	bucketConfig.IdempotencyKey = "deploy-42-assets"
+	helper.AssertNoError(client.Storage().CreateBucket(ctx, bucketConfig))
+	helper.AssertNoError(client.Storage().CreateBucket(ctx, bucketConfig))
+	bucketConfig.IdempotencyKey = ""
+	helper.AssertErrorCode(client.Storage().CreateBucket(ctx, bucketConfig), cloudsdk.ErrResourceConflict)
+
+	dbConfig := cloudsdktesting.GenerateDBConfig("app-db")
+	dbConfig.IdempotencyKey = "deploy-42-db"
+	db, err := client.Database().CreateDB(ctx, dbConfig)
+	helper.AssertNoError(err)
+	again, err := client.Database().CreateDB(ctx, dbConfig)
+	helper.AssertNoError(err)
+	helper.AssertEqual(db.ID, again.ID)
+
+	spotConfig := &services.SpotInstanceConfig{
+		ImageID:        "ami-0abcdef1234567890",
+		InstanceType:   "t3.micro",
+		IdempotencyKey: "deploy-42-spot",
+	}
+	request, err := client.Compute().SpotInstances().Request(ctx, spotConfig)
+	helper.AssertNoError(err)
+	retried, err := client.Compute().SpotInstances().Request(ctx, spotConfig)
+	helper.AssertNoError(err)
+	helper.AssertEqual(request.SpotInstanceRequestId, retried.SpotInstanceRequestId)
+
+	// Keys are forgotten on Reset
+	provider.Reset()
+	vmConfig = cloudsdktesting.GenerateVMConfig("web-server")
+	vmConfig.InstanceType = "t3.large"
+	vmConfig.IdempotencyKey = "deploy-42-web-1"
+	_, err = client.Compute().CreateVM(ctx, vmConfig)
+	helper.AssertNoError(err)
+}
@@ -0,0 +1,130 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestImagesLatest(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	images := client.Compute().Images()
+
+	image, err := images.Latest(ctx, &services.ImageFilter{
+		Owners:      []string{"amazon"},
+		NamePattern: "al2023-ami-2023.*-x86_64",
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ami-0a1b2c3d4e5f60002", image.ID)
+
+	image, err = images.Latest(ctx, &services.ImageFilter{
+		Owners:       []string{"amazon"},
+		NamePattern:  "al2023-ami-*",
+		Architecture: "arm64",
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ami-0a1b2c3d4e5f60003", image.ID)
+
+	_, err = images.Latest(ctx, &services.ImageFilter{Owners: []string{"amazon"}, NamePattern: "debian-*"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	// Public images are only listed for their owner
+	list, err := images.List(ctx, nil)
+	helper.AssertNoError(err)
+	helper.AssertEqual(0, len(list))
+	list, err = images.List(ctx, &services.ImageFilter{Owners: []string{"amazon", "099720109477"}})
+	helper.AssertNoError(err)
+	helper.AssertEqual(4, len(list))
+	helper.AssertEqual("ami-0a1b2c3d4e5f60003", list[0].ID)
+
+	image, err = images.Get(ctx, "ami-0a1b2c3d4e5f60004")
+	helper.AssertNoError(err)
+	helper.AssertEqual("099720109477", image.OwnerID)
+	_, err = images.Get(ctx, "ami-00000000000000000")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+}
+
+func TestImagesCreateAndDeregister(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), &cloudsdk.Config{
+		DefaultTags: map[string]string{"Team": "web"},
+	})
+	images := client.Compute().Images()
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-server"))
+	helper.AssertNoError(err)
+
+	image, err := images.Create(ctx, &services.CreateImageConfig{VMID: vm.ID, Name: "golden-web"})
+	helper.AssertNoError(err)
+	helper.AssertEqual("web", image.Tags["Team"])
+	helper.AssertNoError(services.WaitUntilImageAvailable(ctx, client.Compute(), image.ID, fastPolls))
+
+	// The new image is listed for the account by default
+	list, err := images.List(ctx, nil)
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(list))
+	helper.AssertEqual(image.ID, list[0].ID)
+
+	_, err = images.Create(ctx, &services.CreateImageConfig{VMID: vm.ID, Name: "golden-web"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	_, err = images.Create(ctx, &services.CreateImageConfig{VMID: "i-missing", Name: "golden-api"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	_, err = images.Create(ctx, &services.CreateImageConfig{VMID: vm.ID})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	// VMs can be launched from the image
+	config := cloudsdktesting.GenerateVMConfig("web-server-2")
+	config.ImageID = image.ID
+	_, err = client.Compute().CreateVM(ctx, config)
+	helper.AssertNoError(err)
+
+	helper.AssertErrorCode(images.Deregister(ctx, "ami-0a1b2c3d4e5f60001"), cloudsdk.ErrAuthorization)
+	helper.AssertNoError(images.Deregister(ctx, image.ID))
+	_, err = images.Get(ctx, image.ID)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+}
+
+func TestImagesWithImage(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1").WithImage(&services.Image{
+		ID:           "ami-0123456789abcdef0",
+		Name:         "golden-web-2024-10-01",
+		Architecture: "x86_64",
+		State:        "available",
+		CreationDate: "2024-10-01T00:00:00Z",
+	})
+	client := cloudsdk.New(provider, nil)
+
+	provider.Reset()
+	image, err := client.Compute().Images().Latest(ctx, &services.ImageFilter{NamePattern: "golden-web-*"})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ami-0123456789abcdef0", image.ID)
+}
+
+func TestImagesDryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+
+	err := client.Compute().Images().Deregister(ctx, "ami-0a1b2c3d4e5f60001")
+	if !errors.Is(err, cloudsdk.ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+	helper.AssertEqual(false, provider.WasCalled(cloudsdk.OpDeregisterImage))
+	helper.AssertEqual(cloudsdk.OpDeregisterImage, client.Plan()[0].Operation)
+
+	// Lookups still go to the provider
+	_, err = client.Compute().Images().Latest(ctx, &services.ImageFilter{Owners: []string{"amazon"}})
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, provider.WasCalled(cloudsdk.OpLatestImage))
+}
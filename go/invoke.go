@@ -0,0 +1,173 @@
+package cloudsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// operationFunc performs a single provider call using the prepared context.
+type operationFunc func(ctx context.Context) (interface{}, error)
+
+// invoke runs a service operation through the Client's pipeline.
+// Every call made through a Client-returned service goes through here, so
+// tracing, metrics, retry policies, middleware, dry-run handling, timeouts and logging
+// behave the same for every provider. args are the method's arguments,
+// excluding the context.
+func (c *Client) invoke(ctx context.Context, service ServiceType, operation string, args []interface{}, fn operationFunc) (interface{}, error) {
+	start := time.Now()
+	call := &Call{
+		Provider:  c.provider.Name(),
+		Service:   service,
+		Operation: operation,
+		Args:      args,
+	}
+
+	ctx, stats := withOperationStats(ctx)
+	ctx = c.withRetry(ctx, operation)
+	ctx, span := c.startOperationSpan(ctx, call)
+
+	result, err := c.chain(func(ctx context.Context, call *Call) (interface{}, error) {
+		return c.execute(ctx, call, fn)
+	})(ctx, call)
+
+	duration := time.Since(start)
+	endOperationSpan(span, call, stats, result, err)
+	c.observeOperation(ctx, call, stats, duration, err)
+	c.logOperation(ctx, service, operation, duration, err)
+	return result, err
+}
+
+// execute applies capability checks, dry-run handling, rate limits, the
+// circuit breaker and timeouts to a single provider call. It is the innermost
+// handler of the middleware chain.
+func (c *Client) execute(ctx context.Context, call *Call, fn operationFunc) (interface{}, error) {
+	caps := c.provider.Capabilities()
+	if err := c.checkCapabilities(ctx, caps, call); err != nil {
+		return nil, err
+	}
+
+	plan := c.dryRunPlan(ctx, call)
+	if plan != nil && !caps.DryRun {
+		return nil, c.planLocally(plan, call)
+	}
+
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx, call); err != nil {
+			return nil, err
+		}
+	}
+
+	breaker := c.breakerFor(call.Service)
+	if breaker != nil {
+		if err := breaker.allow(call.Operation); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := c.timeoutFor(ctx, call.Operation)
+	opCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		opCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	var recorder *planRecorder
+	if plan != nil {
+		opCtx, recorder = withPlanRecorder(opCtx)
+	}
+
+	result, err := fn(opCtx)
+	if err != nil && timeout > 0 && errors.Is(opCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		err = NewCloudError(
+			ErrNetworkTimeout,
+			fmt.Sprintf("Operation timed out after %v", timeout),
+			call.Provider, string(call.Service), call.Operation,
+		).WithCause(err).WithSuggestions(
+			"Increase Config.DefaultTimeout or the operation's entry in Config.Timeouts",
+			"Pass a longer timeout for this call with services.WithTimeout",
+			"Check network connectivity to the provider",
+		)
+	}
+
+	if breaker != nil {
+		if ctx.Err() != nil {
+			// The caller gave up, which says nothing about the service
+			breaker.release()
+		} else {
+			breaker.done(err)
+		}
+	}
+
+	if plan != nil && err == nil {
+		result = nil
+		err = c.recordPlan(plan, call, recorder.calls, nil)
+	}
+
+	// Streamed results such as GetObject bodies outlive this call, so the
+	// timeout is released when the body is closed rather than on return.
+	if body, ok := result.(io.ReadCloser); ok && err == nil {
+		result = &cancelOnClose{ReadCloser: body, cancel: cancel}
+	} else {
+		cancel()
+	}
+	return result, err
+}
+
+// timeoutFor returns the timeout for an operation: the call's
+// services.CallOptions timeout, else the configured one, or zero for none.
+func (c *Client) timeoutFor(ctx context.Context, operation string) time.Duration {
+	if timeout := services.CallOptionsFromContext(ctx).Timeout; timeout > 0 {
+		return timeout
+	}
+	if timeout, ok := c.config.Timeouts[operation]; ok {
+		return timeout
+	}
+	return c.config.DefaultTimeout
+}
+
+// logOperation writes one structured record per operation to the configured logger.
+func (c *Client) logOperation(ctx context.Context, service ServiceType, operation string, duration time.Duration, err error) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("provider", c.provider.Name()),
+		slog.String("service", string(service)),
+		slog.String("operation", operation),
+		slog.Duration("duration", duration),
+	}
+
+	if err == nil {
+		logger.LogAttrs(ctx, slog.LevelDebug, "cloudsdk operation succeeded", attrs...)
+		return
+	}
+
+	level := slog.LevelWarn
+	var cloudErr *CloudError
+	if errors.As(err, &cloudErr) {
+		attrs = append(attrs, slog.String("error_code", string(cloudErr.Code)))
+		if cloudErr.Code == ErrDryRun {
+			level = slog.LevelInfo
+		}
+	}
+	attrs = append(attrs, slog.String("error", err.Error()))
+	logger.LogAttrs(ctx, level, "cloudsdk operation failed", attrs...)
+}
+
+// cancelOnClose releases an operation's context when a streamed body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnClose) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,199 @@
+package cloudsdk_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// sshString reads an SSH wire-format string from data and returns it and the
+// rest of data.
+func sshString(t *testing.T, data []byte) ([]byte, []byte) {
+	t.Helper()
+	if len(data) < 4 {
+		t.Fatalf("truncated SSH string")
+	}
+	n := binary.BigEndian.Uint32(data)
+	if uint64(n) > uint64(len(data)-4) {
+		t.Fatalf("truncated SSH string of length %d", n)
+	}
+	return data[4 : 4+n], data[4+n:]
+}
+
+// parseEd25519AuthorizedKey returns the key of an "ssh-ed25519" public key in
+// authorized_keys format.
+func parseEd25519AuthorizedKey(t *testing.T, authorizedKey string) ed25519.PublicKey {
+	t.Helper()
+	fields := strings.Fields(authorizedKey)
+	if len(fields) < 2 || fields[0] != "ssh-ed25519" {
+		t.Fatalf("not an ed25519 public key: %q", authorizedKey)
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		t.Fatalf("public key is not base64: %v", err)
+	}
+	algorithm, rest := sshString(t, blob)
+	key, _ := sshString(t, rest)
+	if string(algorithm) != "ssh-ed25519" || len(key) != ed25519.PublicKeySize {
+		t.Fatalf("malformed ed25519 public key")
+	}
+	return ed25519.PublicKey(key)
+}
+
+// parseOpenSSHEd25519PrivateKey returns the key of an unencrypted ed25519
+// private key in OpenSSH format, as ssh-keygen writes it.
+func parseOpenSSHEd25519PrivateKey(t *testing.T, privateKey string) ed25519.PrivateKey {
+	t.Helper()
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil || block.Type != "OPENSSH PRIVATE KEY" {
+		t.Fatalf("not an OpenSSH private key: %q", privateKey)
+	}
+	data, found := bytes.CutPrefix(block.Bytes, []byte("openssh-key-v1\x00"))
+	if !found {
+		t.Fatalf("missing OpenSSH key magic")
+	}
+	cipher, data := sshString(t, data)
+	kdf, data := sshString(t, data)
+	_, data = sshString(t, data) // KDF options
+	if string(cipher) != "none" || string(kdf) != "none" {
+		t.Fatalf("private key is encrypted with %s/%s", cipher, kdf)
+	}
+	if binary.BigEndian.Uint32(data) != 1 {
+		t.Fatalf("expected one key")
+	}
+	_, data = sshString(t, data[4:]) // public key
+	private, _ := sshString(t, data)
+
+	if len(private) < 8 || !bytes.Equal(private[:4], private[4:8]) {
+		t.Fatalf("private key check bytes don't match")
+	}
+	algorithm, private := sshString(t, private[8:])
+	_, private = sshString(t, private) // public key
+	key, _ := sshString(t, private)
+	if string(algorithm) != "ssh-ed25519" || len(key) != ed25519.PrivateKeySize {
+		t.Fatalf("malformed ed25519 private key")
+	}
+	return ed25519.PrivateKey(key)
+}
+
+func TestKeyPairsCreate(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), &cloudsdk.Config{
+		DefaultTags: map[string]string{"Team": "platform"},
+	})
+	keyPairs := client.Compute().KeyPairs()
+
+	keyPair, err := keyPairs.Create(ctx, &services.KeyPairConfig{Name: "deploy"})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ed25519", keyPair.KeyType)
+	helper.AssertEqual("platform", keyPair.Tags["Team"])
+	helper.AssertContains(keyPair.Fingerprint, "SHA256:")
+
+	// The private key signs what the public key verifies, as in an SSH login
+	publicKey := parseEd25519AuthorizedKey(t, keyPair.PublicKey)
+	privateKey := parseOpenSSHEd25519PrivateKey(t, keyPair.PrivateKey)
+	challenge := []byte("session identifier")
+	if !ed25519.Verify(publicKey, challenge, ed25519.Sign(privateKey, challenge)) {
+		t.Fatal("signature from the private key doesn't verify with the public key")
+	}
+
+	rsaKeyPair, err := keyPairs.Create(ctx, &services.KeyPairConfig{Name: "windows", KeyType: "rsa"})
+	helper.AssertNoError(err)
+	helper.AssertContains(rsaKeyPair.PublicKey, "ssh-rsa ")
+	block, _ := pem.Decode([]byte(rsaKeyPair.PrivateKey))
+	if block == nil {
+		t.Fatalf("RSA private key is not PEM: %q", rsaKeyPair.PrivateKey)
+	}
+	rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	helper.AssertNoError(err)
+	helper.AssertEqual(2048, rsaKey.N.BitLen())
+
+	_, err = keyPairs.Create(ctx, &services.KeyPairConfig{Name: "deploy"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	_, err = keyPairs.Create(ctx, &services.KeyPairConfig{Name: "dsa", KeyType: "dsa"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	// Private keys are only returned once
+	list, err := keyPairs.List(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(list))
+	helper.AssertEqual("deploy", list[0].Name)
+	helper.AssertEqual(keyPair.PublicKey, list[0].PublicKey)
+	helper.AssertEqual("", list[0].PrivateKey)
+	helper.AssertEqual("", list[1].PrivateKey)
+
+	// VMs are launched with the key pair by name
+	config := cloudsdktesting.GenerateVMConfig("web-server")
+	config.KeyName = keyPair.Name
+	vm, err := client.Compute().CreateVM(ctx, config)
+	helper.AssertNoError(err)
+	helper.AssertEqual("deploy", vm.KeyName)
+}
+
+func TestKeyPairsImportAndDelete(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	keyPairs := client.Compute().KeyPairs()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	helper.AssertNoError(err)
+	var blob bytes.Buffer
+	for _, s := range [][]byte{[]byte("ssh-ed25519"), publicKey} {
+		helper.AssertNoError(binary.Write(&blob, binary.BigEndian, uint32(len(s))))
+		blob.Write(s)
+	}
+	authorizedKey := "ssh-ed25519 " + base64.StdEncoding.EncodeToString(blob.Bytes()) + " alice@laptop\n"
+
+	keyPair, err := keyPairs.Import(ctx, &services.ImportKeyPairConfig{Name: "alice-laptop", PublicKey: authorizedKey})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ed25519", keyPair.KeyType)
+	helper.AssertEqual("", keyPair.PrivateKey)
+	helper.AssertEqual(true, publicKey.Equal(parseEd25519AuthorizedKey(t, keyPair.PublicKey)))
+
+	_, err = keyPairs.Import(ctx, &services.ImportKeyPairConfig{Name: "alice-laptop", PublicKey: authorizedKey})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	_, err = keyPairs.Import(ctx, &services.ImportKeyPairConfig{Name: "bad", PublicKey: "ssh-ed25519 not-base64"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	_, err = keyPairs.Import(ctx, &services.ImportKeyPairConfig{Name: "bad", PublicKey: "ssh-rsa " + base64.StdEncoding.EncodeToString(blob.Bytes())})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	helper.AssertNoError(keyPairs.Delete(ctx, "alice-laptop"))
+	// Deleting again is not an error
+	helper.AssertNoError(keyPairs.Delete(ctx, "alice-laptop"))
+	list, err := keyPairs.List(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(0, len(list))
+}
+
+func TestKeyPairsDryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+
+	_, err := client.Compute().KeyPairs().Create(ctx, &services.KeyPairConfig{Name: "deploy"})
+	if !errors.Is(err, cloudsdk.ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+	helper.AssertEqual(false, provider.WasCalled(cloudsdk.OpCreateKeyPair))
+	helper.AssertEqual(cloudsdk.OpCreateKeyPair, client.Plan()[0].Operation)
+
+	_, err = client.Compute().KeyPairs().List(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, provider.WasCalled(cloudsdk.OpListKeyPairs))
+}
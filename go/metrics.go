@@ -0,0 +1,62 @@
+package cloudsdk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// OperationObservation describes one completed operation made through a Client.
+type OperationObservation struct {
+	// Provider, Service and Operation identify the operation, e.g. "aws",
+	// ServiceCompute and OpCreateVM.
+	Provider  string
+	Service   ServiceType
+	Operation string
+
+	// Duration is the operation's latency, including middleware and provider retries.
+	Duration time.Duration
+
+	// Retries is the number of retries the provider reported for the operation.
+	Retries int
+
+	// ErrorCode is the CloudError code of a failed operation. It is empty when
+	// the operation succeeded, and ErrProviderError for failures that are not
+	// CloudErrors, such as context cancellation.
+	ErrorCode ErrorCode
+
+	// Err is the error returned by the operation, or nil.
+	Err error
+}
+
+// Metrics receives an observation for every operation made through a Client.
+// Set Config.Metrics to collect them. Implementations must be safe for
+// concurrent use; PrometheusMetrics is the built-in implementation.
+type Metrics interface {
+	ObserveOperation(ctx context.Context, observation OperationObservation)
+}
+
+// observeOperation reports a completed operation to the configured Metrics.
+func (c *Client) observeOperation(ctx context.Context, call *Call, stats *operationStats, duration time.Duration, err error) {
+	metrics := c.config.Metrics
+	if metrics == nil {
+		return
+	}
+
+	observation := OperationObservation{
+		Provider:  call.Provider,
+		Service:   call.Service,
+		Operation: call.Operation,
+		Duration:  duration,
+		Retries:   int(stats.retries.Load()),
+		Err:       err,
+	}
+	if err != nil {
+		observation.ErrorCode = ErrProviderError
+		var cloudErr *CloudError
+		if errors.As(err, &cloudErr) {
+			observation.ErrorCode = cloudErr.Code
+		}
+	}
+	metrics.ObserveOperation(ctx, observation)
+}
@@ -0,0 +1,214 @@
+package cloudsdk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the histogram bucket upper bounds, in seconds,
+// used by NewPrometheusMetrics when none are given. They span fast reads
+// through slow database provisioning calls.
+var DefaultLatencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// PrometheusMetrics is a Metrics implementation that keeps counters and a
+// latency histogram per provider, service and operation, and serves them in
+// the Prometheus text exposition format.
+//
+// It exposes:
+//   - cloudsdk_operations_total: operations started
+//   - cloudsdk_operation_errors_total: failed operations, with a "code" label holding the ErrorCode
+//   - cloudsdk_operation_retries_total: retries reported by providers
+//   - cloudsdk_operation_duration_seconds: operation latency histogram
+//
+// Example:
+//
+//	metrics := cloudsdk.NewPrometheusMetrics()
+//	client := cloudsdk.New(provider, &cloudsdk.Config{Metrics: metrics})
+//	http.Handle("/metrics", metrics)
+type PrometheusMetrics struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[operationKey]*operationSeries
+}
+
+// operationKey identifies the series of one operation.
+type operationKey struct {
+	provider  string
+	service   string
+	operation string
+}
+
+// operationSeries holds the values recorded for one operation.
+type operationSeries struct {
+	calls        uint64
+	retries      uint64
+	errors       map[ErrorCode]uint64
+	bucketCounts []uint64 // per bucket, not cumulative
+	sum          float64
+}
+
+// NewPrometheusMetrics creates an empty PrometheusMetrics. buckets sets the
+// latency histogram's upper bounds in seconds; if none are given,
+// DefaultLatencyBuckets are used.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &PrometheusMetrics{
+		buckets: sorted,
+		series:  make(map[operationKey]*operationSeries),
+	}
+}
+
+// ObserveOperation implements Metrics.
+func (m *PrometheusMetrics) ObserveOperation(ctx context.Context, observation OperationObservation) {
+	key := operationKey{
+		provider:  observation.Provider,
+		service:   string(observation.Service),
+		operation: observation.Operation,
+	}
+	seconds := observation.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.series[key]
+	if !ok {
+		series = &operationSeries{
+			errors:       make(map[ErrorCode]uint64),
+			bucketCounts: make([]uint64, len(m.buckets)),
+		}
+		m.series[key] = series
+	}
+
+	series.calls++
+	series.retries += uint64(observation.Retries)
+	if observation.ErrorCode != "" {
+		series.errors[observation.ErrorCode]++
+	}
+	series.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			series.bucketCounts[i]++
+			break
+		}
+	}
+}
+
+// ServeHTTP writes the current metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := m.WriteTo(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WriteTo writes the current metrics to w in the Prometheus text format.
+// Series are sorted so the output is stable between scrapes.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	keys := make([]operationKey, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.provider != b.provider {
+			return a.provider < b.provider
+		}
+		if a.service != b.service {
+			return a.service < b.service
+		}
+		return a.operation < b.operation
+	})
+
+	counter := &countingWriter{w: w}
+	out := bufio.NewWriter(counter)
+
+	writeHeader(out, "cloudsdk_operations_total", "counter", "Total number of SDK operations.")
+	for _, key := range keys {
+		fmt.Fprintf(out, "cloudsdk_operations_total{%s} %d\n", key.labels(), m.series[key].calls)
+	}
+
+	writeHeader(out, "cloudsdk_operation_errors_total", "counter", "Total number of failed SDK operations by error code.")
+	for _, key := range keys {
+		series := m.series[key]
+		codes := make([]string, 0, len(series.errors))
+		for code := range series.errors {
+			codes = append(codes, string(code))
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(out, "cloudsdk_operation_errors_total{%s,code=\"%s\"} %d\n",
+				key.labels(), escapeLabelValue(code), series.errors[ErrorCode(code)])
+		}
+	}
+
+	writeHeader(out, "cloudsdk_operation_retries_total", "counter", "Total number of retries made by providers.")
+	for _, key := range keys {
+		fmt.Fprintf(out, "cloudsdk_operation_retries_total{%s} %d\n", key.labels(), m.series[key].retries)
+	}
+
+	writeHeader(out, "cloudsdk_operation_duration_seconds", "histogram", "SDK operation latency in seconds.")
+	for _, key := range keys {
+		series := m.series[key]
+		labels := key.labels()
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += series.bucketCounts[i]
+			fmt.Fprintf(out, "cloudsdk_operation_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(out, "cloudsdk_operation_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, series.calls)
+		fmt.Fprintf(out, "cloudsdk_operation_duration_seconds_sum{%s} %s\n", labels, formatFloat(series.sum))
+		fmt.Fprintf(out, "cloudsdk_operation_duration_seconds_count{%s} %d\n", labels, series.calls)
+	}
+	m.mu.Unlock()
+
+	err := out.Flush()
+	return counter.n, err
+}
+
+// labels formats the key as Prometheus labels, without braces.
+func (k operationKey) labels() string {
+	return fmt.Sprintf("provider=\"%s\",service=\"%s\",operation=\"%s\"",
+		escapeLabelValue(k.provider), escapeLabelValue(k.service), escapeLabelValue(k.operation))
+}
+
+func writeHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and newlines as the
+// Prometheus text format requires.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,78 @@
+package cloudsdk
+
+import "context"
+
+// Call describes a single service operation made through a Client.
+// Middleware receives it before the provider is called and may inspect or
+// adjust it.
+type Call struct {
+	// Provider is the name of the provider handling the call, e.g. "aws".
+	Provider string
+
+	// Service is the service the operation belongs to.
+	Service ServiceType
+
+	// Operation is the operation name, e.g. OpCreateVM or OpRequestSpotInstances.
+	Operation string
+
+	// Args holds the arguments passed to the provider, excluding the context,
+	// in the order of the service method's parameters. Pointer arguments such
+	// as *services.VMConfig are the values the provider will receive, so
+	// middleware can adjust them in place (for example to add tags).
+	Args []interface{}
+}
+
+// Handler performs a Call and returns its result.
+// The result has the type returned by the service method (e.g. *services.VM),
+// or is nil for methods that only return an error.
+type Handler func(ctx context.Context, call *Call) (interface{}, error)
+
+// Middleware wraps a Handler to run code around every service call.
+// A middleware can inspect the call, short-circuit it by returning without
+// calling next, and inspect or replace the result and error.
+//
+// Example:
+//
+//	client.Use(func(next cloudsdk.Handler) cloudsdk.Handler {
+//	    return func(ctx context.Context, call *cloudsdk.Call) (interface{}, error) {
+//	        if cloudsdk.IsMutatingOperation(call.Operation) && !allowed(ctx) {
+//	            return nil, cloudsdk.NewAuthorizationError(call.Provider, string(call.Service), call.Operation, nil)
+//	        }
+//	        return next(ctx, call)
+//	    }
+//	})
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the Client's chain. Middleware run in the order
+// they were added, with the first one outermost, and apply to every call made
+// through services returned by the Client, including the sub-services of
+// Compute such as Volumes and SecurityGroups.
+//
+// Use is safe to call concurrently with service calls; calls already in
+// progress keep the chain they started with.
+func (c *Client) Use(middleware ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chain := make([]Middleware, 0, len(c.middleware)+len(middleware))
+	chain = append(chain, c.middleware...)
+	for _, m := range middleware {
+		if m != nil {
+			chain = append(chain, m)
+		}
+	}
+	c.middleware = chain
+}
+
+// chain wraps final with the Client's middleware.
+func (c *Client) chain(final Handler) Handler {
+	c.mu.RLock()
+	middleware := c.middleware
+	c.mu.RUnlock()
+
+	handler := final
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
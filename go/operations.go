@@ -0,0 +1,137 @@
+package cloudsdk
+
+// Operation names used by the Client for per-operation configuration such as
+// Config.Timeouts. They match the operation names reported in CloudError and
+// recorded by the mock provider.
+const (
+	// Compute operations
+	OpCreateVM           = "CreateVM"
+	OpListVMs            = "ListVMs"
+	OpListVMsWithOptions = "ListVMsWithOptions"
+	OpGetVM              = "GetVM"
+	OpStartVM            = "StartVM"
+	OpStopVM             = "StopVM"
+	OpDeleteVM           = "DeleteVM"
+	OpRebootVM           = "RebootVM"
+	OpResizeVM           = "ResizeVM"
+
+	// Compute sub-service operations
+	OpListInstanceTypes            = "ListInstanceTypes"
+	OpCreatePlacementGroup         = "CreatePlacementGroup"
+	OpDeletePlacementGroup         = "DeletePlacementGroup"
+	OpListPlacementGroups          = "ListPlacementGroups"
+	OpRequestSpotInstances         = "RequestSpotInstances"
+	OpDescribeSpotInstanceRequests = "DescribeSpotInstanceRequests"
+	OpCancelSpotInstanceRequests   = "CancelSpotInstanceRequests"
+	OpCreateVolume                 = "CreateVolume"
+	OpListVolumes                  = "ListVolumes"
+	OpGetVolume                    = "GetVolume"
+	OpAttachVolume                 = "AttachVolume"
+	OpDetachVolume                 = "DetachVolume"
+	OpResizeVolume                 = "ResizeVolume"
+	OpSnapshotVolume               = "SnapshotVolume"
+	OpDeleteVolume                 = "DeleteVolume"
+	OpListImages                   = "ListImages"
+	OpGetImage                     = "GetImage"
+	OpLatestImage                  = "LatestImage"
+	OpCreateImage                  = "CreateImage"
+	OpDeregisterImage              = "DeregisterImage"
+	OpCreateKeyPair                = "CreateKeyPair"
+	OpImportKeyPair                = "ImportKeyPair"
+	OpListKeyPairs                 = "ListKeyPairs"
+	OpDeleteKeyPair                = "DeleteKeyPair"
+	OpCreateSecurityGroup          = "CreateSecurityGroup"
+	OpListSecurityGroups           = "ListSecurityGroups"
+	OpGetSecurityGroup             = "GetSecurityGroup"
+	OpAddSecurityGroupRules        = "AddSecurityGroupRules"
+	OpRevokeSecurityGroupRules     = "RevokeSecurityGroupRules"
+	OpDeleteSecurityGroup          = "DeleteSecurityGroup"
+
+	// Storage operations
+	OpCreateBucket = "CreateBucket"
+	OpListBuckets  = "ListBuckets"
+	OpDeleteBucket = "DeleteBucket"
+	OpPutObject    = "PutObject"
+	OpGetObject    = "GetObject"
+	OpDeleteObject = "DeleteObject"
+	OpListObjects  = "ListObjects"
+
+	// Database operations
+	OpCreateDB = "CreateDB"
+	OpListDBs  = "ListDBs"
+	OpGetDB    = "GetDB"
+	OpDeleteDB = "DeleteDB"
+)
+
+// serviceOperations lists the operations of each service, in the order above.
+var serviceOperations = map[ServiceType][]string{
+	ServiceCompute: {
+		OpCreateVM, OpListVMs, OpListVMsWithOptions, OpGetVM, OpStartVM, OpStopVM, OpDeleteVM,
+		OpRebootVM, OpResizeVM,
+		OpListInstanceTypes,
+		OpCreatePlacementGroup, OpDeletePlacementGroup, OpListPlacementGroups,
+		OpRequestSpotInstances, OpDescribeSpotInstanceRequests, OpCancelSpotInstanceRequests,
+		OpCreateVolume, OpListVolumes, OpGetVolume, OpAttachVolume, OpDetachVolume,
+		OpResizeVolume, OpSnapshotVolume, OpDeleteVolume,
+		OpListImages, OpGetImage, OpLatestImage, OpCreateImage, OpDeregisterImage,
+		OpCreateKeyPair, OpImportKeyPair, OpListKeyPairs, OpDeleteKeyPair,
+		OpCreateSecurityGroup, OpListSecurityGroups, OpGetSecurityGroup,
+		OpAddSecurityGroupRules, OpRevokeSecurityGroupRules, OpDeleteSecurityGroup,
+	},
+	ServiceStorage: {
+		OpCreateBucket, OpListBuckets, OpDeleteBucket,
+		OpPutObject, OpGetObject, OpDeleteObject, OpListObjects,
+	},
+	ServiceDatabase: {
+		OpCreateDB, OpListDBs, OpGetDB, OpDeleteDB,
+	},
+}
+
+// ServiceOperations returns the names of every operation of a service,
+// including those of its sub-services. Providers use it to build their
+// Capabilities.
+func ServiceOperations(service ServiceType) []string {
+	return append([]string(nil), serviceOperations[service]...)
+}
+
+// mutatingOperations lists the operations that create, change or delete resources.
+// These are the operations skipped in dry-run mode.
+var mutatingOperations = map[string]bool{
+	OpCreateVM:                   true,
+	OpStartVM:                    true,
+	OpStopVM:                     true,
+	OpDeleteVM:                   true,
+	OpRebootVM:                   true,
+	OpResizeVM:                   true,
+	OpCreatePlacementGroup:       true,
+	OpDeletePlacementGroup:       true,
+	OpRequestSpotInstances:       true,
+	OpCancelSpotInstanceRequests: true,
+	OpCreateVolume:               true,
+	OpAttachVolume:               true,
+	OpDetachVolume:               true,
+	OpResizeVolume:               true,
+	OpSnapshotVolume:             true,
+	OpDeleteVolume:               true,
+	OpCreateImage:                true,
+	OpDeregisterImage:            true,
+	OpCreateKeyPair:              true,
+	OpImportKeyPair:              true,
+	OpDeleteKeyPair:              true,
+	OpCreateSecurityGroup:        true,
+	OpAddSecurityGroupRules:      true,
+	OpRevokeSecurityGroupRules:   true,
+	OpDeleteSecurityGroup:        true,
+	OpCreateBucket:               true,
+	OpDeleteBucket:               true,
+	OpPutObject:                  true,
+	OpDeleteObject:               true,
+	OpCreateDB:                   true,
+	OpDeleteDB:                   true,
+}
+
+// IsMutatingOperation reports whether the named operation creates, changes or
+// deletes resources, as opposed to only reading them.
+func IsMutatingOperation(operation string) bool {
+	return mutatingOperations[operation]
+}
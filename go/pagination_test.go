@@ -0,0 +1,133 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// createVMs creates n VMs named prefix-0 to prefix-(n-1) with the given tags.
+func createVMs(t *testing.T, compute services.Compute, prefix string, n int, tags map[string]string) []*services.VM {
+	t.Helper()
+	vms := make([]*services.VM, n)
+	for i := range vms {
+		config := cloudsdktesting.GenerateVMConfig(fmt.Sprintf("%s-%d", prefix, i))
+		config.Tags = tags
+		vm, err := compute.CreateVM(context.Background(), config)
+		if err != nil {
+			t.Fatalf("CreateVM failed: %v", err)
+		}
+		vms[i] = vm
+	}
+	return vms
+}
+
+func TestListVMsWithOptions(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	compute := client.Compute()
+
+	web := createVMs(t, compute, "web", 3, map[string]string{"Environment": "production"})
+	createVMs(t, compute, "batch", 2, map[string]string{"Environment": "staging"})
+	helper.AssertNoError(compute.StopVM(ctx, web[0].ID))
+
+	// Filters are combined
+	page, err := compute.ListVMsWithOptions(ctx, &services.ListVMsOptions{
+		Tags:       map[string]string{"Environment": "production"},
+		States:     []string{"running"},
+		NamePrefix: "web-",
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(page.VMs))
+	helper.AssertEqual("", page.NextCursor)
+	for _, vm := range page.VMs {
+		helper.AssertContains(vm.Name, "web-")
+		helper.AssertEqual("running", vm.State)
+	}
+
+	// Pages follow one another without gaps or repeats
+	seen := map[string]bool{}
+	opts := &services.ListVMsOptions{PageSize: 2}
+	pages := 0
+	for {
+		page, err := compute.ListVMsWithOptions(ctx, opts)
+		helper.AssertNoError(err)
+		pages++
+		for _, vm := range page.VMs {
+			if seen[vm.ID] {
+				t.Fatalf("VM %s listed twice", vm.ID)
+			}
+			seen[vm.ID] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	helper.AssertEqual(5, len(seen))
+	helper.AssertEqual(3, pages)
+
+	// Page sizes are validated
+	_, err = compute.ListVMsWithOptions(ctx, &services.ListVMsOptions{PageSize: 5000})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+}
+
+func TestVMIterator(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, nil)
+
+	createVMs(t, client.Compute(), "web", 5, nil)
+
+	// Iterating everything fetches every page
+	it := services.NewVMIterator(ctx, client.Compute(), services.ListVMsOptions{PageSize: 2})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	helper.AssertNoError(it.Err())
+	helper.AssertEqual(5, count)
+	helper.AssertEqual(3, it.Pages())
+	helper.AssertEqual(3, provider.CallCount(cloudsdk.OpListVMsWithOptions))
+
+	// Stopping early doesn't fetch the remaining pages, and the cursor
+	// resumes where the iterator stopped
+	provider.Reset()
+	createVMs(t, client.Compute(), "web", 5, nil)
+	it = services.NewVMIterator(ctx, client.Compute(), services.ListVMsOptions{PageSize: 2})
+	helper.AssertEqual(true, it.Next())
+	helper.AssertEqual(1, provider.CallCount(cloudsdk.OpListVMsWithOptions))
+
+	resumed := services.NewVMIterator(ctx, client.Compute(), services.ListVMsOptions{PageSize: 2, Cursor: it.Cursor()})
+	helper.AssertEqual(true, resumed.NextPage())
+	helper.AssertEqual(2, len(resumed.Page().VMs))
+
+	// Errors stop the iterator
+	provider = mock.New("us-east-1").
+		WithError(cloudsdk.OpListVMsWithOptions, cloudsdk.NewAuthorizationError("mock", "compute", cloudsdk.OpListVMsWithOptions, nil))
+	it = services.NewVMIterator(ctx, provider.Compute(), services.ListVMsOptions{})
+	helper.AssertEqual(false, it.Next())
+	helper.AssertErrorCode(it.Err(), cloudsdk.ErrAuthorization)
+	helper.AssertEqual(false, it.Next())
+	helper.AssertEqual(1, provider.CallCount(cloudsdk.OpListVMsWithOptions))
+
+	// A nil page from middleware that short-circuits the call ends the
+	// iteration
+	client = cloudsdk.New(mock.New("us-east-1"), nil)
+	client.Use(func(next cloudsdk.Handler) cloudsdk.Handler {
+		return func(ctx context.Context, call *cloudsdk.Call) (interface{}, error) {
+			return nil, nil
+		}
+	})
+	it = services.NewVMIterator(ctx, client.Compute(), services.ListVMsOptions{})
+	helper.AssertEqual(false, it.Next())
+	helper.AssertNoError(it.Err())
+	helper.AssertEqual(false, it.NextPage())
+}
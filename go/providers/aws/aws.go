@@ -48,6 +48,7 @@
 //   - WithCredentials(): Set explicit access keys
 //   - WithSessionToken(): Add session token for temporary credentials
 //   - WithDebug(): Enable detailed request/response logging
+//   - WithLogger(): Send structured logs to your own slog.Logger
 //   - WithTimeout(): Set custom API call timeouts
 //   - WithRetryMaxAttempts(): Configure retry behavior
 //
@@ -102,11 +103,13 @@ package aws
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
 	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/compute"
 	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/database"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
 	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/storage"
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -136,8 +139,15 @@ type Config struct {
 
 	// Debug enables detailed logging of AWS API calls.
 	// Useful for troubleshooting authentication and API issues.
+	// When Logger is nil, debug records are written to stderr.
 	Debug bool
 
+	// Logger receives structured records for AWS API calls: requests and
+	// responses at debug level, retries at warn level. Secrets such as
+	// instance user data and database passwords are redacted.
+	// If nil, slog.Default() is used (or a stderr debug logger when Debug is set).
+	Logger *slog.Logger
+
 	// Timeout sets the default timeout for AWS API calls.
 	// If not provided, uses AWS SDK defaults (typically 30 seconds).
 	Timeout time.Duration
@@ -241,12 +251,28 @@ func WithSessionToken(sessionToken string) Option {
 //
 // Security note: Debug logs may contain sensitive information.
 // Only enable in development or when troubleshooting issues.
+// Known secrets (user data, master passwords) are always redacted.
 func WithDebug() Option {
 	return func(c *Config) {
 		c.Debug = true
 	}
 }
 
+// WithLogger sets the slog.Logger that receives AWS API logs.
+// Requests and responses are logged at debug level with the operation and
+// request ID; retries are logged at warn level with the attempt number,
+// duration and error. The handler's level decides what is kept.
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	provider, err := aws.New("us-east-1", aws.WithLogger(logger))
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
 // WithTimeout sets the default timeout for AWS API calls.
 // This applies to all service operations unless overridden at the operation level.
 //
@@ -353,6 +379,10 @@ func New(region string, options ...Option) (*AWSProvider, error) {
 		return nil, err
 	}
 
+	if cfg.Debug && cfg.Logger == nil {
+		cfg.Logger = awslog.DebugLogger()
+	}
+
 	// Create provider (AWS config will be loaded lazily)
 	provider := &AWSProvider{
 		config: cfg,
@@ -534,7 +564,7 @@ func containsSubstring(s, substr string) bool {
 func (p *AWSProvider) Compute() services.Compute {
 	// Note: We don't initialize here to avoid blocking the provider creation.
 	// Initialization happens when service methods are actually called.
-	return compute.New(p.awsConfig)
+	return compute.New(p.awsConfig, compute.WithLogger(p.config.Logger))
 }
 
 // Storage returns the AWS storage service for managing S3 buckets and objects.
@@ -557,7 +587,7 @@ func (p *AWSProvider) Compute() services.Compute {
 // Note: This method performs lazy initialization of AWS credentials.
 // Credential errors will be returned when service methods are called.
 func (p *AWSProvider) Storage() services.Storage {
-	return storage.New(p.awsConfig)
+	return storage.New(p.awsConfig, storage.WithLogger(p.config.Logger))
 }
 
 // Database returns the AWS database service for managing RDS instances.
@@ -581,7 +611,7 @@ func (p *AWSProvider) Storage() services.Storage {
 // Note: This method performs lazy initialization of AWS credentials.
 // Credential errors will be returned when service methods are called.
 func (p *AWSProvider) Database() services.Database {
-	return database.New(p.awsConfig)
+	return database.New(p.awsConfig, database.WithLogger(p.config.Logger))
 }
 
 // Name returns the provider name identifier.
@@ -609,6 +639,37 @@ func (p *AWSProvider) SupportedServices() []cloudsdk.ServiceType {
 	}
 }
 
+// Capabilities reports every operation of the AWS provider's services as
+// supported. The configuration fields listed are accepted by the services
+// types but not yet sent to AWS, so the Client rejects configs that set them
+// rather than creating resources without them.
+//
+// The AWS services plan dry runs themselves. EC2 calls are sent with DryRun
+// set to check the caller's permissions; S3 and RDS calls are only recorded.
+// A services.WithRegion call option sends a single request to another region.
+func (p *AWSProvider) Capabilities() cloudsdk.Capabilities {
+	var operations []string
+	for _, service := range p.SupportedServices() {
+		operations = append(operations, cloudsdk.ServiceOperations(service)...)
+	}
+	return cloudsdk.Capabilities{
+		Operations:     operations,
+		DryRun:         true,
+		RegionOverride: true,
+		UnsupportedFields: map[string][]string{
+			cloudsdk.OpCreateBucket: {
+				"ACL", "StorageClass", "Encryption", "LifecycleRules", "PublicAccessBlock",
+				"NotificationConfig", "CorsRules", "WebsiteConfig", "ReplicationConfig",
+			},
+			cloudsdk.OpCreateDB: {
+				"StorageType", "KmsKeyId", "VpcSecurityGroups", "SubnetGroupName", "MultiAZ",
+				"BackupRetentionPeriod", "BackupWindow", "MaintenanceWindow", "DeletionProtection",
+				"PerformanceInsightsEnabled", "MonitoringInterval", "MonitoringRoleArn", "EnabledCloudwatchLogsExports",
+			},
+		},
+	}
+}
+
 // Connect validates the AWS configuration and credentials.
 // This method can be called to test connectivity before using services.
 // It's optional - services will automatically initialize when first used.
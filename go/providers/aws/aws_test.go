@@ -3,6 +3,7 @@ package aws
 import (
 	"os"
 	"testing"
+	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
@@ -98,6 +99,33 @@ func TestProviderInterface(t *testing.T) {
 	}
 }
 
+func TestProviderCapabilities(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	provider, err := New("us-east-1")
+	helper.AssertNoError(err)
+
+	caps := provider.Capabilities()
+	for _, service := range provider.SupportedServices() {
+		for _, operation := range cloudsdk.ServiceOperations(service) {
+			if !caps.SupportsOperation(operation) {
+				t.Errorf("Expected operation %s to be supported", operation)
+			}
+		}
+	}
+
+	// Fields that aren't sent to AWS are reported as unsupported
+	helper.AssertEqual(false, caps.SupportsField(cloudsdk.OpCreateDB, "MultiAZ"))
+	helper.AssertEqual(false, caps.SupportsField(cloudsdk.OpCreateBucket, "ReplicationConfig.Rules"))
+	helper.AssertEqual(true, caps.SupportsField(cloudsdk.OpCreateDB, "StorageEncrypted"))
+	helper.AssertEqual(true, caps.SupportsField(cloudsdk.OpCreateBucket, "Versioning"))
+	helper.AssertEqual(true, caps.SupportsField(cloudsdk.OpCreateBucket, "Tags"))
+	helper.AssertEqual(true, caps.SupportsField(cloudsdk.OpCreateDB, "Tags"))
+	for _, field := range []string{"Tags", "SubnetID", "AssignPublicIP", "PlacementGroup", "IamInstanceProfile", "Monitoring", "EbsOptimized"} {
+		helper.AssertEqual(true, caps.SupportsField(cloudsdk.OpCreateVM, field))
+	}
+}
+
 func TestServiceInterfaces(t *testing.T) {
 	helper := cloudsdktesting.NewTestHelper(t)
 
@@ -294,3 +322,37 @@ func BenchmarkServiceAccess(b *testing.B) {
 		}
 	})
 }
+
+func TestOpenFromURL(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	provider, err := cloudsdk.OpenProvider("aws://eu-west-1?profile=prod&timeout=45s&retries=5&debug=true")
+	helper.AssertNoError(err)
+
+	awsProvider, ok := provider.(*AWSProvider)
+	if !ok {
+		t.Fatalf("Expected *AWSProvider, got %T", provider)
+	}
+	helper.AssertEqual("eu-west-1", awsProvider.Region())
+	helper.AssertEqual("prod", awsProvider.config.Profile)
+	helper.AssertEqual(45*time.Second, awsProvider.config.Timeout)
+	helper.AssertEqual(5, awsProvider.config.RetryMaxAttempts)
+	helper.AssertEqual(true, awsProvider.config.Debug)
+}
+
+func TestOpenFromURL_InvalidParams(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	invalidURLs := []string{
+		"aws://us-east-1?timeout=soon",
+		"aws://us-east-1?retries=many",
+		"aws://us-east-1?debug=maybe",
+		"aws://us-east-1?secret_key=oops",
+		"aws://",
+	}
+
+	for _, rawURL := range invalidURLs {
+		_, err := cloudsdk.OpenProvider(rawURL)
+		helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awserr"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsidem"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -16,109 +21,24 @@ import (
 	"github.com/aws/smithy-go"
 )
 
-// RetryConfig defines retry behavior for AWS operations
-type RetryConfig struct {
-	MaxAttempts   int
-	InitialDelay  time.Duration
-	MaxDelay      time.Duration
-	BackoffFactor float64
-}
+// RetryConfig defines retry behavior for AWS operations.
+// It is the shared cloudsdk.RetryPolicy.
+type RetryConfig = cloudsdk.RetryPolicy
 
 // DefaultRetryConfig provides sensible defaults for retry behavior
-var DefaultRetryConfig = RetryConfig{
-	MaxAttempts:   3,
-	InitialDelay:  100 * time.Millisecond,
-	MaxDelay:      5 * time.Second,
-	BackoffFactor: 2.0,
-}
-
-// retryWithBackoff executes a function with exponential backoff retry logic
-func retryWithBackoff(ctx context.Context, config RetryConfig, operation func() error) error {
-	var lastErr error
-	delay := config.InitialDelay
-
-	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
-		if attempt > 1 {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
-			}
-		}
-
-		err := operation()
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-		if !isRetryableAWSError(err) {
-			return err
-		}
-
-		if attempt < config.MaxAttempts {
-			delay = time.Duration(float64(delay) * config.BackoffFactor)
-			if delay > config.MaxDelay {
-				delay = config.MaxDelay
-			}
-			log.Printf("AWS Compute: Retrying operation (attempt %d/%d) after %v due to: %v",
-				attempt+1, config.MaxAttempts, delay, err)
-		}
-	}
-
-	return lastErr
-}
-
-// isRetryableAWSError determines if an AWS error should be retried
-func isRetryableAWSError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	// Check for context cancellation/timeout - don't retry
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-		return false
-	}
-
-	// Check for AWS-specific retryable errors
-	var ae smithy.APIError
-	if errors.As(err, &ae) {
-		code := ae.ErrorCode()
-		switch code {
-		case "Throttling", "ThrottlingException", "RequestLimitExceeded":
-			return true
-		case "InternalError", "InternalFailure", "ServiceUnavailable":
-			return true
-		case "RequestTimeout", "RequestTimeoutException":
-			return true
-		}
-	}
+var DefaultRetryConfig = cloudsdk.DefaultRetryPolicy
 
-	// Check error message for common transient issues
-	errMsg := strings.ToLower(err.Error())
-	transientMessages := []string{
-		"connection reset",
-		"connection refused",
-		"timeout",
-		"temporary failure",
-		"service unavailable",
-		"internal error",
-	}
-
-	for _, msg := range transientMessages {
-		if strings.Contains(errMsg, msg) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// wrapAWSError converts AWS errors to CloudError with helpful context
+// wrapAWSError converts AWS errors to CloudError with helpful context, including
+// the request ID, error code, HTTP status and attempt count of the failure
 func wrapAWSError(err error, provider, service, operation string) error {
 	if err == nil {
 		return nil
 	}
+	return awserr.Annotate(classifyAWSError(err, provider, service, operation), err)
+}
+
+// classifyAWSError maps an AWS error to the CloudError code and suggestions for it
+func classifyAWSError(err error, provider, service, operation string) *cloudsdk.CloudError {
 
 	// Handle context errors
 	if errors.Is(err, context.Canceled) {
@@ -139,6 +59,16 @@ func wrapAWSError(err error, provider, service, operation string) error {
 		code := ae.ErrorCode()
 		message := ae.ErrorMessage()
 
+		// An expired or malformed ListVMsWithOptions cursor
+		if code == "InvalidParameterValue" && strings.Contains(strings.ToLower(message), "nexttoken") {
+			return cloudsdk.NewInvalidConfigError(provider, service, "Cursor", "invalid or expired cursor").
+				WithCause(err).
+				WithSuggestions(
+					"Pass the NextCursor of the previous page unchanged, with the same filters",
+					"Start again from the first page with an empty cursor",
+				)
+		}
+
 		switch code {
 		case "UnauthorizedOperation", "AccessDenied":
 			return cloudsdk.NewAuthorizationError(provider, service, operation, err).
@@ -165,7 +95,42 @@ func wrapAWSError(err error, provider, service, operation string) error {
 					"Ensure the instance hasn't been terminated",
 				)
 
-		case "InvalidAMIID.NotFound":
+		case "InvalidVolume.NotFound":
+			return cloudsdk.NewResourceNotFoundError(provider, service, "volume", extractVolumeIDFromError(message)).
+				WithSuggestions(
+					"Verify the volume ID is correct",
+					"Check that the volume exists in the current region",
+					"Ensure the volume hasn't been deleted",
+				)
+
+		case "InvalidSnapshot.NotFound":
+			return cloudsdk.NewInvalidConfigError(provider, service, "SnapshotID", "Snapshot not found").
+				WithCause(err).
+				WithSuggestions(
+					"Verify the snapshot ID is correct and exists in your region",
+					"Check that the snapshot is shared with your account",
+				)
+
+		case "VolumeInUse", "IncorrectModificationState", "InvalidVolume.ZoneMismatch", "InvalidAttachment.NotFound":
+			return cloudsdk.NewInvalidStateError(provider, service, operation, extractVolumeIDFromError(message), "").
+				WithCause(err).
+				WithSuggestions(
+					"Use Volumes().Get to check the volume's state and attachments",
+					"Volumes can only be attached to instances in their availability zone",
+					"Wait for an earlier attach, detach or modification to finish before retrying",
+				)
+
+		case "InvalidAMIID.NotFound", "InvalidAMIID.Unavailable":
+			// Looking up or deregistering a missing image, as opposed to
+			// launching a VM from one
+			if operation == "GetImage" || operation == "DeregisterImage" {
+				return cloudsdk.NewResourceNotFoundError(provider, service, "image", extractImageIDFromError(message)).
+					WithSuggestions(
+						"Verify the image ID is correct",
+						"Check that the image exists in the current region and is shared with your account",
+						"Use Images().Latest to look the current image up by name",
+					)
+			}
 			return cloudsdk.NewInvalidConfigError(provider, service, "ImageID", "AMI not found").
 				WithSuggestions(
 					"Verify the AMI ID is correct and exists in your region",
@@ -173,6 +138,62 @@ func wrapAWSError(err error, provider, service, operation string) error {
 					"Ensure the AMI is compatible with the instance type",
 				)
 
+		case "InvalidAMIName.Duplicate":
+			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "An image with this name already exists", provider, service, operation).
+				WithCause(err).
+				WithSuggestions(
+					"Choose a different image name, e.g. with a date suffix",
+					"Deregister the existing image first",
+				)
+
+		case "InvalidKeyPair.Duplicate":
+			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "A key pair with this name already exists", provider, service, operation).
+				WithCause(err).
+				WithSuggestions(
+					"Choose a different key pair name",
+					"Delete the existing key pair first",
+				)
+
+		case "InvalidKey.Format", "InvalidKeyPair.Format":
+			return cloudsdk.NewInvalidConfigError(provider, service, "PublicKey", "Public key is not in a supported format").
+				WithSuggestions(
+					"Pass the contents of a .pub file, e.g. \"ssh-ed25519 AAAA... user@host\"",
+					"Only ed25519 and RSA keys are supported",
+				)
+
+		case "InvalidGroup.NotFound", "InvalidGroupId.Malformed":
+			// Managing a missing group, as opposed to launching a VM in one
+			switch operation {
+			case "GetSecurityGroup", "AddSecurityGroupRules", "RevokeSecurityGroupRules", "DeleteSecurityGroup":
+				return cloudsdk.NewResourceNotFoundError(provider, service, "security group", extractSecurityGroupIDFromError(message)).
+					WithSuggestions(
+						"Verify the security group ID is correct",
+						"Check that the security group exists in the current region",
+						"Rules can only name groups in the same VPC",
+					)
+			}
+			return cloudsdk.NewInvalidConfigError(provider, service, "SecurityGroups", "Security group not found").
+				WithCause(err).
+				WithSuggestions(
+					"Verify the security group IDs are correct",
+					"Security groups must be in the same VPC as the subnet",
+				)
+
+		case "InvalidGroup.Duplicate", "InvalidPermission.Duplicate":
+			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, message, provider, service, operation).
+				WithCause(err).
+				WithSuggestions(
+					"Use SecurityGroups().List or Get to find the existing group or rule",
+					"Choose a different group name, or leave out rules the group already has",
+				)
+
+		case "InvalidPermission.NotFound":
+			return cloudsdk.NewResourceNotFoundError(provider, service, "security group rule", extractSecurityGroupIDFromError(message)).
+				WithSuggestions(
+					"Use SecurityGroups().Get to check the group's current rules",
+					"Rules only match when protocol, ports and CIDR or group are all equal",
+				)
+
 		case "InvalidInstanceType":
 			return cloudsdk.NewInvalidConfigError(provider, service, "InstanceType", "Invalid instance type").
 				WithSuggestions(
@@ -190,7 +211,7 @@ func wrapAWSError(err error, provider, service, operation string) error {
 				)
 
 		case "Throttling", "RequestLimitExceeded":
-			return cloudsdk.NewRateLimitError(provider, service, operation, 0).
+			return cloudsdk.NewRateLimitError(provider, service, operation, awsretry.RetryAfter(err)).
 				WithCause(err).
 				WithSuggestions(
 					"Reduce the frequency of API calls",
@@ -198,6 +219,14 @@ func wrapAWSError(err error, provider, service, operation string) error {
 					"Consider using batch operations where available",
 				)
 
+		case "IdempotentParameterMismatch":
+			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "Idempotency key was already used with a different configuration", provider, service, operation).
+				WithCause(err).
+				WithSuggestions(
+					"Use a new idempotency key for a different configuration",
+					"Retry with exactly the same configuration as the first call",
+				)
+
 		case "InsufficientInstanceCapacity":
 			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "Insufficient capacity for instance type", provider, service, operation).
 				WithCause(err).
@@ -207,15 +236,37 @@ func wrapAWSError(err error, provider, service, operation string) error {
 					"Wait and retry later when capacity becomes available",
 				)
 
-		case "InstanceLimitExceeded":
-			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "Instance limit exceeded", provider, service, operation).
+		case "InstanceLimitExceeded", "VcpuLimitExceeded", "MaxSpotInstanceCountExceeded", "AddressLimitExceeded", "VolumeLimitExceeded", "ResourceLimitExceeded":
+			return cloudsdk.NewQuotaExceededError(provider, service, operation, message).
 				WithCause(err).
 				WithSuggestions(
-					"Request a limit increase from AWS Support",
+					"Request a limit increase through AWS Service Quotas",
 					"Terminate unused instances to free up capacity",
 					"Use different instance types with available capacity",
 				)
 
+		case "IncorrectInstanceState", "IncorrectState", "IncorrectSpotRequestState":
+			return cloudsdk.NewInvalidStateError(provider, service, operation, extractInstanceIDFromError(message), "").
+				WithCause(err).
+				WithSuggestions(
+					"Use GetVM to check the instance state",
+					"Wait for pending or stopping instances to settle before retrying",
+				)
+
+		case "OperationNotPermitted":
+			return cloudsdk.NewPreconditionFailedError(provider, service, operation, message).
+				WithCause(err).
+				WithSuggestions(
+					"Check the instance's termination and stop protection attributes",
+				)
+
+		case "DependencyViolation":
+			return cloudsdk.NewDependencyViolationError(provider, service, operation, message).
+				WithCause(err).
+				WithSuggestions(
+					"Detach network interfaces, volumes or instances that use the resource",
+				)
+
 		default:
 			// Generic AWS error
 			return cloudsdk.NewCloudError(cloudsdk.ErrProviderError, fmt.Sprintf("AWS error: %s", message), provider, service, operation).
@@ -250,22 +301,119 @@ func extractInstanceIDFromError(message string) string {
 	return "unknown"
 }
 
-// logRequest logs AWS API requests for debugging (when debug is enabled)
-func logRequest(operation string, input interface{}, debug bool) {
-	if debug {
-		log.Printf("AWS Compute: %s request: %+v", operation, input)
+// extractVolumeIDFromError attempts to extract a volume ID from error messages
+func extractVolumeIDFromError(message string) string {
+	for _, part := range strings.Fields(message) {
+		if part = strings.Trim(part, "'\",.:"); strings.HasPrefix(part, "vol-") {
+			return part
+		}
 	}
+	return "unknown"
 }
 
-// logResponse logs AWS API responses for debugging (when debug is enabled)
-func logResponse(operation string, output interface{}, err error, debug bool) {
-	if debug {
-		if err != nil {
-			log.Printf("AWS Compute: %s error: %v", operation, err)
-		} else {
-			log.Printf("AWS Compute: %s response: %+v", operation, output)
+// extractImageIDFromError attempts to extract an image ID from error messages
+func extractImageIDFromError(message string) string {
+	for _, part := range strings.Fields(message) {
+		if part = strings.Trim(part, "'\"[],.:"); strings.HasPrefix(part, "ami-") {
+			return part
 		}
 	}
+	return "unknown"
+}
+
+// extractSecurityGroupIDFromError attempts to extract a security group ID from error messages
+func extractSecurityGroupIDFromError(message string) string {
+	for _, part := range strings.Fields(message) {
+		if part = strings.Trim(part, "'\"[],.:"); strings.HasPrefix(part, "sg-") {
+			return part
+		}
+	}
+	return "unknown"
+}
+
+// ec2Options returns the per-request overrides for the call's
+// services.CallOptions: a Region option sends the request to that region
+func ec2Options(ctx context.Context) []func(*ec2.Options) {
+	region := services.CallOptionsFromContext(ctx).Region
+	if region == "" {
+		return nil
+	}
+	return []func(*ec2.Options){func(o *ec2.Options) {
+		o.Region = region
+	}}
+}
+
+// logRequest logs EC2 API requests at debug level, with secrets redacted
+func logRequest(ctx context.Context, logger *slog.Logger, operation string, input interface{}) {
+	awslog.Request(ctx, logger, "compute", operation, redactInput(input))
+}
+
+// redactInput returns a copy of an EC2 input with secrets masked for logging.
+// User data often carries bootstrap credentials, so it is never logged.
+func redactInput(input interface{}) interface{} {
+	switch in := input.(type) {
+	case *ec2.RunInstancesInput:
+		if in != nil && in.UserData != nil {
+			masked := *in
+			masked.UserData = aws.String(awslog.Redacted)
+			return &masked
+		}
+	case *ec2.RequestSpotInstancesInput:
+		if in != nil && in.LaunchSpecification != nil && in.LaunchSpecification.UserData != nil {
+			spec := *in.LaunchSpecification
+			spec.UserData = aws.String(awslog.Redacted)
+			masked := *in
+			masked.LaunchSpecification = &spec
+			return &masked
+		}
+	}
+	return input
+}
+
+// logResponse logs EC2 API responses and errors at debug level, with secrets
+// redacted
+func logResponse(ctx context.Context, logger *slog.Logger, operation string, output interface{}, err error) {
+	awslog.Response(ctx, logger, "compute", operation, redactOutput(output), err)
+}
+
+// redactOutput returns a copy of an EC2 output with secrets masked for
+// logging. The private key of a new key pair is never logged.
+func redactOutput(output interface{}) interface{} {
+	if out, ok := output.(*ec2.CreateKeyPairOutput); ok && out != nil && out.KeyMaterial != nil {
+		masked := *out
+		masked.KeyMaterial = aws.String(awslog.Redacted)
+		return &masked
+	}
+	return output
+}
+
+// planEC2Call handles a mutating EC2 call in a dry run. send makes the call
+// with DryRun set, which checks the caller's permissions without changing
+// anything; EC2 answers DryRunOperation when the call would have succeeded.
+// The call is then recorded in the dry run's plan.
+func planEC2Call(ctx context.Context, logger *slog.Logger, operation, api string, input interface{}, send func(ctx context.Context) error) error {
+	logRequest(ctx, logger, api, input)
+	err := send(ctx)
+	logResponse(ctx, logger, api, nil, err)
+
+	var ae smithy.APIError
+	if err != nil && !(errors.As(err, &ae) && ae.ErrorCode() == "DryRunOperation") {
+		return wrapAWSError(err, "aws", "compute", operation)
+	}
+	recordPlannedCall(ctx, operation, api, input, true)
+	return nil
+}
+
+// recordPlannedCall adds an EC2 call to the dry run's plan, with secrets redacted.
+func recordPlannedCall(ctx context.Context, operation, api string, input interface{}, permissionChecked bool) {
+	cloudsdk.RecordPlannedCall(ctx, cloudsdk.PlannedCall{
+		Provider:          "aws",
+		Service:           cloudsdk.ServiceCompute,
+		Operation:         operation,
+		API:               api,
+		Input:             redactInput(input),
+		PermissionChecked: permissionChecked,
+	})
 }
 
 // EC2ClientInterface defines methods we need from EC2 client for testing
@@ -275,6 +423,8 @@ type EC2ClientInterface interface {
 	StartInstances(ctx context.Context, input *ec2.StartInstancesInput, opts ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
 	StopInstances(ctx context.Context, input *ec2.StopInstancesInput, opts ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
 	TerminateInstances(ctx context.Context, input *ec2.TerminateInstancesInput, opts ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error)
+	RebootInstances(ctx context.Context, input *ec2.RebootInstancesInput, opts ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error)
+	ModifyInstanceAttribute(ctx context.Context, input *ec2.ModifyInstanceAttributeInput, opts ...func(*ec2.Options)) (*ec2.ModifyInstanceAttributeOutput, error)
 	CreateTags(ctx context.Context, input *ec2.CreateTagsInput, opts ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
 	DescribeInstanceTypes(ctx context.Context, input *ec2.DescribeInstanceTypesInput, opts ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error)
 	DescribePlacementGroups(ctx context.Context, input *ec2.DescribePlacementGroupsInput, opts ...func(*ec2.Options)) (*ec2.DescribePlacementGroupsOutput, error)
@@ -283,6 +433,27 @@ type EC2ClientInterface interface {
 	RequestSpotInstances(ctx context.Context, input *ec2.RequestSpotInstancesInput, opts ...func(*ec2.Options)) (*ec2.RequestSpotInstancesOutput, error)
 	DescribeSpotInstanceRequests(ctx context.Context, input *ec2.DescribeSpotInstanceRequestsInput, opts ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error)
 	CancelSpotInstanceRequests(ctx context.Context, input *ec2.CancelSpotInstanceRequestsInput, opts ...func(*ec2.Options)) (*ec2.CancelSpotInstanceRequestsOutput, error)
+	CreateVolume(ctx context.Context, input *ec2.CreateVolumeInput, opts ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error)
+	DescribeVolumes(ctx context.Context, input *ec2.DescribeVolumesInput, opts ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	AttachVolume(ctx context.Context, input *ec2.AttachVolumeInput, opts ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error)
+	DetachVolume(ctx context.Context, input *ec2.DetachVolumeInput, opts ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error)
+	ModifyVolume(ctx context.Context, input *ec2.ModifyVolumeInput, opts ...func(*ec2.Options)) (*ec2.ModifyVolumeOutput, error)
+	CreateSnapshot(ctx context.Context, input *ec2.CreateSnapshotInput, opts ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error)
+	DeleteVolume(ctx context.Context, input *ec2.DeleteVolumeInput, opts ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)
+	DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, opts ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
+	CreateImage(ctx context.Context, input *ec2.CreateImageInput, opts ...func(*ec2.Options)) (*ec2.CreateImageOutput, error)
+	DeregisterImage(ctx context.Context, input *ec2.DeregisterImageInput, opts ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error)
+	CreateKeyPair(ctx context.Context, input *ec2.CreateKeyPairInput, opts ...func(*ec2.Options)) (*ec2.CreateKeyPairOutput, error)
+	ImportKeyPair(ctx context.Context, input *ec2.ImportKeyPairInput, opts ...func(*ec2.Options)) (*ec2.ImportKeyPairOutput, error)
+	DescribeKeyPairs(ctx context.Context, input *ec2.DescribeKeyPairsInput, opts ...func(*ec2.Options)) (*ec2.DescribeKeyPairsOutput, error)
+	DeleteKeyPair(ctx context.Context, input *ec2.DeleteKeyPairInput, opts ...func(*ec2.Options)) (*ec2.DeleteKeyPairOutput, error)
+	CreateSecurityGroup(ctx context.Context, input *ec2.CreateSecurityGroupInput, opts ...func(*ec2.Options)) (*ec2.CreateSecurityGroupOutput, error)
+	DescribeSecurityGroups(ctx context.Context, input *ec2.DescribeSecurityGroupsInput, opts ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	AuthorizeSecurityGroupIngress(ctx context.Context, input *ec2.AuthorizeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
+	AuthorizeSecurityGroupEgress(ctx context.Context, input *ec2.AuthorizeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupEgressOutput, error)
+	RevokeSecurityGroupIngress(ctx context.Context, input *ec2.RevokeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	RevokeSecurityGroupEgress(ctx context.Context, input *ec2.RevokeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error)
+	DeleteSecurityGroup(ctx context.Context, input *ec2.DeleteSecurityGroupInput, opts ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)
 }
 
 // AWSCompute implements the Compute interface for AWS
@@ -291,65 +462,86 @@ type AWSCompute struct {
 	instanceTypesSvc   *InstanceTypesServiceImpl
 	placementGroupsSvc *PlacementGroupsServiceImpl
 	spotInstancesSvc   *SpotInstancesServiceImpl
-	debug              bool
+	volumesSvc         *VolumesServiceImpl
+	imagesSvc          *ImagesServiceImpl
+	keyPairsSvc        *KeyPairsServiceImpl
+	securityGroupsSvc  *SecurityGroupsServiceImpl
+	logger             *slog.Logger
 	retryConfig        RetryConfig
+	waitOptions        []services.WaitOption
 }
 
-// New creates a new AWSCompute instance with real AWS client
-func New(cfg aws.Config) services.Compute {
-	client := ec2.NewFromConfig(cfg)
-	return &AWSCompute{
-		client:             client,
-		instanceTypesSvc:   &InstanceTypesServiceImpl{client: client, debug: false},
-		placementGroupsSvc: &PlacementGroupsServiceImpl{client: client, debug: false},
-		spotInstancesSvc:   &SpotInstancesServiceImpl{client: client, debug: false},
-		debug:              false,
-		retryConfig:        DefaultRetryConfig,
+// Option configures an AWSCompute created by New or NewWithClient
+type Option func(*AWSCompute)
+
+// WithLogger sets the logger for EC2 requests, responses and retries, shared
+// with the InstanceTypes, PlacementGroups, SpotInstances, Volumes, Images,
+// KeyPairs and SecurityGroups sub-services.
+// Requests and responses are logged at debug level with user data redacted.
+// A nil logger means slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *AWSCompute) {
+		c.logger = logger
 	}
 }
 
-// NewWithClient creates a new AWSCompute instance with custom client (for testing)
-func NewWithClient(client EC2ClientInterface) services.Compute {
-	return &AWSCompute{
-		client:             client,
-		instanceTypesSvc:   &InstanceTypesServiceImpl{client: client, debug: false},
-		placementGroupsSvc: &PlacementGroupsServiceImpl{client: client, debug: false},
-		spotInstancesSvc:   &SpotInstancesServiceImpl{client: client, debug: false},
-		debug:              false,
-		retryConfig:        DefaultRetryConfig,
+// WithWaitOptions sets how ResizeVM polls while it waits for an instance to
+// stop and start again.
+func WithWaitOptions(opts ...services.WaitOption) Option {
+	return func(c *AWSCompute) {
+		c.waitOptions = opts
 	}
 }
 
-// NewWithOptions creates a new AWSCompute instance with custom options
-func NewWithOptions(cfg aws.Config, debug bool, retryConfig *RetryConfig) services.Compute {
-	client := ec2.NewFromConfig(cfg)
+// New creates a new AWSCompute instance with real AWS client
+func New(cfg aws.Config, opts ...Option) services.Compute {
+	return newAWSCompute(ec2.NewFromConfig(cfg), DefaultRetryConfig, opts)
+}
 
+// NewWithClient creates a new AWSCompute instance with custom client (for testing)
+func NewWithClient(client EC2ClientInterface, opts ...Option) services.Compute {
+	return newAWSCompute(client, DefaultRetryConfig, opts)
+}
+
+// NewWithOptions creates a new AWSCompute instance with custom options.
+// When debug is true, everything is logged to stderr, including requests and
+// responses.
+func NewWithOptions(cfg aws.Config, debug bool, retryConfig *RetryConfig) services.Compute {
 	finalRetryConfig := DefaultRetryConfig
 	if retryConfig != nil {
 		finalRetryConfig = *retryConfig
 	}
 
-	return &AWSCompute{
-		client:             client,
-		instanceTypesSvc:   &InstanceTypesServiceImpl{client: client, debug: debug},
-		placementGroupsSvc: &PlacementGroupsServiceImpl{client: client, debug: debug},
-		spotInstancesSvc:   &SpotInstancesServiceImpl{client: client, debug: debug},
-		debug:              debug,
-		retryConfig:        finalRetryConfig,
+	var opts []Option
+	if debug {
+		opts = append(opts, WithLogger(awslog.DebugLogger()))
 	}
+	return newAWSCompute(ec2.NewFromConfig(cfg), finalRetryConfig, opts)
+}
+
+func newAWSCompute(client EC2ClientInterface, retryConfig RetryConfig, opts []Option) *AWSCompute {
+	c := &AWSCompute{
+		client:      client,
+		retryConfig: retryConfig,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.instanceTypesSvc = &InstanceTypesServiceImpl{client: client, logger: c.logger}
+	c.placementGroupsSvc = &PlacementGroupsServiceImpl{client: client, logger: c.logger}
+	c.spotInstancesSvc = &SpotInstancesServiceImpl{client: client, logger: c.logger}
+	c.volumesSvc = &VolumesServiceImpl{client: client, logger: c.logger, retryConfig: retryConfig}
+	c.imagesSvc = &ImagesServiceImpl{client: client, logger: c.logger, retryConfig: retryConfig}
+	c.keyPairsSvc = &KeyPairsServiceImpl{client: client, logger: c.logger, retryConfig: retryConfig}
+	c.securityGroupsSvc = &SecurityGroupsServiceImpl{client: client, logger: c.logger, retryConfig: retryConfig}
+	return c
 }
 
 // CreateVM creates a new virtual machine
 func (c *AWSCompute) CreateVM(ctx context.Context, config *services.VMConfig) (*services.VM, error) {
 	// Validate input configuration
-	if config == nil {
-		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "config", "configuration cannot be nil")
-	}
-	if config.ImageID == "" {
-		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "ImageID", "image ID is required")
-	}
-	if config.InstanceType == "" {
-		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "InstanceType", "instance type is required")
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
 	}
 
 	input := &ec2.RunInstancesInput{
@@ -359,30 +551,34 @@ func (c *AWSCompute) CreateVM(ctx context.Context, config *services.VMConfig) (*
 		MaxCount:     aws.Int32(1),
 	}
 
-	// Add optional parameters
-	if config.KeyName != "" {
-		input.KeyName = aws.String(config.KeyName)
-	}
-	if config.UserData != "" {
-		input.UserData = aws.String(config.UserData)
-	}
-	if len(config.SecurityGroups) > 0 {
-		input.SecurityGroupIds = make([]string, len(config.SecurityGroups))
-		copy(input.SecurityGroupIds, config.SecurityGroups)
+	applyVMConfig(input, config)
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, c.logger, "CreateVM", "RunInstances", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := c.client.RunInstances(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
 	}
 
-	logRequest("RunInstances", input, c.debug)
+	// Every attempt sends the same client token, so a retry after a
+	// RunInstances call that succeeded but timed out returns that instance
+	// instead of launching another one
+	input.ClientToken = aws.String(awsidem.Token(config.IdempotencyKey))
+
+	logRequest(ctx, c.logger, "RunInstances", input)
 
 	var resp *ec2.RunInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, c.retryConfig, func() error {
-		resp, err = c.client.RunInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "RunInstances", func(ctx context.Context) error {
+		resp, err = c.client.RunInstances(ctx, input, ec2Options(ctx)...)
 		return err
 	})
 
-	logResponse("RunInstances", resp, retryErr, c.debug)
+	logResponse(ctx, c.logger, "RunInstances", resp, retryErr)
 
 	if retryErr != nil {
 		return nil, wrapAWSError(retryErr, "aws", "compute", "CreateVM")
@@ -397,145 +593,250 @@ func (c *AWSCompute) CreateVM(ctx context.Context, config *services.VMConfig) (*
 			)
 	}
 
-	inst := resp.Instances[0]
+	vm := vmFromInstance(resp.Instances[0])
+	if vm.Name == "" {
+		vm.Name = config.Name
+	}
+	if len(vm.Tags) == 0 {
+		// RunInstances may not echo the tags it applied
+		vm.Tags = vmTags(config)
+	}
+	return vm, nil
+}
 
-	// Create VM response with proper error handling for optional fields
-	vm := &services.VM{
-		ID:    aws.ToString(inst.InstanceId),
-		Name:  config.Name, // AWS doesn't set name here, but we can tag later
-		State: string(inst.State.Name),
+// applyVMConfig sets the optional fields of config on input.
+func applyVMConfig(input *ec2.RunInstancesInput, config *services.VMConfig) {
+	if config.KeyName != "" {
+		input.KeyName = aws.String(config.KeyName)
+	}
+	if config.UserData != "" {
+		input.UserData = aws.String(config.UserData)
 	}
 
-	// Safely handle optional fields
-	if inst.PublicIpAddress != nil {
-		vm.PublicIP = aws.ToString(inst.PublicIpAddress)
+	// A public IP can only be requested on a network interface, which then
+	// also carries the subnet and security groups
+	if config.AssignPublicIP != nil {
+		nic := types.InstanceNetworkInterfaceSpecification{
+			DeviceIndex:              aws.Int32(0),
+			AssociatePublicIpAddress: aws.Bool(*config.AssignPublicIP),
+			DeleteOnTermination:      aws.Bool(true),
+		}
+		if config.SubnetID != "" {
+			nic.SubnetId = aws.String(config.SubnetID)
+		}
+		if len(config.SecurityGroups) > 0 {
+			nic.Groups = append([]string(nil), config.SecurityGroups...)
+		}
+		input.NetworkInterfaces = []types.InstanceNetworkInterfaceSpecification{nic}
+	} else {
+		if config.SubnetID != "" {
+			input.SubnetId = aws.String(config.SubnetID)
+		}
+		if len(config.SecurityGroups) > 0 {
+			input.SecurityGroupIds = append([]string(nil), config.SecurityGroups...)
+		}
 	}
-	if inst.PrivateIpAddress != nil {
-		vm.PrivateIP = aws.ToString(inst.PrivateIpAddress)
+
+	if config.PlacementGroup != "" {
+		input.Placement = &types.Placement{GroupName: aws.String(config.PlacementGroup)}
 	}
-	if inst.LaunchTime != nil {
-		vm.LaunchTime = inst.LaunchTime.String()
+	if config.IamInstanceProfile != "" {
+		if strings.HasPrefix(config.IamInstanceProfile, "arn:") {
+			input.IamInstanceProfile = &types.IamInstanceProfileSpecification{Arn: aws.String(config.IamInstanceProfile)}
+		} else {
+			input.IamInstanceProfile = &types.IamInstanceProfileSpecification{Name: aws.String(config.IamInstanceProfile)}
+		}
+	}
+	if config.Monitoring != nil {
+		input.Monitoring = &types.RunInstancesMonitoringEnabled{Enabled: aws.Bool(*config.Monitoring)}
+	}
+	if config.EbsOptimized != nil {
+		input.EbsOptimized = aws.Bool(*config.EbsOptimized)
 	}
 
-	// Add name tag if specified
-	if config.Name != "" {
-		tagErr := c.addNameTag(ctx, aws.ToString(inst.InstanceId), config.Name)
-		if tagErr != nil {
-			log.Printf("AWS Compute: Warning - failed to add name tag to instance %s: %v",
-				aws.ToString(inst.InstanceId), tagErr)
+	// Tags are applied at launch, so the instance is never untagged
+	if tags := ec2Tags(vmTags(config)); len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{
+			{ResourceType: types.ResourceTypeInstance, Tags: tags},
+			{ResourceType: types.ResourceTypeVolume, Tags: tags},
 		}
 	}
+}
 
-	return vm, nil
+// ec2Tags converts tags to EC2 tags, sorted by key.
+func ec2Tags(tags map[string]string) []types.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	converted := make([]types.Tag, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		converted = append(converted, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return converted
 }
 
-// addNameTag adds a Name tag to an EC2 instance
-func (c *AWSCompute) addNameTag(ctx context.Context, instanceID, name string) error {
-	input := &ec2.CreateTagsInput{
-		Resources: []string{instanceID},
-		Tags: []types.Tag{
-			{
-				Key:   aws.String("Name"),
-				Value: aws.String(name),
-			},
-		},
+// tagMap converts EC2 tags to a map, or nil if there are none.
+func tagMap(tags []types.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	converted := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		converted[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
+	return converted
+}
 
-	_, err := c.client.CreateTags(ctx, input)
-	return err
+// vmTags returns the tags of a VM created from config: its Tags, plus a Name
+// tag unless Tags already has one.
+func vmTags(config *services.VMConfig) map[string]string {
+	tags := make(map[string]string, len(config.Tags)+1)
+	if config.Name != "" {
+		tags["Name"] = config.Name
+	}
+	for key, value := range config.Tags {
+		tags[key] = value
+	}
+	return tags
+}
+
+// sortedKeys returns the keys of m in order, so that requests built from maps
+// are the same from one call to the next.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
-// ListVMs lists all virtual machines
+// ListVMs lists all virtual machines, following NextToken across every page
 func (c *AWSCompute) ListVMs(ctx context.Context) ([]*services.VM, error) {
 	input := &ec2.DescribeInstancesInput{}
 
-	logRequest("DescribeInstances", input, c.debug)
+	vms := []*services.VM{}
+	for {
+		resp, err := c.describeInstances(ctx, input, "ListVMs")
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resp.Reservations {
+			for _, inst := range res.Instances {
+				vms = append(vms, vmFromInstance(inst))
+			}
+		}
+		if aws.ToString(resp.NextToken) == "" {
+			return vms, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
 
-	var resp *ec2.DescribeInstancesOutput
-	var err error
+// EC2 accepts between minVMPageSize and maxVMPageSize results per
+// DescribeInstances page.
+const (
+	minVMPageSize = 5
+	maxVMPageSize = 1000
+)
 
-	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, c.retryConfig, func() error {
-		resp, err = c.client.DescribeInstances(ctx, input)
-		return err
-	})
+// nonTerminatedStates are the instance states listed by ListVMsWithOptions
+// when no states are given.
+var nonTerminatedStates = []string{"pending", "running", "shutting-down", "stopping", "stopped"}
 
-	logResponse("DescribeInstances", resp, retryErr, c.debug)
+// ListVMsWithOptions lists one page of virtual machines, filtered by EC2 so
+// that only matching instances are transferred. The cursor is EC2's NextToken.
+func (c *AWSCompute) ListVMsWithOptions(ctx context.Context, opts *services.ListVMsOptions) (*services.VMPage, error) {
+	if opts == nil {
+		opts = &services.ListVMsOptions{}
+	}
+	if err := cloudsdk.ValidateConfig("aws", "compute", opts); err != nil {
+		return nil, err
+	}
 
-	if retryErr != nil {
-		return nil, wrapAWSError(retryErr, "aws", "compute", "ListVMs")
+	input := &ec2.DescribeInstancesInput{
+		Filters:    vmFilters(opts),
+		MaxResults: aws.Int32(maxVMPageSize),
+	}
+	if opts.PageSize > 0 {
+		input.MaxResults = aws.Int32(max(opts.PageSize, minVMPageSize))
+	}
+	if opts.Cursor != "" {
+		input.NextToken = aws.String(opts.Cursor)
+	}
+
+	resp, err := c.describeInstances(ctx, input, "ListVMsWithOptions")
+	if err != nil {
+		return nil, err
 	}
 
-	var vms []*services.VM
+	page := &services.VMPage{
+		VMs:        []*services.VM{},
+		NextCursor: aws.ToString(resp.NextToken),
+	}
 	for _, res := range resp.Reservations {
 		for _, inst := range res.Instances {
-			vm := &services.VM{
-				ID:    aws.ToString(inst.InstanceId),
-				State: string(inst.State.Name),
-			}
-
-			// Safely handle optional fields
-			if inst.PublicIpAddress != nil {
-				vm.PublicIP = aws.ToString(inst.PublicIpAddress)
-			}
-			if inst.PrivateIpAddress != nil {
-				vm.PrivateIP = aws.ToString(inst.PrivateIpAddress)
-			}
-			if inst.LaunchTime != nil {
-				vm.LaunchTime = inst.LaunchTime.String()
-			}
-
-			// Get name from tags
-			for _, tag := range inst.Tags {
-				if aws.ToString(tag.Key) == "Name" {
-					vm.Name = aws.ToString(tag.Value)
-					break
-				}
-			}
-
-			vms = append(vms, vm)
+			page.VMs = append(page.VMs, vmFromInstance(inst))
 		}
 	}
-
-	return vms, nil
+	return page, nil
 }
 
-// GetVM gets a specific virtual machine by ID
-func (c *AWSCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
-	// Validate input
-	if id == "" {
-		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "id", "instance ID cannot be empty")
+// vmFilters translates the filters of opts to DescribeInstances filters.
+func vmFilters(opts *services.ListVMsOptions) []types.Filter {
+	states := opts.States
+	if len(states) == 0 {
+		states = nonTerminatedStates
 	}
+	filters := []types.Filter{{Name: aws.String("instance-state-name"), Values: states}}
 
-	input := &ec2.DescribeInstancesInput{
-		InstanceIds: []string{id},
+	if opts.NamePrefix != "" {
+		filters = append(filters, types.Filter{Name: aws.String("tag:Name"), Values: []string{opts.NamePrefix + "*"}})
+	}
+	if len(opts.InstanceTypes) > 0 {
+		filters = append(filters, types.Filter{Name: aws.String("instance-type"), Values: opts.InstanceTypes})
+	}
+	if len(opts.SubnetIDs) > 0 {
+		filters = append(filters, types.Filter{Name: aws.String("subnet-id"), Values: opts.SubnetIDs})
 	}
 
-	logRequest("DescribeInstances", input, c.debug)
+	for _, key := range sortedKeys(opts.Tags) {
+		filters = append(filters, types.Filter{Name: aws.String("tag:" + key), Values: []string{opts.Tags[key]}})
+	}
+	return filters
+}
+
+// describeInstances makes one DescribeInstances call with retries, reporting
+// errors as operation.
+func (c *AWSCompute) describeInstances(ctx context.Context, input *ec2.DescribeInstancesInput, operation string) (*ec2.DescribeInstancesOutput, error) {
+	logRequest(ctx, c.logger, "DescribeInstances", input)
 
 	var resp *ec2.DescribeInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, c.retryConfig, func() error {
-		resp, err = c.client.DescribeInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "DescribeInstances", func(ctx context.Context) error {
+		resp, err = c.client.DescribeInstances(ctx, input, ec2Options(ctx)...)
 		return err
 	})
 
-	logResponse("DescribeInstances", resp, retryErr, c.debug)
+	logResponse(ctx, c.logger, "DescribeInstances", resp, retryErr)
 
 	if retryErr != nil {
-		return nil, wrapAWSError(retryErr, "aws", "compute", "GetVM")
-	}
-
-	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
-		return nil, cloudsdk.NewResourceNotFoundError("aws", "compute", "instance", id)
+		return nil, wrapAWSError(retryErr, "aws", "compute", operation)
 	}
+	return resp, nil
+}
 
-	inst := resp.Reservations[0].Instances[0]
+// vmFromInstance converts an EC2 instance to a VM.
+func vmFromInstance(inst types.Instance) *services.VM {
 	vm := &services.VM{
-		ID:    aws.ToString(inst.InstanceId),
-		State: string(inst.State.Name),
+		ID:           aws.ToString(inst.InstanceId),
+		InstanceType: string(inst.InstanceType),
+		SubnetID:     aws.ToString(inst.SubnetId),
+	}
+	if inst.State != nil {
+		vm.State = string(inst.State.Name)
 	}
 
 	// Safely handle optional fields
@@ -548,16 +849,66 @@ func (c *AWSCompute) GetVM(ctx context.Context, id string) (*services.VM, error)
 	if inst.LaunchTime != nil {
 		vm.LaunchTime = inst.LaunchTime.String()
 	}
+	if inst.KeyName != nil {
+		vm.KeyName = aws.ToString(inst.KeyName)
+	}
+	for _, group := range inst.SecurityGroups {
+		vm.SecurityGroups = append(vm.SecurityGroups, aws.ToString(group.GroupId))
+	}
+	if inst.Placement != nil {
+		vm.PlacementGroup = aws.ToString(inst.Placement.GroupName)
+	}
+	if inst.IamInstanceProfile != nil {
+		vm.IamInstanceProfile = aws.ToString(inst.IamInstanceProfile.Arn)
+	}
+	if inst.Monitoring != nil {
+		vm.Monitoring = inst.Monitoring.State == types.MonitoringStateEnabled || inst.Monitoring.State == types.MonitoringStatePending
+	}
+	vm.EbsOptimized = aws.ToBool(inst.EbsOptimized)
 
-	// Get name from tags
-	for _, tag := range inst.Tags {
-		if aws.ToString(tag.Key) == "Name" {
-			vm.Name = aws.ToString(tag.Value)
-			break
+	// Block devices include the root volume
+	for _, mapping := range inst.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
 		}
+		vm.Volumes = append(vm.Volumes, services.VolumeAttachment{
+			VolumeID:            aws.ToString(mapping.Ebs.VolumeId),
+			VMID:                vm.ID,
+			Device:              aws.ToString(mapping.DeviceName),
+			State:               string(mapping.Ebs.Status),
+			DeleteOnTermination: aws.ToBool(mapping.Ebs.DeleteOnTermination),
+		})
 	}
 
-	return vm, nil
+	// The name is the Name tag
+	if vm.Tags = tagMap(inst.Tags); vm.Tags != nil {
+		vm.Name = vm.Tags["Name"]
+	}
+
+	return vm
+}
+
+// GetVM gets a specific virtual machine by ID
+func (c *AWSCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
+	// Validate input
+	if id == "" {
+		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "id", "instance ID cannot be empty")
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		InstanceIds: []string{id},
+	}
+
+	resp, err := c.describeInstances(ctx, input, "GetVM")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
+		return nil, cloudsdk.NewResourceNotFoundError("aws", "compute", "instance", id)
+	}
+
+	return vmFromInstance(resp.Reservations[0].Instances[0]), nil
 }
 
 func (c *AWSCompute) StartVM(ctx context.Context, id string) error {
@@ -570,18 +921,27 @@ func (c *AWSCompute) StartVM(ctx context.Context, id string) error {
 		InstanceIds: []string{id},
 	}
 
-	logRequest("StartInstances", input, c.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, c.logger, "StartVM", "StartInstances", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := c.client.StartInstances(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, c.logger, "StartInstances", input)
 
 	var resp *ec2.StartInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, c.retryConfig, func() error {
-		resp, err = c.client.StartInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "StartInstances", func(ctx context.Context) error {
+		resp, err = c.client.StartInstances(ctx, input, ec2Options(ctx)...)
 		return err
 	})
 
-	logResponse("StartInstances", resp, retryErr, c.debug)
+	logResponse(ctx, c.logger, "StartInstances", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapAWSError(retryErr, "aws", "compute", "StartVM")
@@ -600,18 +960,27 @@ func (c *AWSCompute) StopVM(ctx context.Context, id string) error {
 		InstanceIds: []string{id},
 	}
 
-	logRequest("StopInstances", input, c.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, c.logger, "StopVM", "StopInstances", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := c.client.StopInstances(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, c.logger, "StopInstances", input)
 
 	var resp *ec2.StopInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, c.retryConfig, func() error {
-		resp, err = c.client.StopInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "StopInstances", func(ctx context.Context) error {
+		resp, err = c.client.StopInstances(ctx, input, ec2Options(ctx)...)
 		return err
 	})
 
-	logResponse("StopInstances", resp, retryErr, c.debug)
+	logResponse(ctx, c.logger, "StopInstances", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapAWSError(retryErr, "aws", "compute", "StopVM")
@@ -620,6 +989,222 @@ func (c *AWSCompute) StopVM(ctx context.Context, id string) error {
 	return nil
 }
 
+// RebootVM reboots a running instance
+func (c *AWSCompute) RebootVM(ctx context.Context, id string) error {
+	// Validate input
+	if id == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "id", "instance ID cannot be empty")
+	}
+
+	input := &ec2.RebootInstancesInput{
+		InstanceIds: []string{id},
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, c.logger, "RebootVM", "RebootInstances", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := c.client.RebootInstances(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, c.logger, "RebootInstances", input)
+
+	var resp *ec2.RebootInstancesOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "RebootInstances", func(ctx context.Context) error {
+		resp, err = c.client.RebootInstances(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, c.logger, "RebootInstances", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "RebootVM")
+	}
+
+	return nil
+}
+
+// ResizeVM changes the instance type of an instance. EC2 can only change the
+// type of a stopped instance, so a running instance is stopped first and
+// started again afterwards. If a step fails, the instance is rolled back to
+// its original type and state.
+func (c *AWSCompute) ResizeVM(ctx context.Context, id string, instanceType string) error {
+	// Validate input
+	if id == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "id", "instance ID cannot be empty")
+	}
+	if instanceType == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "InstanceType", "instance type cannot be empty")
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return c.planResize(ctx, id, instanceType)
+	}
+
+	vm, err := c.GetVM(ctx, id)
+	if err != nil {
+		return err
+	}
+	if vm.InstanceType == instanceType {
+		return nil
+	}
+	if vm.State != "running" && vm.State != "stopped" {
+		return cloudsdk.NewInvalidStateError("aws", "compute", "ResizeVM", id, vm.State).
+			WithSuggestions(
+				"Wait for the instance to be running or stopped before resizing it",
+				"Use services.WaitUntilVMRunning or services.WaitUntilVMStopped to wait",
+			)
+	}
+
+	from, wasRunning := vm.InstanceType, vm.State == "running"
+	fail := func(step string, err error) error {
+		resizeErr := &services.ResizeError{VMID: id, From: from, To: instanceType, Step: step, Err: err}
+		resizeErr.RollbackErr = c.rollbackResize(ctx, id, from, wasRunning)
+		resizeErr.RolledBack = resizeErr.RollbackErr == nil
+		return resizeErr
+	}
+
+	if wasRunning {
+		if err := c.StopVM(ctx, id); err != nil {
+			return fail(services.ResizeStepStop, err)
+		}
+		if err := services.WaitUntilVMStopped(ctx, c, id, c.waitOptions...); err != nil {
+			return fail(services.ResizeStepStop, err)
+		}
+	}
+
+	if err := c.modifyInstanceType(ctx, id, instanceType); err != nil {
+		return fail(services.ResizeStepModify, err)
+	}
+
+	if wasRunning {
+		if err := c.StartVM(ctx, id); err != nil {
+			return fail(services.ResizeStepStart, err)
+		}
+		if err := services.WaitUntilVMRunning(ctx, c, id, c.waitOptions...); err != nil {
+			return fail(services.ResizeStepStart, err)
+		}
+	}
+
+	return nil
+}
+
+// rollbackResize restores the instance type of a stopped instance, and starts
+// it again if it was running before the resize. A rollback is attempted even
+// if ctx has ended, with a fresh timeout, so that the instance isn't left
+// stopped because the caller gave up.
+func (c *AWSCompute) rollbackResize(ctx context.Context, id, instanceType string, start bool) error {
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+		defer cancel()
+	}
+
+	vm, err := c.GetVM(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch vm.State {
+	case "stopped":
+	case "stopping":
+		if err := services.WaitUntilVMStopped(ctx, c, id, c.waitOptions...); err != nil {
+			return err
+		}
+	default:
+		// Running, or on its way: restoring the type would mean stopping
+		// it again, which is left to the caller
+		if vm.InstanceType == instanceType {
+			return nil
+		}
+		return fmt.Errorf("instance is %s with instance type %s", vm.State, vm.InstanceType)
+	}
+	if vm.InstanceType != instanceType {
+		if err := c.modifyInstanceType(ctx, id, instanceType); err != nil {
+			return err
+		}
+	}
+	if !start {
+		return nil
+	}
+	if err := c.StartVM(ctx, id); err != nil {
+		return err
+	}
+	return services.WaitUntilVMRunning(ctx, c, id, c.waitOptions...)
+}
+
+// rollbackTimeout bounds a ResizeVM rollback that runs after the caller's
+// context has ended.
+const rollbackTimeout = 10 * time.Minute
+
+// modifyInstanceType sets the instance type of a stopped instance.
+func (c *AWSCompute) modifyInstanceType(ctx context.Context, id, instanceType string) error {
+	input := &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(id),
+		InstanceType: &types.AttributeValue{Value: aws.String(instanceType)},
+	}
+
+	logRequest(ctx, c.logger, "ModifyInstanceAttribute", input)
+
+	var resp *ec2.ModifyInstanceAttributeOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "ModifyInstanceAttribute", func(ctx context.Context) error {
+		resp, err = c.client.ModifyInstanceAttribute(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, c.logger, "ModifyInstanceAttribute", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "ResizeVM")
+	}
+	return nil
+}
+
+// planResize records the calls of a resize in the dry run's plan, checking
+// the caller's permission for each. The instance's current state isn't
+// checked, so all three calls are planned.
+func (c *AWSCompute) planResize(ctx context.Context, id, instanceType string) error {
+	stop := &ec2.StopInstancesInput{InstanceIds: []string{id}}
+	err := planEC2Call(ctx, c.logger, "ResizeVM", "StopInstances", stop, func(ctx context.Context) error {
+		dryRun := *stop
+		dryRun.DryRun = aws.Bool(true)
+		_, err := c.client.StopInstances(ctx, &dryRun, ec2Options(ctx)...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	modify := &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(id),
+		InstanceType: &types.AttributeValue{Value: aws.String(instanceType)},
+	}
+	err = planEC2Call(ctx, c.logger, "ResizeVM", "ModifyInstanceAttribute", modify, func(ctx context.Context) error {
+		dryRun := *modify
+		dryRun.DryRun = aws.Bool(true)
+		_, err := c.client.ModifyInstanceAttribute(ctx, &dryRun, ec2Options(ctx)...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	start := &ec2.StartInstancesInput{InstanceIds: []string{id}}
+	return planEC2Call(ctx, c.logger, "ResizeVM", "StartInstances", start, func(ctx context.Context) error {
+		dryRun := *start
+		dryRun.DryRun = aws.Bool(true)
+		_, err := c.client.StartInstances(ctx, &dryRun, ec2Options(ctx)...)
+		return err
+	})
+}
+
 func (c *AWSCompute) DeleteVM(ctx context.Context, id string) error {
 	// Validate input
 	if id == "" {
@@ -630,18 +1215,27 @@ func (c *AWSCompute) DeleteVM(ctx context.Context, id string) error {
 		InstanceIds: []string{id},
 	}
 
-	logRequest("TerminateInstances", input, c.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, c.logger, "DeleteVM", "TerminateInstances", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := c.client.TerminateInstances(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, c.logger, "TerminateInstances", input)
 
 	var resp *ec2.TerminateInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, c.retryConfig, func() error {
-		resp, err = c.client.TerminateInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, c.logger, c.retryConfig, "compute", "TerminateInstances", func(ctx context.Context) error {
+		resp, err = c.client.TerminateInstances(ctx, input, ec2Options(ctx)...)
 		return err
 	})
 
-	logResponse("TerminateInstances", resp, retryErr, c.debug)
+	logResponse(ctx, c.logger, "TerminateInstances", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapAWSError(retryErr, "aws", "compute", "DeleteVM")
@@ -668,7 +1262,7 @@ func (c *AWSCompute) SpotInstances() services.SpotInstancesService {
 // InstanceTypesServiceImpl implements InstanceTypesService
 type InstanceTypesServiceImpl struct {
 	client EC2ClientInterface
-	debug  bool
+	logger *slog.Logger
 }
 
 // List returns a list of instance types based on filters
@@ -686,11 +1280,11 @@ func (s *InstanceTypesServiceImpl) List(ctx context.Context, filter *services.In
 		// We'll need to filter the results after fetching
 	}
 
-	logRequest("DescribeInstanceTypes", input, s.debug)
+	logRequest(ctx, s.logger, "DescribeInstanceTypes", input)
 
-	resp, err := s.client.DescribeInstanceTypes(ctx, input)
+	resp, err := s.client.DescribeInstanceTypes(ctx, input, ec2Options(ctx)...)
 
-	logResponse("DescribeInstanceTypes", resp, err, s.debug)
+	logResponse(ctx, s.logger, "DescribeInstanceTypes", resp, err)
 
 	if err != nil {
 		return nil, wrapAWSError(err, "aws", "compute", "ListInstanceTypes")
@@ -740,7 +1334,7 @@ func (s *InstanceTypesServiceImpl) List(ctx context.Context, filter *services.In
 // PlacementGroupsServiceImpl implements PlacementGroupsService
 type PlacementGroupsServiceImpl struct {
 	client EC2ClientInterface
-	debug  bool
+	logger *slog.Logger
 }
 
 // Create creates a new placement group
@@ -761,11 +1355,20 @@ func (s *PlacementGroupsServiceImpl) Create(ctx context.Context, config *service
 		Strategy:  types.PlacementStrategy(config.Strategy),
 	}
 
-	logRequest("CreatePlacementGroup", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "CreatePlacementGroup", "CreatePlacementGroup", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CreatePlacementGroup(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "CreatePlacementGroup", input)
 
-	_, err := s.client.CreatePlacementGroup(ctx, input)
+	_, err := s.client.CreatePlacementGroup(ctx, input, ec2Options(ctx)...)
 
-	logResponse("CreatePlacementGroup", nil, err, s.debug)
+	logResponse(ctx, s.logger, "CreatePlacementGroup", nil, err)
 
 	if err != nil {
 		return nil, wrapAWSError(err, "aws", "compute", "CreatePlacementGroup")
@@ -776,11 +1379,11 @@ func (s *PlacementGroupsServiceImpl) Create(ctx context.Context, config *service
 		GroupNames: []string{config.GroupName},
 	}
 
-	logRequest("DescribePlacementGroups", describeInput, s.debug)
+	logRequest(ctx, s.logger, "DescribePlacementGroups", describeInput)
 
-	resp, err := s.client.DescribePlacementGroups(ctx, describeInput)
+	resp, err := s.client.DescribePlacementGroups(ctx, describeInput, ec2Options(ctx)...)
 
-	logResponse("DescribePlacementGroups", resp, err, s.debug)
+	logResponse(ctx, s.logger, "DescribePlacementGroups", resp, err)
 
 	if err != nil {
 		return nil, wrapAWSError(err, "aws", "compute", "CreatePlacementGroup")
@@ -812,11 +1415,20 @@ func (s *PlacementGroupsServiceImpl) Delete(ctx context.Context, groupName strin
 		GroupName: aws.String(groupName),
 	}
 
-	logRequest("DeletePlacementGroup", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "DeletePlacementGroup", "DeletePlacementGroup", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.DeletePlacementGroup(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
 
-	_, err := s.client.DeletePlacementGroup(ctx, input)
+	logRequest(ctx, s.logger, "DeletePlacementGroup", input)
 
-	logResponse("DeletePlacementGroup", nil, err, s.debug)
+	_, err := s.client.DeletePlacementGroup(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "DeletePlacementGroup", nil, err)
 
 	if err != nil {
 		return wrapAWSError(err, "aws", "compute", "DeletePlacementGroup")
@@ -829,11 +1441,11 @@ func (s *PlacementGroupsServiceImpl) Delete(ctx context.Context, groupName strin
 func (s *PlacementGroupsServiceImpl) List(ctx context.Context) ([]*services.PlacementGroup, error) {
 	input := &ec2.DescribePlacementGroupsInput{}
 
-	logRequest("DescribePlacementGroups", input, s.debug)
+	logRequest(ctx, s.logger, "DescribePlacementGroups", input)
 
-	resp, err := s.client.DescribePlacementGroups(ctx, input)
+	resp, err := s.client.DescribePlacementGroups(ctx, input, ec2Options(ctx)...)
 
-	logResponse("DescribePlacementGroups", resp, err, s.debug)
+	logResponse(ctx, s.logger, "DescribePlacementGroups", resp, err)
 
 	if err != nil {
 		return nil, wrapAWSError(err, "aws", "compute", "ListPlacementGroups")
@@ -853,10 +1465,30 @@ func (s *PlacementGroupsServiceImpl) List(ctx context.Context) ([]*services.Plac
 	return placementGroups, nil
 }
 
+// Volumes returns the EBS volumes service
+func (c *AWSCompute) Volumes() services.VolumesService {
+	return c.volumesSvc
+}
+
+// Images returns the AMI service
+func (c *AWSCompute) Images() services.ImagesService {
+	return c.imagesSvc
+}
+
+// KeyPairs returns the EC2 key pairs service
+func (c *AWSCompute) KeyPairs() services.KeyPairsService {
+	return c.keyPairsSvc
+}
+
+// SecurityGroups returns the EC2 security groups service
+func (c *AWSCompute) SecurityGroups() services.SecurityGroupsService {
+	return c.securityGroupsSvc
+}
+
 // SpotInstancesServiceImpl implements SpotInstancesService
 type SpotInstancesServiceImpl struct {
 	client EC2ClientInterface
-	debug  bool
+	logger *slog.Logger
 }
 
 // Request requests spot instances
@@ -871,6 +1503,9 @@ func (s *SpotInstancesServiceImpl) Request(ctx context.Context, config *services
 	if config.InstanceType == "" {
 		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "InstanceType", "instance type is required")
 	}
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
 
 	input := &ec2.RequestSpotInstancesInput{
 		LaunchSpecification: &types.RequestSpotLaunchSpecification{
@@ -900,11 +1535,22 @@ func (s *SpotInstancesServiceImpl) Request(ctx context.Context, config *services
 		}
 	}
 
-	logRequest("RequestSpotInstances", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "RequestSpotInstances", "RequestSpotInstances", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.RequestSpotInstances(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
 
-	resp, err := s.client.RequestSpotInstances(ctx, input)
+	input.ClientToken = aws.String(awsidem.Token(config.IdempotencyKey))
 
-	logResponse("RequestSpotInstances", resp, err, s.debug)
+	logRequest(ctx, s.logger, "RequestSpotInstances", input)
+
+	resp, err := s.client.RequestSpotInstances(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "RequestSpotInstances", resp, err)
 
 	if err != nil {
 		return nil, wrapAWSError(err, "aws", "compute", "RequestSpotInstances")
@@ -942,11 +1588,11 @@ func (s *SpotInstancesServiceImpl) Describe(ctx context.Context, requestIds []st
 		input.SpotInstanceRequestIds = requestIds
 	}
 
-	logRequest("DescribeSpotInstanceRequests", input, s.debug)
+	logRequest(ctx, s.logger, "DescribeSpotInstanceRequests", input)
 
-	resp, err := s.client.DescribeSpotInstanceRequests(ctx, input)
+	resp, err := s.client.DescribeSpotInstanceRequests(ctx, input, ec2Options(ctx)...)
 
-	logResponse("DescribeSpotInstanceRequests", resp, err, s.debug)
+	logResponse(ctx, s.logger, "DescribeSpotInstanceRequests", resp, err)
 
 	if err != nil {
 		return nil, wrapAWSError(err, "aws", "compute", "DescribeSpotInstanceRequests")
@@ -986,11 +1632,20 @@ func (s *SpotInstancesServiceImpl) Cancel(ctx context.Context, requestId string)
 		SpotInstanceRequestIds: []string{requestId},
 	}
 
-	logRequest("CancelSpotInstanceRequests", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "CancelSpotInstanceRequests", "CancelSpotInstanceRequests", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CancelSpotInstanceRequests(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "CancelSpotInstanceRequests", input)
 
-	_, err := s.client.CancelSpotInstanceRequests(ctx, input)
+	_, err := s.client.CancelSpotInstanceRequests(ctx, input, ec2Options(ctx)...)
 
-	logResponse("CancelSpotInstanceRequests", nil, err, s.debug)
+	logResponse(ctx, s.logger, "CancelSpotInstanceRequests", nil, err)
 
 	if err != nil {
 		return wrapAWSError(err, "aws", "compute", "CancelSpotInstanceRequests")
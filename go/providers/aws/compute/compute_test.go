@@ -1,23 +1,36 @@
 package compute
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 // mockEC2Client is a mock implementation of the EC2 client
 type mockEC2Client struct {
 	runInstancesResponse                 *ec2.RunInstancesOutput
 	runInstancesError                    error
+	runInstancesInput                    *ec2.RunInstancesInput
+	runInstancesOptions                  []func(*ec2.Options)
 	describeInstancesResponse            *ec2.DescribeInstancesOutput
 	describeInstancesError               error
 	startInstancesResponse               *ec2.StartInstancesOutput
@@ -26,6 +39,9 @@ type mockEC2Client struct {
 	stopInstancesError                   error
 	terminateInstancesResponse           *ec2.TerminateInstancesOutput
 	terminateInstancesError              error
+	rebootInstancesInput                 *ec2.RebootInstancesInput
+	rebootInstancesError                 error
+	modifyInstanceAttributeError         error
 	describeInstanceTypesResponse        *ec2.DescribeInstanceTypesOutput
 	describeInstanceTypesError           error
 	describePlacementGroupsResponse      *ec2.DescribePlacementGroupsOutput
@@ -40,6 +56,36 @@ type mockEC2Client struct {
 	describeSpotInstanceRequestsError    error
 	cancelSpotInstanceRequestsResponse   *ec2.CancelSpotInstanceRequestsOutput
 	cancelSpotInstanceRequestsError      error
+	createVolumeResponse                 *ec2.CreateVolumeOutput
+	createVolumeError                    error
+	describeVolumesResponse              *ec2.DescribeVolumesOutput
+	describeVolumesError                 error
+	attachVolumeError                    error
+	detachVolumeError                    error
+	modifyVolumeError                    error
+	createSnapshotResponse               *ec2.CreateSnapshotOutput
+	createSnapshotError                  error
+	deleteVolumeError                    error
+	describeImagesResponse               *ec2.DescribeImagesOutput
+	describeImagesError                  error
+	createImageResponse                  *ec2.CreateImageOutput
+	createImageError                     error
+	deregisterImageError                 error
+	createKeyPairResponse                *ec2.CreateKeyPairOutput
+	createKeyPairError                   error
+	importKeyPairResponse                *ec2.ImportKeyPairOutput
+	importKeyPairError                   error
+	describeKeyPairsResponse             *ec2.DescribeKeyPairsOutput
+	describeKeyPairsError                error
+	deleteKeyPairError                   error
+	createSecurityGroupResponse          *ec2.CreateSecurityGroupOutput
+	createSecurityGroupError             error
+	describeSecurityGroupsResponse       *ec2.DescribeSecurityGroupsOutput
+	describeSecurityGroupsError          error
+	authorizeSecurityGroupError          error
+	revokeSecurityGroupIngressResponse   *ec2.RevokeSecurityGroupIngressOutput
+	revokeSecurityGroupError             error
+	deleteSecurityGroupError             error
 }
 
 // CreateTags implements EC2ClientInterface.
@@ -48,6 +94,8 @@ func (m *mockEC2Client) CreateTags(ctx context.Context, input *ec2.CreateTagsInp
 }
 
 func (m *mockEC2Client) RunInstances(ctx context.Context, input *ec2.RunInstancesInput, opts ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error) {
+	m.runInstancesInput = input
+	m.runInstancesOptions = opts
 	return m.runInstancesResponse, m.runInstancesError
 }
 
@@ -55,6 +103,15 @@ func (m *mockEC2Client) DescribeInstances(ctx context.Context, input *ec2.Descri
 	return m.describeInstancesResponse, m.describeInstancesError
 }
 
+func (m *mockEC2Client) RebootInstances(ctx context.Context, input *ec2.RebootInstancesInput, opts ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error) {
+	m.rebootInstancesInput = input
+	return &ec2.RebootInstancesOutput{}, m.rebootInstancesError
+}
+
+func (m *mockEC2Client) ModifyInstanceAttribute(ctx context.Context, input *ec2.ModifyInstanceAttributeInput, opts ...func(*ec2.Options)) (*ec2.ModifyInstanceAttributeOutput, error) {
+	return &ec2.ModifyInstanceAttributeOutput{}, m.modifyInstanceAttributeError
+}
+
 func (m *mockEC2Client) DescribeInstanceTypes(ctx context.Context, input *ec2.DescribeInstanceTypesInput, opts ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
 	return m.describeInstanceTypesResponse, m.describeInstanceTypesError
 }
@@ -95,6 +152,93 @@ func (m *mockEC2Client) TerminateInstances(ctx context.Context, input *ec2.Termi
 	return m.terminateInstancesResponse, m.terminateInstancesError
 }
 
+func (m *mockEC2Client) CreateVolume(ctx context.Context, input *ec2.CreateVolumeInput, opts ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+	return m.createVolumeResponse, m.createVolumeError
+}
+
+func (m *mockEC2Client) DescribeVolumes(ctx context.Context, input *ec2.DescribeVolumesInput, opts ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	return m.describeVolumesResponse, m.describeVolumesError
+}
+
+func (m *mockEC2Client) AttachVolume(ctx context.Context, input *ec2.AttachVolumeInput, opts ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error) {
+	return &ec2.AttachVolumeOutput{}, m.attachVolumeError
+}
+
+func (m *mockEC2Client) DetachVolume(ctx context.Context, input *ec2.DetachVolumeInput, opts ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error) {
+	return &ec2.DetachVolumeOutput{}, m.detachVolumeError
+}
+
+func (m *mockEC2Client) ModifyVolume(ctx context.Context, input *ec2.ModifyVolumeInput, opts ...func(*ec2.Options)) (*ec2.ModifyVolumeOutput, error) {
+	return &ec2.ModifyVolumeOutput{}, m.modifyVolumeError
+}
+
+func (m *mockEC2Client) CreateSnapshot(ctx context.Context, input *ec2.CreateSnapshotInput, opts ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	return m.createSnapshotResponse, m.createSnapshotError
+}
+
+func (m *mockEC2Client) DeleteVolume(ctx context.Context, input *ec2.DeleteVolumeInput, opts ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	return &ec2.DeleteVolumeOutput{}, m.deleteVolumeError
+}
+
+func (m *mockEC2Client) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, opts ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	return m.describeImagesResponse, m.describeImagesError
+}
+
+func (m *mockEC2Client) CreateImage(ctx context.Context, input *ec2.CreateImageInput, opts ...func(*ec2.Options)) (*ec2.CreateImageOutput, error) {
+	return m.createImageResponse, m.createImageError
+}
+
+func (m *mockEC2Client) DeregisterImage(ctx context.Context, input *ec2.DeregisterImageInput, opts ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error) {
+	return &ec2.DeregisterImageOutput{}, m.deregisterImageError
+}
+
+func (m *mockEC2Client) CreateKeyPair(ctx context.Context, input *ec2.CreateKeyPairInput, opts ...func(*ec2.Options)) (*ec2.CreateKeyPairOutput, error) {
+	return m.createKeyPairResponse, m.createKeyPairError
+}
+
+func (m *mockEC2Client) ImportKeyPair(ctx context.Context, input *ec2.ImportKeyPairInput, opts ...func(*ec2.Options)) (*ec2.ImportKeyPairOutput, error) {
+	return m.importKeyPairResponse, m.importKeyPairError
+}
+
+func (m *mockEC2Client) DescribeKeyPairs(ctx context.Context, input *ec2.DescribeKeyPairsInput, opts ...func(*ec2.Options)) (*ec2.DescribeKeyPairsOutput, error) {
+	return m.describeKeyPairsResponse, m.describeKeyPairsError
+}
+
+func (m *mockEC2Client) DeleteKeyPair(ctx context.Context, input *ec2.DeleteKeyPairInput, opts ...func(*ec2.Options)) (*ec2.DeleteKeyPairOutput, error) {
+	return &ec2.DeleteKeyPairOutput{}, m.deleteKeyPairError
+}
+
+func (m *mockEC2Client) CreateSecurityGroup(ctx context.Context, input *ec2.CreateSecurityGroupInput, opts ...func(*ec2.Options)) (*ec2.CreateSecurityGroupOutput, error) {
+	return m.createSecurityGroupResponse, m.createSecurityGroupError
+}
+
+func (m *mockEC2Client) DescribeSecurityGroups(ctx context.Context, input *ec2.DescribeSecurityGroupsInput, opts ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return m.describeSecurityGroupsResponse, m.describeSecurityGroupsError
+}
+
+func (m *mockEC2Client) AuthorizeSecurityGroupIngress(ctx context.Context, input *ec2.AuthorizeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	return &ec2.AuthorizeSecurityGroupIngressOutput{Return: aws.Bool(true)}, m.authorizeSecurityGroupError
+}
+
+func (m *mockEC2Client) AuthorizeSecurityGroupEgress(ctx context.Context, input *ec2.AuthorizeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupEgressOutput, error) {
+	return &ec2.AuthorizeSecurityGroupEgressOutput{Return: aws.Bool(true)}, m.authorizeSecurityGroupError
+}
+
+func (m *mockEC2Client) RevokeSecurityGroupIngress(ctx context.Context, input *ec2.RevokeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	if m.revokeSecurityGroupIngressResponse != nil {
+		return m.revokeSecurityGroupIngressResponse, m.revokeSecurityGroupError
+	}
+	return &ec2.RevokeSecurityGroupIngressOutput{Return: aws.Bool(true)}, m.revokeSecurityGroupError
+}
+
+func (m *mockEC2Client) RevokeSecurityGroupEgress(ctx context.Context, input *ec2.RevokeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	return &ec2.RevokeSecurityGroupEgressOutput{Return: aws.Bool(true)}, m.revokeSecurityGroupError
+}
+
+func (m *mockEC2Client) DeleteSecurityGroup(ctx context.Context, input *ec2.DeleteSecurityGroupInput, opts ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error) {
+	return &ec2.DeleteSecurityGroupOutput{}, m.deleteSecurityGroupError
+}
+
 func TestAWSCompute_CreateVM(t *testing.T) {
 	helper := cloudsdktesting.NewTestHelper(t)
 
@@ -126,6 +270,87 @@ func TestAWSCompute_CreateVM(t *testing.T) {
 	helper.AssertEqual("10.0.0.1", vm.PrivateIP)
 }
 
+func TestAWSCompute_CreateVMConfigFields(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	mockClient := &mockEC2Client{
+		runInstancesResponse: &ec2.RunInstancesOutput{
+			Instances: []types.Instance{{
+				InstanceId:         aws.String("i-1234567890abcdef0"),
+				InstanceType:       types.InstanceTypeT3Micro,
+				State:              &types.InstanceState{Name: types.InstanceStateNamePending},
+				SubnetId:           aws.String("subnet-12345"),
+				Placement:          &types.Placement{GroupName: aws.String("hpc-cluster")},
+				IamInstanceProfile: &types.IamInstanceProfile{Arn: aws.String("arn:aws:iam::123456789012:instance-profile/web")},
+				Monitoring:         &types.Monitoring{State: types.MonitoringStatePending},
+				EbsOptimized:       aws.Bool(true),
+			}},
+		},
+	}
+	compute := NewWithClient(mockClient)
+
+	config := cloudsdktesting.GenerateVMConfig("web-server")
+	config.SubnetID = "subnet-12345"
+	config.SecurityGroups = []string{"sg-web"}
+	config.AssignPublicIP = aws.Bool(true)
+	config.PlacementGroup = "hpc-cluster"
+	config.IamInstanceProfile = "web"
+	config.Monitoring = aws.Bool(true)
+	config.EbsOptimized = aws.Bool(true)
+	config.Tags = map[string]string{"Environment": "production"}
+
+	vm, err := compute.CreateVM(ctx, config)
+	helper.AssertNoError(err)
+	input := mockClient.runInstancesInput
+
+	// A public IP moves the subnet and security groups onto the interface
+	helper.AssertEqual(1, len(input.NetworkInterfaces))
+	nic := input.NetworkInterfaces[0]
+	helper.AssertEqual(true, aws.ToBool(nic.AssociatePublicIpAddress))
+	helper.AssertEqual("subnet-12345", aws.ToString(nic.SubnetId))
+	helper.AssertEqual("sg-web", nic.Groups[0])
+	if input.SubnetId != nil || input.SecurityGroupIds != nil {
+		t.Errorf("expected no instance-level subnet or security groups with a network interface")
+	}
+	helper.AssertEqual("hpc-cluster", aws.ToString(input.Placement.GroupName))
+	helper.AssertEqual("web", aws.ToString(input.IamInstanceProfile.Name))
+	helper.AssertEqual(true, aws.ToBool(input.Monitoring.Enabled))
+	helper.AssertEqual(true, aws.ToBool(input.EbsOptimized))
+
+	// Tags, with the Name tag, are applied to the instance and its volumes at launch
+	helper.AssertEqual(2, len(input.TagSpecifications))
+	helper.AssertEqual(types.ResourceTypeInstance, input.TagSpecifications[0].ResourceType)
+	helper.AssertEqual(types.ResourceTypeVolume, input.TagSpecifications[1].ResourceType)
+	tags := input.TagSpecifications[0].Tags
+	helper.AssertEqual(2, len(tags))
+	helper.AssertEqual("Environment", aws.ToString(tags[0].Key))
+	helper.AssertEqual("Name", aws.ToString(tags[1].Key))
+	helper.AssertEqual("web-server", aws.ToString(tags[1].Value))
+
+	// The VM reports the launched configuration
+	helper.AssertEqual("web-server", vm.Name)
+	helper.AssertEqual("production", vm.Tags["Environment"])
+	helper.AssertEqual("t3.micro", vm.InstanceType)
+	helper.AssertEqual("subnet-12345", vm.SubnetID)
+	helper.AssertEqual("hpc-cluster", vm.PlacementGroup)
+	helper.AssertContains(vm.IamInstanceProfile, "instance-profile/web")
+	helper.AssertEqual(true, vm.Monitoring)
+	helper.AssertEqual(true, vm.EbsOptimized)
+
+	// Without AssignPublicIP the subnet and groups go on the instance, and
+	// profiles can be given by ARN
+	config.AssignPublicIP = nil
+	config.IamInstanceProfile = "arn:aws:iam::123456789012:instance-profile/web"
+	_, err = compute.CreateVM(ctx, config)
+	helper.AssertNoError(err)
+	input = mockClient.runInstancesInput
+	helper.AssertEqual(0, len(input.NetworkInterfaces))
+	helper.AssertEqual("subnet-12345", aws.ToString(input.SubnetId))
+	helper.AssertEqual("sg-web", input.SecurityGroupIds[0])
+	helper.AssertEqual(config.IamInstanceProfile, aws.ToString(input.IamInstanceProfile.Arn))
+}
+
 func TestAWSCompute_CreateVM_ErrorScenarios(t *testing.T) {
 	helper := cloudsdktesting.NewTestHelper(t)
 
@@ -198,6 +423,143 @@ func TestAWSCompute_ListVMs(t *testing.T) {
 	helper.AssertEqual("test-vm", vms[0].Name)
 }
 
+// pagedEC2Client serves DescribeInstances pages keyed by NextToken, the
+// first page under "", and records the input of each call.
+type pagedEC2Client struct {
+	mockEC2Client
+	pages  map[string]*ec2.DescribeInstancesOutput
+	inputs []*ec2.DescribeInstancesInput
+}
+
+func (m *pagedEC2Client) DescribeInstances(ctx context.Context, input *ec2.DescribeInstancesInput, opts ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	m.inputs = append(m.inputs, input)
+	page, ok := m.pages[aws.ToString(input.NextToken)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "InvalidParameterValue", Message: "Invalid NextToken"}
+	}
+	return page, nil
+}
+
+func instancePage(nextToken string, ids ...string) *ec2.DescribeInstancesOutput {
+	instances := make([]types.Instance, len(ids))
+	for i, id := range ids {
+		instances[i] = types.Instance{
+			InstanceId:   aws.String(id),
+			InstanceType: types.InstanceTypeT3Micro,
+			SubnetId:     aws.String("subnet-12345"),
+			State:        &types.InstanceState{Name: types.InstanceStateNameRunning},
+			Tags: []types.Tag{
+				{Key: aws.String("Name"), Value: aws.String("web-" + id)},
+				{Key: aws.String("Environment"), Value: aws.String("production")},
+			},
+		}
+	}
+	output := &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: instances}}}
+	if nextToken != "" {
+		output.NextToken = aws.String(nextToken)
+	}
+	return output
+}
+
+func TestAWSCompute_ListVMsPagination(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := &pagedEC2Client{pages: map[string]*ec2.DescribeInstancesOutput{
+		"":       instancePage("page-2", "i-1", "i-2"),
+		"page-2": instancePage("page-3", "i-3"),
+		"page-3": instancePage("", "i-4"),
+	}}
+	compute := NewWithClient(client)
+
+	// ListVMs follows NextToken to the last page
+	vms, err := compute.ListVMs(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(4, len(vms))
+	helper.AssertEqual(3, len(client.inputs))
+	helper.AssertEqual("page-3", aws.ToString(client.inputs[2].NextToken))
+	helper.AssertEqual("web-i-4", vms[3].Name)
+	helper.AssertEqual("production", vms[3].Tags["Environment"])
+	helper.AssertEqual("t3.micro", vms[3].InstanceType)
+	helper.AssertEqual("subnet-12345", vms[3].SubnetID)
+
+	// ListVMsWithOptions returns one page, with NextToken as its cursor
+	client.inputs = nil
+	page, err := compute.ListVMsWithOptions(ctx, &services.ListVMsOptions{PageSize: 2, Cursor: "page-2"})
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(page.VMs))
+	helper.AssertEqual("i-3", page.VMs[0].ID)
+	helper.AssertEqual("page-3", page.NextCursor)
+	helper.AssertEqual(int32(5), aws.ToInt32(client.inputs[0].MaxResults))
+
+	// The iterator stops fetching once the caller stops
+	client.inputs = nil
+	it := services.NewVMIterator(ctx, compute, services.ListVMsOptions{})
+	for it.Next() {
+		if it.VM().ID == "i-2" {
+			break
+		}
+	}
+	helper.AssertNoError(it.Err())
+	helper.AssertEqual(1, len(client.inputs))
+	helper.AssertEqual("page-2", it.Cursor())
+
+	// Invalid cursors are reported
+	_, err = compute.ListVMsWithOptions(ctx, &services.ListVMsOptions{Cursor: "bogus"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+}
+
+func TestAWSCompute_ListVMsFilters(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := &pagedEC2Client{pages: map[string]*ec2.DescribeInstancesOutput{"": instancePage("")}}
+	compute := NewWithClient(client)
+
+	// Terminated instances are left out by default
+	_, err := compute.ListVMsWithOptions(ctx, nil)
+	helper.AssertNoError(err)
+	input := client.inputs[0]
+	helper.AssertEqual(int32(1000), aws.ToInt32(input.MaxResults))
+	helper.AssertEqual(1, len(input.Filters))
+	helper.AssertEqual("instance-state-name", aws.ToString(input.Filters[0].Name))
+	helper.AssertEqual(false, containsValue(input.Filters[0].Values, "terminated"))
+
+	_, err = compute.ListVMsWithOptions(ctx, &services.ListVMsOptions{
+		Tags:          map[string]string{"Environment": "production", "Team": "web"},
+		States:        []string{"running", "stopped"},
+		NamePrefix:    "web-",
+		InstanceTypes: []string{"t3.micro"},
+		SubnetIDs:     []string{"subnet-12345"},
+	})
+	helper.AssertNoError(err)
+	filters := map[string][]string{}
+	for _, filter := range client.inputs[1].Filters {
+		filters[aws.ToString(filter.Name)] = filter.Values
+	}
+	helper.AssertEqual(6, len(filters))
+	helper.AssertEqual("running,stopped", strings.Join(filters["instance-state-name"], ","))
+	helper.AssertEqual("web-*", strings.Join(filters["tag:Name"], ","))
+	helper.AssertEqual("t3.micro", strings.Join(filters["instance-type"], ","))
+	helper.AssertEqual("subnet-12345", strings.Join(filters["subnet-id"], ","))
+	helper.AssertEqual("production", strings.Join(filters["tag:Environment"], ","))
+	helper.AssertEqual("web", strings.Join(filters["tag:Team"], ","))
+
+	// Page sizes EC2 would reject are caught before the call
+	_, err = compute.ListVMsWithOptions(ctx, &services.ListVMsOptions{PageSize: 5000})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	helper.AssertEqual(2, len(client.inputs))
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func TestAWSCompute_GetVM(t *testing.T) {
 	helper := cloudsdktesting.NewTestHelper(t)
 
@@ -257,11 +619,126 @@ func TestAWSCompute_VMLifecycle(t *testing.T) {
 	err = compute.StopVM(context.Background(), vmID)
 	helper.AssertNoError(err)
 
+	// Test reboot VM
+	err = compute.RebootVM(context.Background(), vmID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(vmID, mockClient.rebootInstancesInput.InstanceIds[0])
+
 	// Test delete VM
 	err = compute.DeleteVM(context.Background(), vmID)
 	helper.AssertNoError(err)
 }
 
+// instanceEC2Client simulates a single instance whose state and type follow
+// the stop, modify and start calls made on it, and records those calls.
+type instanceEC2Client struct {
+	mockEC2Client
+	state        types.InstanceStateName
+	instanceType types.InstanceType
+	startErrors  []error
+	calls        []string
+}
+
+func (m *instanceEC2Client) DescribeInstances(ctx context.Context, input *ec2.DescribeInstancesInput, opts ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{{
+		InstanceId:   aws.String(input.InstanceIds[0]),
+		InstanceType: m.instanceType,
+		State:        &types.InstanceState{Name: m.state},
+	}}}}}, nil
+}
+
+func (m *instanceEC2Client) StopInstances(ctx context.Context, input *ec2.StopInstancesInput, opts ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	m.calls = append(m.calls, "stop")
+	m.state = types.InstanceStateNameStopped
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+func (m *instanceEC2Client) ModifyInstanceAttribute(ctx context.Context, input *ec2.ModifyInstanceAttributeInput, opts ...func(*ec2.Options)) (*ec2.ModifyInstanceAttributeOutput, error) {
+	value := aws.ToString(input.InstanceType.Value)
+	m.calls = append(m.calls, "modify "+value)
+	if m.state != types.InstanceStateNameStopped {
+		return nil, &smithy.GenericAPIError{Code: "IncorrectInstanceState", Message: "The instance is not in the 'stopped' state."}
+	}
+	m.instanceType = types.InstanceType(value)
+	return &ec2.ModifyInstanceAttributeOutput{}, nil
+}
+
+func (m *instanceEC2Client) StartInstances(ctx context.Context, input *ec2.StartInstancesInput, opts ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	m.calls = append(m.calls, "start")
+	if len(m.startErrors) > 0 {
+		err := m.startErrors[0]
+		m.startErrors = m.startErrors[1:]
+		return nil, err
+	}
+	m.state = types.InstanceStateNameRunning
+	return &ec2.StartInstancesOutput{}, nil
+}
+
+func TestAWSCompute_ResizeVM(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	fastPolls := WithWaitOptions(services.WithPollInterval(time.Millisecond, time.Millisecond))
+	id := "i-1234567890abcdef0"
+
+	// A running instance is stopped, modified and started again
+	client := &instanceEC2Client{state: types.InstanceStateNameRunning, instanceType: types.InstanceTypeT3Micro}
+	compute := NewWithClient(client, fastPolls)
+	helper.AssertNoError(compute.ResizeVM(ctx, id, "t3.large"))
+	helper.AssertEqual("stop,modify t3.large,start", strings.Join(client.calls, ","))
+	helper.AssertEqual(types.InstanceTypeT3Large, client.instanceType)
+	helper.AssertEqual(types.InstanceStateNameRunning, client.state)
+
+	// Resizing to the current type does nothing
+	client.calls = nil
+	helper.AssertNoError(compute.ResizeVM(ctx, id, "t3.large"))
+	helper.AssertEqual(0, len(client.calls))
+
+	// A stopped instance stays stopped
+	client = &instanceEC2Client{state: types.InstanceStateNameStopped, instanceType: types.InstanceTypeT3Micro}
+	helper.AssertNoError(NewWithClient(client, fastPolls).ResizeVM(ctx, id, "t3.large"))
+	helper.AssertEqual("modify t3.large", strings.Join(client.calls, ","))
+
+	// A failed start is rolled back to the original type
+	capacityErr := &smithy.GenericAPIError{Code: "InsufficientInstanceCapacity", Message: "Insufficient capacity."}
+	client = &instanceEC2Client{
+		state:        types.InstanceStateNameRunning,
+		instanceType: types.InstanceTypeT3Micro,
+		startErrors:  []error{capacityErr},
+	}
+	err := NewWithClient(client, fastPolls).ResizeVM(ctx, id, "p4d.24xlarge")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	var resizeErr *services.ResizeError
+	if !errors.As(err, &resizeErr) {
+		t.Fatalf("expected *services.ResizeError, got %T", err)
+	}
+	helper.AssertEqual(services.ResizeStepStart, resizeErr.Step)
+	helper.AssertEqual(true, resizeErr.RolledBack)
+	helper.AssertEqual("t3.micro", resizeErr.From)
+	helper.AssertEqual("stop,modify p4d.24xlarge,start,modify t3.micro,start", strings.Join(client.calls, ","))
+	helper.AssertEqual(types.InstanceTypeT3Micro, client.instanceType)
+	helper.AssertEqual(types.InstanceStateNameRunning, client.state)
+
+	// A failed rollback is reported
+	client = &instanceEC2Client{
+		state:        types.InstanceStateNameRunning,
+		instanceType: types.InstanceTypeT3Micro,
+		startErrors:  []error{capacityErr, capacityErr},
+	}
+	err = NewWithClient(client, fastPolls).ResizeVM(ctx, id, "p4d.24xlarge")
+	if !errors.As(err, &resizeErr) {
+		t.Fatalf("expected *services.ResizeError, got %T", err)
+	}
+	helper.AssertEqual(false, resizeErr.RolledBack)
+	helper.AssertErrorCode(resizeErr.RollbackErr, cloudsdk.ErrResourceConflict)
+	helper.AssertContains(err.Error(), "rollback failed")
+
+	// Only running and stopped instances can be resized
+	client = &instanceEC2Client{state: types.InstanceStateNamePending, instanceType: types.InstanceTypeT3Micro}
+	err = NewWithClient(client, fastPolls).ResizeVM(ctx, id, "t3.large")
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidState)
+	helper.AssertEqual(0, len(client.calls))
+}
+
 func TestAWSCompute_InstanceTypes(t *testing.T) {
 	helper := cloudsdktesting.NewTestHelper(t)
 
@@ -394,6 +871,13 @@ func TestAWSCompute_SpotInstances(t *testing.T) {
 	helper.AssertEqual("sir-12345", request.SpotInstanceRequestId)
 	helper.AssertEqual("open", request.State)
 
+	// Idempotency keys longer than an EC2 ClientToken are rejected before the
+	// request is sent
+	tooLong := *config
+	tooLong.IdempotencyKey = strings.Repeat("k", 65)
+	_, err = compute.SpotInstances().Request(context.Background(), &tooLong)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
 	// Test describe spot instance requests
 	requests, err := compute.SpotInstances().Describe(context.Background(), []string{"sir-12345"})
 	helper.AssertNoError(err)
@@ -406,6 +890,363 @@ func TestAWSCompute_SpotInstances(t *testing.T) {
 	helper.AssertNoError(err)
 }
 
+// throttledEC2Client fails the first StartInstances call with a throttling error.
+type throttledEC2Client struct {
+	mockEC2Client
+	calls int
+}
+
+func (m *throttledEC2Client) StartInstances(ctx context.Context, input *ec2.StartInstancesInput, opts ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	m.calls++
+	if m.calls == 1 {
+		return nil, &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusBadRequest}},
+				Err:      &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"},
+			},
+			RequestID: "req-throttled",
+		}
+	}
+	output := &ec2.StartInstancesOutput{}
+	awsmiddleware.SetRequestIDMetadata(&output.ResultMetadata, "req-ok")
+	return output, nil
+}
+
+// retriedEC2Client fails the first RunInstances call with a retryable error
+// and records the client token of each call.
+type retriedEC2Client struct {
+	mockEC2Client
+	clientTokens []string
+}
+
+func (m *retriedEC2Client) RunInstances(ctx context.Context, input *ec2.RunInstancesInput, opts ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error) {
+	m.clientTokens = append(m.clientTokens, aws.ToString(input.ClientToken))
+	if len(m.clientTokens) == 1 {
+		return nil, &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "Request limit exceeded"}
+	}
+	return &ec2.RunInstancesOutput{
+		Instances: []types.Instance{{
+			InstanceId: aws.String("i-1234567890abcdef0"),
+			State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+		}},
+	}, nil
+}
+
+func TestAWSCompute_CreateVMClientToken(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	// Retries send the client token of the first attempt
+	client := &retriedEC2Client{}
+	_, err := NewWithClient(client).CreateVM(ctx, cloudsdktesting.GenerateVMConfig("test-vm"))
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(client.clientTokens))
+	if client.clientTokens[0] == "" {
+		t.Fatal("expected a generated client token")
+	}
+	helper.AssertEqual(client.clientTokens[0], client.clientTokens[1])
+
+	// The idempotency key is the client token
+	config := cloudsdktesting.GenerateVMConfig("test-vm")
+	config.IdempotencyKey = "deploy-42-web-1"
+	client = &retriedEC2Client{}
+	_, err = NewWithClient(client).CreateVM(ctx, config)
+	helper.AssertNoError(err)
+	helper.AssertEqual("deploy-42-web-1", client.clientTokens[0])
+	helper.AssertEqual("deploy-42-web-1", client.clientTokens[1])
+
+	// Reusing the key with different parameters is a conflict
+	mockClient := &mockEC2Client{
+		runInstancesError: &smithy.GenericAPIError{Code: "IdempotentParameterMismatch", Message: "Arguments on this idempotent request are inconsistent"},
+	}
+	_, err = NewWithClient(mockClient).CreateVM(ctx, config)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+}
+
+func TestAWSCompute_CallOptions(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	mockClient := &mockEC2Client{
+		runInstancesResponse: &ec2.RunInstancesOutput{
+			Instances: []types.Instance{{
+				InstanceId: aws.String("i-1234567890abcdef0"),
+				State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+			}},
+		},
+	}
+	compute := NewWithClient(mockClient)
+
+	// Without options, requests go to the client's region
+	_, err := compute.CreateVM(context.Background(), cloudsdktesting.GenerateVMConfig("test-vm"))
+	helper.AssertNoError(err)
+	helper.AssertEqual(0, len(mockClient.runInstancesOptions))
+
+	// A Region option overrides it for the request
+	ctx := services.ContextWithCallOptions(context.Background(), services.WithRegion("eu-west-1"))
+	_, err = compute.CreateVM(ctx, cloudsdktesting.GenerateVMConfig("test-vm"))
+	helper.AssertNoError(err)
+	options := ec2.Options{Region: "us-east-1"}
+	for _, opt := range mockClient.runInstancesOptions {
+		opt(&options)
+	}
+	helper.AssertEqual("eu-west-1", options.Region)
+}
+
+// attemptTracer records the name and attributes of each span started through it.
+type attemptTracer struct {
+	names []string
+	attrs []map[string]interface{}
+}
+
+type attemptSpan struct {
+	attrs map[string]interface{}
+	err   error
+}
+
+func (t *attemptTracer) Start(ctx context.Context, name string, attrs ...cloudsdk.Attribute) (context.Context, cloudsdk.Span) {
+	span := &attemptSpan{attrs: make(map[string]interface{})}
+	span.SetAttributes(attrs...)
+	t.names = append(t.names, name)
+	t.attrs = append(t.attrs, span.attrs)
+	return ctx, span
+}
+
+func (s *attemptSpan) SetAttributes(attrs ...cloudsdk.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *attemptSpan) RecordError(err error) { s.err = err }
+func (s *attemptSpan) End()                  {}
+
+func TestAWSCompute_RetryAttemptSpans(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	client := &throttledEC2Client{}
+	compute := NewWithClient(client)
+	tracer := &attemptTracer{}
+	ctx := cloudsdk.ContextWithTracer(context.Background(), tracer)
+
+	err := compute.StartVM(ctx, "i-1234567890abcdef0")
+
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, client.calls)
+	helper.AssertEqual(2, len(tracer.names))
+	helper.AssertEqual("aws.compute.StartInstances", tracer.names[0])
+	helper.AssertEqual("StartInstances", tracer.attrs[0][cloudsdk.AttrOperation])
+	helper.AssertEqual(1, tracer.attrs[0][cloudsdk.AttrAttempt])
+	helper.AssertEqual(2, tracer.attrs[1][cloudsdk.AttrAttempt])
+}
+
+func TestAWSCompute_RateLimitRetryAfter(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	mockClient := &mockEC2Client{
+		runInstancesError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: header}},
+			Err:      &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "Request limit exceeded"},
+		},
+	}
+	compute := NewWithClient(mockClient)
+	ctx := cloudsdk.ContextWithRetryPolicy(context.Background(), cloudsdk.NoRetry)
+
+	_, err := compute.CreateVM(ctx, cloudsdktesting.GenerateVMConfig("test-vm"))
+
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+	helper.AssertEqual(7*time.Second, cloudsdk.RetryAfterHint(err))
+}
+
+func TestAWSCompute_ErrorDetails(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	mockClient := &mockEC2Client{
+		startInstancesError: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+				Err:      &smithy.GenericAPIError{Code: "ServiceUnavailable", Message: "Service is unavailable"},
+			},
+			RequestID: "req-unavailable",
+		},
+	}
+	compute := NewWithClient(mockClient)
+	ctx := cloudsdk.ContextWithRetryPolicy(context.Background(), cloudsdk.RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+	})
+
+	err := compute.StartVM(ctx, "i-1234567890abcdef0")
+
+	var cloudErr *cloudsdk.CloudError
+	if !errors.As(err, &cloudErr) {
+		t.Fatalf("expected a CloudError, got %v", err)
+	}
+	helper.AssertEqual(cloudsdk.ErrProviderError, cloudErr.Code)
+	helper.AssertEqual("req-unavailable", cloudErr.Context.RequestID)
+	helper.AssertEqual("ServiceUnavailable", cloudErr.Context.ProviderCode)
+	helper.AssertEqual(http.StatusServiceUnavailable, cloudErr.Context.StatusCode)
+	helper.AssertEqual(3, cloudErr.Context.Attempts)
+	helper.AssertEqual(http.StatusBadGateway, cloudErr.HTTPStatus())
+	helper.AssertContains(err.Error(), "request ID: req-unavailable")
+}
+
+func TestAWSCompute_StateAndQuotaErrors(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	mockClient := &mockEC2Client{
+		runInstancesError:  &smithy.GenericAPIError{Code: "VcpuLimitExceeded", Message: "You have requested more vCPU capacity than your current vCPU limit"},
+		stopInstancesError: &smithy.GenericAPIError{Code: "IncorrectInstanceState", Message: "The instance is not in a state from which it can be stopped"},
+	}
+	compute := NewWithClient(mockClient)
+
+	_, err := compute.CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-server"))
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+
+	err = compute.StopVM(ctx, "i-1234567890abcdef0")
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidState)
+	if !errors.Is(err, cloudsdk.ErrInvalidState) {
+		t.Errorf("expected errors.Is to match ErrInvalidState, got %v", err)
+	}
+}
+
+func TestAWSCompute_DryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	mockClient := &mockEC2Client{
+		runInstancesError:       &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."},
+		terminateInstancesError: &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "You are not authorized to perform this operation."},
+	}
+	compute := NewWithClient(mockClient)
+	ctx, plan := cloudsdk.ContextWithPlan(context.Background())
+
+	config := cloudsdktesting.GenerateVMConfig("web-server")
+	config.UserData = "#!/bin/bash\necho secret"
+	vm, err := compute.CreateVM(ctx, config)
+	helper.AssertNoError(err)
+	if vm != nil {
+		t.Errorf("expected no VM from a dry run, got %+v", vm)
+	}
+	helper.AssertEqual(true, aws.ToBool(mockClient.runInstancesInput.DryRun))
+
+	calls := plan.Calls()
+	helper.AssertEqual(1, len(calls))
+	helper.AssertEqual("RunInstances", calls[0].API)
+	helper.AssertEqual(cloudsdk.OpCreateVM, calls[0].Operation)
+	helper.AssertEqual(true, calls[0].PermissionChecked)
+	input := calls[0].Input.(*ec2.RunInstancesInput)
+	helper.AssertEqual("[REDACTED]", aws.ToString(input.UserData))
+	if input.DryRun != nil {
+		t.Errorf("expected the planned call without DryRun, got %v", aws.ToBool(input.DryRun))
+	}
+	// The Name tag is part of the launch, not a separate call
+	named := false
+	for _, tag := range input.TagSpecifications[0].Tags {
+		named = named || aws.ToString(tag.Key) == "Name" && aws.ToString(tag.Value) == "web-server"
+	}
+	helper.AssertEqual(true, named)
+
+	// A resize plans every step, without reading the instance
+	helper.AssertNoError(compute.ResizeVM(ctx, "i-1234567890abcdef0", "t3.large"))
+	calls = plan.Calls()
+	helper.AssertEqual(4, len(calls))
+	helper.AssertEqual("StopInstances", calls[1].API)
+	helper.AssertEqual("ModifyInstanceAttribute", calls[2].API)
+	helper.AssertEqual("StartInstances", calls[3].API)
+	helper.AssertEqual(cloudsdk.OpResizeVM, calls[3].Operation)
+
+	// Permission failures are reported as they would be for the real call
+	err = compute.DeleteVM(ctx, "i-1234567890abcdef0")
+	helper.AssertErrorCode(err, cloudsdk.ErrAuthorization)
+	helper.AssertEqual(4, len(plan.Calls()))
+}
+
+// logRecords decodes the JSON log records written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAWSCompute_LoggingRedactsUserData(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mockClient := &mockEC2Client{
+		runInstancesResponse: &ec2.RunInstancesOutput{
+			Instances: []types.Instance{
+				{
+					InstanceId: stringPtr("i-1234567890abcdef0"),
+					State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+				},
+			},
+		},
+	}
+	compute := NewWithClient(mockClient, WithLogger(logger))
+	config := cloudsdktesting.GenerateVMConfig("test-vm")
+	config.UserData = "#!/bin/bash\nexport DB_PASSWORD=hunter2"
+
+	_, err := compute.CreateVM(context.Background(), config)
+
+	helper.AssertNoError(err)
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Fatalf("user data was logged: %s", buf.String())
+	}
+	helper.AssertContains(buf.String(), awslog.Redacted)
+
+	records := logRecords(t, &buf)
+	helper.AssertEqual("aws request", records[0]["msg"])
+	helper.AssertEqual("compute", records[0]["service"])
+	helper.AssertEqual("RunInstances", records[0]["operation"])
+}
+
+func TestAWSCompute_LoggingRetries(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	compute := NewWithClient(&throttledEC2Client{}, WithLogger(logger))
+
+	err := compute.StartVM(context.Background(), "i-1234567890abcdef0")
+
+	helper.AssertNoError(err)
+	var retry, response map[string]interface{}
+	for _, record := range logRecords(t, &buf) {
+		switch record["msg"] {
+		case "aws attempt failed, retrying":
+			retry = record
+		case "aws response":
+			response = record
+		}
+	}
+	if retry == nil || response == nil {
+		t.Fatalf("missing retry or response record: %s", buf.String())
+	}
+	helper.AssertEqual("WARN", retry["level"])
+	helper.AssertEqual("StartInstances", retry["operation"])
+	helper.AssertEqual(float64(1), retry["attempt"])
+	helper.AssertEqual("req-throttled", retry["request_id"])
+	helper.AssertContains(retry["error"].(string), "Throttling")
+	if _, ok := retry["duration"]; !ok {
+		t.Errorf("retry record has no duration: %v", retry)
+	}
+	helper.AssertEqual("req-ok", response["request_id"])
+}
+
 func TestAWSCompute_ConcurrentOperations(t *testing.T) {
 	mockClient := &mockEC2Client{
 		describeInstancesResponse: &ec2.DescribeInstancesOutput{
@@ -0,0 +1,284 @@
+package compute
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// ImagesServiceImpl implements ImagesService with AMIs
+type ImagesServiceImpl struct {
+	client      EC2ClientInterface
+	logger      *slog.Logger
+	retryConfig RetryConfig
+}
+
+// maxImagePageSize is the most images DescribeImages returns per page.
+const maxImagePageSize = 1000
+
+// List lists AMIs, newest first, following NextToken across every page.
+// EC2 filters on everything but the creation date window, which is applied
+// to the results.
+func (s *ImagesServiceImpl) List(ctx context.Context, filter *services.ImageFilter) ([]*services.Image, error) {
+	return s.listImages(ctx, filter, "ListImages")
+}
+
+// listImages lists the AMIs that match filter, reporting errors as operation.
+func (s *ImagesServiceImpl) listImages(ctx context.Context, filter *services.ImageFilter, operation string) ([]*services.Image, error) {
+	if filter == nil {
+		filter = &services.ImageFilter{}
+	}
+
+	input := &ec2.DescribeImagesInput{
+		Owners:  filter.Owners,
+		Filters: imageFilters(filter),
+	}
+	if len(filter.ImageIDs) > 0 {
+		// MaxResults can't be combined with image IDs
+		input.ImageIds = filter.ImageIDs
+	} else {
+		input.MaxResults = aws.Int32(maxImagePageSize)
+		if len(input.Owners) == 0 {
+			input.Owners = []string{"self"}
+		}
+	}
+
+	images := []*services.Image{}
+	for {
+		resp, err := s.describeImages(ctx, input, operation)
+		if err != nil {
+			return nil, err
+		}
+		for _, ami := range resp.Images {
+			image := imageFromAMI(ami)
+			if createdWithin(image.CreationDate, filter.CreatedAfter, filter.CreatedBefore) {
+				images = append(images, image)
+			}
+		}
+		if aws.ToString(resp.NextToken) == "" {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	// EC2 creation dates are RFC3339 in UTC, so they sort as strings
+	sort.SliceStable(images, func(i, j int) bool { return images[i].CreationDate > images[j].CreationDate })
+	return images, nil
+}
+
+// imageFilters translates filter to DescribeImages filters.
+func imageFilters(filter *services.ImageFilter) []types.Filter {
+	var filters []types.Filter
+	if filter.NamePattern != "" {
+		filters = append(filters, types.Filter{Name: aws.String("name"), Values: []string{filter.NamePattern}})
+	}
+	if filter.Architecture != "" {
+		filters = append(filters, types.Filter{Name: aws.String("architecture"), Values: []string{filter.Architecture}})
+	}
+	if len(filter.States) > 0 {
+		filters = append(filters, types.Filter{Name: aws.String("state"), Values: filter.States})
+	}
+	for _, key := range sortedKeys(filter.Tags) {
+		filters = append(filters, types.Filter{Name: aws.String("tag:" + key), Values: []string{filter.Tags[key]}})
+	}
+	return filters
+}
+
+// createdWithin reports whether creationDate falls within the window from
+// after to before, either of which may be zero to leave it open.
+func createdWithin(creationDate string, after, before time.Time) bool {
+	if after.IsZero() && before.IsZero() {
+		return true
+	}
+	created, err := time.Parse(time.RFC3339, creationDate)
+	if err != nil {
+		return false
+	}
+	return (after.IsZero() || !created.Before(after)) && (before.IsZero() || !created.After(before))
+}
+
+// describeImages makes one DescribeImages call with retries, reporting
+// errors as operation.
+func (s *ImagesServiceImpl) describeImages(ctx context.Context, input *ec2.DescribeImagesInput, operation string) (*ec2.DescribeImagesOutput, error) {
+	logRequest(ctx, s.logger, "DescribeImages", input)
+
+	var resp *ec2.DescribeImagesOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DescribeImages", func(ctx context.Context) error {
+		resp, err = s.client.DescribeImages(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DescribeImages", resp, retryErr)
+
+	if retryErr != nil {
+		return nil, wrapAWSError(retryErr, "aws", "compute", operation)
+	}
+	return resp, nil
+}
+
+// imageFromAMI converts an AMI to an Image.
+func imageFromAMI(ami types.Image) *services.Image {
+	return &services.Image{
+		ID:             aws.ToString(ami.ImageId),
+		Name:           aws.ToString(ami.Name),
+		Description:    aws.ToString(ami.Description),
+		OwnerID:        aws.ToString(ami.OwnerId),
+		OwnerAlias:     aws.ToString(ami.ImageOwnerAlias),
+		Architecture:   string(ami.Architecture),
+		State:          string(ami.State),
+		CreationDate:   aws.ToString(ami.CreationDate),
+		Public:         aws.ToBool(ami.Public),
+		Platform:       string(ami.Platform),
+		RootDeviceType: string(ami.RootDeviceType),
+		Tags:           tagMap(ami.Tags),
+	}
+}
+
+// Get gets an AMI by ID
+func (s *ImagesServiceImpl) Get(ctx context.Context, id string) (*services.Image, error) {
+	// Validate input
+	if id == "" {
+		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "id", "image ID cannot be empty")
+	}
+
+	resp, err := s.describeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []string{id}}, "GetImage")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Images) == 0 {
+		return nil, cloudsdk.NewResourceNotFoundError("aws", "compute", "image", id)
+	}
+
+	return imageFromAMI(resp.Images[0]), nil
+}
+
+// Latest returns the newest available AMI that matches filter
+func (s *ImagesServiceImpl) Latest(ctx context.Context, filter *services.ImageFilter) (*services.Image, error) {
+	available := services.ImageFilter{}
+	if filter != nil {
+		available = *filter
+	}
+	available.States = []string{string(types.ImageStateAvailable)}
+
+	images, err := s.listImages(ctx, &available, "LatestImage")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(images) == 0 {
+		return nil, cloudsdk.NewResourceNotFoundError("aws", "compute", "image", available.NamePattern).
+			WithSuggestions(
+				"Check the name pattern, architecture and owners of the filter",
+				"Images owned by other accounts need their owner in Owners, e.g. \"amazon\"",
+				"Verify the image is published in the current region",
+			)
+	}
+
+	return images[0], nil
+}
+
+// Create creates an AMI from an instance, tagging the AMI and its snapshots
+func (s *ImagesServiceImpl) Create(ctx context.Context, config *services.CreateImageConfig) (*services.Image, error) {
+	// Validate input configuration
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
+
+	input := &ec2.CreateImageInput{
+		InstanceId: aws.String(config.VMID),
+		Name:       aws.String(config.Name),
+	}
+	if config.Description != "" {
+		input.Description = aws.String(config.Description)
+	}
+	if config.NoReboot {
+		input.NoReboot = aws.Bool(true)
+	}
+	if tags := ec2Tags(config.Tags); len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{
+			{ResourceType: types.ResourceTypeImage, Tags: tags},
+			{ResourceType: types.ResourceTypeSnapshot, Tags: tags},
+		}
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "CreateImage", "CreateImage", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CreateImage(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "CreateImage", input)
+
+	// CreateImage has no client token, so it is not retried: a retry after a
+	// call that succeeded but timed out would fail with a duplicate name
+	resp, err := s.client.CreateImage(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "CreateImage", resp, err)
+
+	if err != nil {
+		return nil, wrapAWSError(err, "aws", "compute", "CreateImage")
+	}
+
+	return &services.Image{
+		ID:          aws.ToString(resp.ImageId),
+		Name:        config.Name,
+		Description: config.Description,
+		State:       string(types.ImageStatePending),
+		Tags:        config.Tags,
+	}, nil
+}
+
+// Deregister deregisters an AMI. Its snapshots are kept.
+func (s *ImagesServiceImpl) Deregister(ctx context.Context, id string) error {
+	// Validate input
+	if id == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "id", "image ID cannot be empty")
+	}
+
+	input := &ec2.DeregisterImageInput{
+		ImageId: aws.String(id),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "DeregisterImage", "DeregisterImage", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.DeregisterImage(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "DeregisterImage", input)
+
+	var resp *ec2.DeregisterImageOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DeregisterImage", func(ctx context.Context) error {
+		resp, err = s.client.DeregisterImage(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DeregisterImage", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "DeregisterImage")
+	}
+
+	return nil
+}
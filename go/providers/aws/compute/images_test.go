@@ -0,0 +1,224 @@
+package compute
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// amiEC2Client serves DescribeImages pages keyed by NextToken, the first
+// page under "", and records the input of each call.
+type amiEC2Client struct {
+	mockEC2Client
+	pages           map[string]*ec2.DescribeImagesOutput
+	describes       []*ec2.DescribeImagesInput
+	createInput     *ec2.CreateImageInput
+	deregisterInput *ec2.DeregisterImageInput
+}
+
+func (m *amiEC2Client) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, opts ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	m.describes = append(m.describes, input)
+	if m.describeImagesError != nil {
+		return nil, m.describeImagesError
+	}
+	return m.pages[aws.ToString(input.NextToken)], nil
+}
+
+func (m *amiEC2Client) CreateImage(ctx context.Context, input *ec2.CreateImageInput, opts ...func(*ec2.Options)) (*ec2.CreateImageOutput, error) {
+	m.createInput = input
+	if m.createImageError != nil {
+		return nil, m.createImageError
+	}
+	return &ec2.CreateImageOutput{ImageId: aws.String("ami-0123456789abcdef0")}, nil
+}
+
+func (m *amiEC2Client) DeregisterImage(ctx context.Context, input *ec2.DeregisterImageInput, opts ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error) {
+	m.deregisterInput = input
+	return &ec2.DeregisterImageOutput{}, m.deregisterImageError
+}
+
+// ami returns an available Amazon Linux AMI created on date.
+func ami(id, name, date string) types.Image {
+	return types.Image{
+		ImageId:         aws.String(id),
+		Name:            aws.String(name),
+		OwnerId:         aws.String("137112412989"),
+		ImageOwnerAlias: aws.String("amazon"),
+		Architecture:    types.ArchitectureValuesX8664,
+		State:           types.ImageStateAvailable,
+		CreationDate:    aws.String(date),
+		Public:          aws.Bool(true),
+		RootDeviceType:  types.DeviceTypeEbs,
+	}
+}
+
+func newAMIEC2Client() *amiEC2Client {
+	return &amiEC2Client{pages: map[string]*ec2.DescribeImagesOutput{
+		"": {
+			Images: []types.Image{
+				ami("ami-0000000000000001", "al2023-ami-2023.5.20240701.0-kernel-6.1-x86_64", "2024-07-01T18:25:33.000Z"),
+				ami("ami-0000000000000003", "al2023-ami-2023.4.20240401.0-kernel-6.1-x86_64", "2024-04-01T10:00:00.000Z"),
+			},
+			NextToken: aws.String("page-2"),
+		},
+		"page-2": {
+			Images: []types.Image{
+				ami("ami-0000000000000002", "al2023-ami-2023.6.20241010.0-kernel-6.1-x86_64", "2024-10-10T20:14:05.000Z"),
+			},
+		},
+	}}
+}
+
+func TestAWSImages_List(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newAMIEC2Client()
+	images := NewWithClient(client).Images()
+
+	// Every page is fetched and the images are sorted newest first
+	list, err := images.List(ctx, &services.ImageFilter{
+		Owners:       []string{"amazon"},
+		NamePattern:  "al2023-ami-2023.*-x86_64",
+		Architecture: "x86_64",
+		Tags:         map[string]string{"Release": "stable"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(3, len(list))
+	helper.AssertEqual(2, len(client.describes))
+	helper.AssertEqual("ami-0000000000000002", list[0].ID)
+	helper.AssertEqual("ami-0000000000000003", list[2].ID)
+	helper.AssertEqual("amazon", list[0].OwnerAlias)
+	helper.AssertEqual("x86_64", list[0].Architecture)
+	helper.AssertEqual(true, list[0].Public)
+
+	input := client.describes[0]
+	helper.AssertEqual("amazon", input.Owners[0])
+	helper.AssertEqual(3, len(input.Filters))
+	helper.AssertEqual("name", aws.ToString(input.Filters[0].Name))
+	helper.AssertEqual("al2023-ami-2023.*-x86_64", input.Filters[0].Values[0])
+	helper.AssertEqual("architecture", aws.ToString(input.Filters[1].Name))
+	helper.AssertEqual("tag:Release", aws.ToString(input.Filters[2].Name))
+
+	// The creation date window is applied to the results
+	list, err = images.List(ctx, &services.ImageFilter{
+		Owners:        []string{"amazon"},
+		CreatedAfter:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBefore: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(list))
+	helper.AssertEqual("ami-0000000000000001", list[0].ID)
+
+	// Without owners only the account's own images are listed
+	client.describes = nil
+	_, err = images.List(ctx, nil)
+	helper.AssertNoError(err)
+	helper.AssertEqual("self", client.describes[0].Owners[0])
+	helper.AssertEqual(int32(maxImagePageSize), aws.ToInt32(client.describes[0].MaxResults))
+}
+
+func TestAWSImages_LatestAndGet(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newAMIEC2Client()
+	images := NewWithClient(client).Images()
+
+	image, err := images.Latest(ctx, &services.ImageFilter{Owners: []string{"amazon"}, NamePattern: "al2023-ami-*"})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ami-0000000000000002", image.ID)
+	// Only available images are candidates
+	states := client.describes[0].Filters[1]
+	helper.AssertEqual("state", aws.ToString(states.Name))
+	helper.AssertEqual("available", states.Values[0])
+
+	client.pages = map[string]*ec2.DescribeImagesOutput{"": {}}
+	_, err = images.Latest(ctx, &services.ImageFilter{Owners: []string{"amazon"}, NamePattern: "missing-*"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	client.pages = map[string]*ec2.DescribeImagesOutput{"": {Images: []types.Image{ami("ami-0000000000000001", "al2023", "2024-07-01T18:25:33.000Z")}}}
+	image, err = images.Get(ctx, "ami-0000000000000001")
+	helper.AssertNoError(err)
+	helper.AssertEqual("al2023", image.Name)
+	helper.AssertEqual("ami-0000000000000001", client.describes[len(client.describes)-1].ImageIds[0])
+	if client.describes[len(client.describes)-1].MaxResults != nil {
+		t.Error("expected no MaxResults with image IDs")
+	}
+
+	// A missing image is not found, rather than an invalid launch configuration
+	client.describeImagesError = &smithy.GenericAPIError{Code: "InvalidAMIID.NotFound", Message: "The image id '[ami-0000000000000009]' does not exist"}
+	_, err = images.Get(ctx, "ami-0000000000000009")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	helper.AssertContains(err.Error(), "ami-0000000000000009")
+}
+
+func TestAWSImages_CreateAndDeregister(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newAMIEC2Client()
+	images := NewWithClient(client).Images()
+
+	image, err := images.Create(ctx, &services.CreateImageConfig{
+		VMID:        "i-1234567890abcdef0",
+		Name:        "golden-web-2024-10-16",
+		Description: "web server golden image",
+		NoReboot:    true,
+		Tags:        map[string]string{"Role": "web"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("ami-0123456789abcdef0", image.ID)
+	helper.AssertEqual("pending", image.State)
+	helper.AssertEqual("i-1234567890abcdef0", aws.ToString(client.createInput.InstanceId))
+	helper.AssertEqual(true, aws.ToBool(client.createInput.NoReboot))
+	helper.AssertEqual(2, len(client.createInput.TagSpecifications))
+	helper.AssertEqual(types.ResourceTypeImage, client.createInput.TagSpecifications[0].ResourceType)
+
+	_, err = images.Create(ctx, &services.CreateImageConfig{VMID: "i-1234567890abcdef0", Name: "x"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	client.createImageError = &smithy.GenericAPIError{Code: "InvalidAMIName.Duplicate", Message: "AMI name golden-web-2024-10-16 is already in use by AMI ami-0123456789abcdef0"}
+	_, err = images.Create(ctx, &services.CreateImageConfig{VMID: "i-1234567890abcdef0", Name: "golden-web-2024-10-16"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+
+	helper.AssertNoError(images.Deregister(ctx, "ami-0123456789abcdef0"))
+	helper.AssertEqual("ami-0123456789abcdef0", aws.ToString(client.deregisterInput.ImageId))
+
+	client.deregisterImageError = &smithy.GenericAPIError{Code: "InvalidAMIID.NotFound", Message: "The image id '[ami-0123456789abcdef0]' does not exist"}
+	helper.AssertErrorCode(images.Deregister(ctx, "ami-0123456789abcdef0"), cloudsdk.ErrResourceNotFound)
+
+	// Launching from a missing image is still an invalid configuration
+	mockClient := &mockEC2Client{runInstancesError: &smithy.GenericAPIError{Code: "InvalidAMIID.NotFound", Message: "The image id '[ami-0123456789abcdef0]' does not exist"}}
+	_, err = NewWithClient(mockClient).CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-server"))
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+}
+
+func TestAWSImages_DryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	client := newAMIEC2Client()
+	client.createImageError = &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."}
+	images := NewWithClient(client).Images()
+	ctx, plan := cloudsdk.ContextWithPlan(context.Background())
+
+	image, err := images.Create(ctx, &services.CreateImageConfig{VMID: "i-1234567890abcdef0", Name: "golden-web"})
+	helper.AssertNoError(err)
+	if image != nil {
+		t.Errorf("expected no image from a dry run, got %+v", image)
+	}
+	helper.AssertEqual(true, aws.ToBool(client.createInput.DryRun))
+
+	calls := plan.Calls()
+	helper.AssertEqual(1, len(calls))
+	helper.AssertEqual("CreateImage", calls[0].API)
+	helper.AssertEqual(cloudsdk.OpCreateImage, calls[0].Operation)
+}
@@ -0,0 +1,228 @@
+package compute
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// KeyPairsServiceImpl implements KeyPairsService with EC2 key pairs
+type KeyPairsServiceImpl struct {
+	client      EC2ClientInterface
+	logger      *slog.Logger
+	retryConfig RetryConfig
+}
+
+// Create generates a key pair. EC2 returns ed25519 private keys in OpenSSH
+// format and RSA private keys in PKCS #1 PEM format. It doesn't return the
+// public key, which List does.
+func (s *KeyPairsServiceImpl) Create(ctx context.Context, config *services.KeyPairConfig) (*services.KeyPair, error) {
+	// Validate input configuration
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
+
+	keyType := config.KeyType
+	if keyType == "" {
+		keyType = string(types.KeyTypeEd25519)
+	}
+
+	input := &ec2.CreateKeyPairInput{
+		KeyName:   aws.String(config.Name),
+		KeyType:   types.KeyType(keyType),
+		KeyFormat: types.KeyFormatPem,
+	}
+	if tags := ec2Tags(config.Tags); len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{
+			{ResourceType: types.ResourceTypeKeyPair, Tags: tags},
+		}
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "CreateKeyPair", "CreateKeyPair", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CreateKeyPair(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "CreateKeyPair", input)
+
+	// CreateKeyPair has no client token, so it is not retried: a retry after
+	// a call that succeeded but timed out would fail with a duplicate name,
+	// and the private key from the first call would be lost
+	resp, err := s.client.CreateKeyPair(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "CreateKeyPair", resp, err)
+
+	if err != nil {
+		return nil, wrapAWSError(err, "aws", "compute", "CreateKeyPair")
+	}
+
+	return &services.KeyPair{
+		ID:          aws.ToString(resp.KeyPairId),
+		Name:        aws.ToString(resp.KeyName),
+		KeyType:     keyType,
+		Fingerprint: aws.ToString(resp.KeyFingerprint),
+		PrivateKey:  aws.ToString(resp.KeyMaterial),
+		CreateTime:  time.Now().UTC().Format(time.RFC3339),
+		Tags:        tagMap(resp.Tags),
+	}, nil
+}
+
+// Import imports a public key in OpenSSH authorized_keys format
+func (s *KeyPairsServiceImpl) Import(ctx context.Context, config *services.ImportKeyPairConfig) (*services.KeyPair, error) {
+	// Validate input configuration
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
+
+	publicKey := strings.TrimSpace(config.PublicKey)
+	input := &ec2.ImportKeyPairInput{
+		KeyName:           aws.String(config.Name),
+		PublicKeyMaterial: []byte(publicKey),
+	}
+	if tags := ec2Tags(config.Tags); len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{
+			{ResourceType: types.ResourceTypeKeyPair, Tags: tags},
+		}
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "ImportKeyPair", "ImportKeyPair", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.ImportKeyPair(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "ImportKeyPair", input)
+
+	// ImportKeyPair has no client token, so it is not retried: a retry after
+	// a call that succeeded but timed out would fail with a duplicate name
+	resp, err := s.client.ImportKeyPair(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "ImportKeyPair", resp, err)
+
+	if err != nil {
+		return nil, wrapAWSError(err, "aws", "compute", "ImportKeyPair")
+	}
+
+	return &services.KeyPair{
+		ID:          aws.ToString(resp.KeyPairId),
+		Name:        aws.ToString(resp.KeyName),
+		KeyType:     keyTypeOf(publicKey),
+		Fingerprint: aws.ToString(resp.KeyFingerprint),
+		PublicKey:   publicKey,
+		CreateTime:  time.Now().UTC().Format(time.RFC3339),
+		Tags:        tagMap(resp.Tags),
+	}, nil
+}
+
+// keyTypeOf returns the key type of a public key in OpenSSH format
+func keyTypeOf(publicKey string) string {
+	switch {
+	case strings.HasPrefix(publicKey, "ssh-ed25519 "):
+		return string(types.KeyTypeEd25519)
+	case strings.HasPrefix(publicKey, "ssh-rsa "):
+		return string(types.KeyTypeRsa)
+	default:
+		return ""
+	}
+}
+
+// List lists every key pair in the region with its public key. EC2 doesn't
+// paginate DescribeKeyPairs.
+func (s *KeyPairsServiceImpl) List(ctx context.Context) ([]*services.KeyPair, error) {
+	input := &ec2.DescribeKeyPairsInput{
+		IncludePublicKey: aws.Bool(true),
+	}
+
+	logRequest(ctx, s.logger, "DescribeKeyPairs", input)
+
+	var resp *ec2.DescribeKeyPairsOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DescribeKeyPairs", func(ctx context.Context) error {
+		resp, err = s.client.DescribeKeyPairs(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DescribeKeyPairs", resp, retryErr)
+
+	if retryErr != nil {
+		return nil, wrapAWSError(retryErr, "aws", "compute", "ListKeyPairs")
+	}
+
+	keyPairs := make([]*services.KeyPair, 0, len(resp.KeyPairs))
+	for _, info := range resp.KeyPairs {
+		keyPair := &services.KeyPair{
+			ID:          aws.ToString(info.KeyPairId),
+			Name:        aws.ToString(info.KeyName),
+			KeyType:     string(info.KeyType),
+			Fingerprint: aws.ToString(info.KeyFingerprint),
+			PublicKey:   strings.TrimSpace(aws.ToString(info.PublicKey)),
+			Tags:        tagMap(info.Tags),
+		}
+		if info.CreateTime != nil {
+			keyPair.CreateTime = info.CreateTime.UTC().Format(time.RFC3339)
+		}
+		keyPairs = append(keyPairs, keyPair)
+	}
+	sort.Slice(keyPairs, func(i, j int) bool { return keyPairs[i].Name < keyPairs[j].Name })
+
+	return keyPairs, nil
+}
+
+// Delete deletes a key pair by name. EC2 reports success for key pairs that
+// don't exist.
+func (s *KeyPairsServiceImpl) Delete(ctx context.Context, name string) error {
+	// Validate input
+	if name == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "name", "key pair name cannot be empty")
+	}
+
+	input := &ec2.DeleteKeyPairInput{
+		KeyName: aws.String(name),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "DeleteKeyPair", "DeleteKeyPair", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.DeleteKeyPair(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "DeleteKeyPair", input)
+
+	var resp *ec2.DeleteKeyPairOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DeleteKeyPair", func(ctx context.Context) error {
+		resp, err = s.client.DeleteKeyPair(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DeleteKeyPair", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "DeleteKeyPair")
+	}
+
+	return nil
+}
@@ -0,0 +1,463 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// SecurityGroupsServiceImpl implements SecurityGroupsService with EC2
+// security groups
+type SecurityGroupsServiceImpl struct {
+	client      EC2ClientInterface
+	logger      *slog.Logger
+	retryConfig RetryConfig
+}
+
+// Create creates a VPC security group. The returned group has the egress rule
+// EC2 adds to every new group; its NetworkID is empty if the group was
+// created in the default VPC.
+func (s *SecurityGroupsServiceImpl) Create(ctx context.Context, config *services.SecurityGroupConfig) (*services.SecurityGroup, error) {
+	// Validate input configuration
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
+
+	// EC2 requires a description
+	description := config.Description
+	if description == "" {
+		description = config.Name
+	}
+
+	input := &ec2.CreateSecurityGroupInput{
+		GroupName:   aws.String(config.Name),
+		Description: aws.String(description),
+	}
+	if config.NetworkID != "" {
+		input.VpcId = aws.String(config.NetworkID)
+	}
+	if tags := ec2Tags(config.Tags); len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{
+			{ResourceType: types.ResourceTypeSecurityGroup, Tags: tags},
+		}
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "CreateSecurityGroup", "CreateSecurityGroup", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CreateSecurityGroup(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "CreateSecurityGroup", input)
+
+	// CreateSecurityGroup has no client token, so it is not retried: a retry
+	// after a call that succeeded but timed out would fail with a duplicate
+	// name
+	resp, err := s.client.CreateSecurityGroup(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "CreateSecurityGroup", resp, err)
+
+	if err != nil {
+		return nil, wrapAWSError(err, "aws", "compute", "CreateSecurityGroup")
+	}
+
+	return &services.SecurityGroup{
+		ID:          aws.ToString(resp.GroupId),
+		Name:        config.Name,
+		Description: description,
+		NetworkID:   config.NetworkID,
+		Rules: []services.FirewallRule{
+			{Direction: "egress", Protocol: "all", CIDR: "0.0.0.0/0"},
+		},
+		Tags: tagMap(resp.Tags),
+	}, nil
+}
+
+// List lists security groups, filtered by EC2 and following NextToken across
+// every page
+func (s *SecurityGroupsServiceImpl) List(ctx context.Context, filter *services.SecurityGroupFilter) ([]*services.SecurityGroup, error) {
+	input := &ec2.DescribeSecurityGroupsInput{Filters: securityGroupFilters(filter)}
+
+	groups := []*services.SecurityGroup{}
+	for {
+		resp, err := s.describeSecurityGroups(ctx, input, "ListSecurityGroups")
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range resp.SecurityGroups {
+			groups = append(groups, securityGroupFromEC2(group))
+		}
+		if aws.ToString(resp.NextToken) == "" {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups, nil
+}
+
+// securityGroupFilters translates filter to DescribeSecurityGroups filters.
+func securityGroupFilters(filter *services.SecurityGroupFilter) []types.Filter {
+	if filter == nil {
+		return nil
+	}
+	var filters []types.Filter
+	if filter.Name != "" {
+		filters = append(filters, types.Filter{Name: aws.String("group-name"), Values: []string{filter.Name}})
+	}
+	if filter.NetworkID != "" {
+		filters = append(filters, types.Filter{Name: aws.String("vpc-id"), Values: []string{filter.NetworkID}})
+	}
+	for _, key := range sortedKeys(filter.Tags) {
+		filters = append(filters, types.Filter{Name: aws.String("tag:" + key), Values: []string{filter.Tags[key]}})
+	}
+	return filters
+}
+
+// Get gets a security group by ID
+func (s *SecurityGroupsServiceImpl) Get(ctx context.Context, id string) (*services.SecurityGroup, error) {
+	// Validate input
+	if id == "" {
+		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "id", "security group ID cannot be empty")
+	}
+
+	resp, err := s.describeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: []string{id}}, "GetSecurityGroup")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.SecurityGroups) == 0 {
+		return nil, cloudsdk.NewResourceNotFoundError("aws", "compute", "security group", id)
+	}
+
+	return securityGroupFromEC2(resp.SecurityGroups[0]), nil
+}
+
+// describeSecurityGroups makes one DescribeSecurityGroups call with retries,
+// reporting errors as operation.
+func (s *SecurityGroupsServiceImpl) describeSecurityGroups(ctx context.Context, input *ec2.DescribeSecurityGroupsInput, operation string) (*ec2.DescribeSecurityGroupsOutput, error) {
+	logRequest(ctx, s.logger, "DescribeSecurityGroups", input)
+
+	var resp *ec2.DescribeSecurityGroupsOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DescribeSecurityGroups", func(ctx context.Context) error {
+		resp, err = s.client.DescribeSecurityGroups(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DescribeSecurityGroups", resp, retryErr)
+
+	if retryErr != nil {
+		return nil, wrapAWSError(retryErr, "aws", "compute", operation)
+	}
+	return resp, nil
+}
+
+// securityGroupFromEC2 converts an EC2 security group to a SecurityGroup.
+func securityGroupFromEC2(group types.SecurityGroup) *services.SecurityGroup {
+	rules := rulesFromIPPermissions("ingress", group.IpPermissions)
+	rules = append(rules, rulesFromIPPermissions("egress", group.IpPermissionsEgress)...)
+	return &services.SecurityGroup{
+		ID:          aws.ToString(group.GroupId),
+		Name:        aws.ToString(group.GroupName),
+		Description: aws.ToString(group.Description),
+		NetworkID:   aws.ToString(group.VpcId),
+		Rules:       rules,
+		Tags:        tagMap(group.Tags),
+	}
+}
+
+// rulesFromIPPermissions converts EC2 IP permissions to one FirewallRule per
+// CIDR block and group. Rules on prefix lists have no provider-neutral
+// equivalent and are left out, and ICMP rules for specific types are listed
+// as matching every type.
+func rulesFromIPPermissions(direction string, permissions []types.IpPermission) []services.FirewallRule {
+	var rules []services.FirewallRule
+	for _, permission := range permissions {
+		rule := services.FirewallRule{Direction: direction}
+		switch protocol := aws.ToString(permission.IpProtocol); protocol {
+		case "-1":
+			rule.Protocol = "all"
+		case "tcp", "6":
+			rule.Protocol = "tcp"
+		case "udp", "17":
+			rule.Protocol = "udp"
+		case "icmp", "1":
+			rule.Protocol = "icmp"
+		default:
+			rule.Protocol = protocol
+		}
+		if rule.Protocol == "tcp" || rule.Protocol == "udp" {
+			rule.FromPort = aws.ToInt32(permission.FromPort)
+			rule.ToPort = aws.ToInt32(permission.ToPort)
+		}
+
+		for _, ipRange := range permission.IpRanges {
+			cidr := rule
+			cidr.CIDR = aws.ToString(ipRange.CidrIp)
+			cidr.Description = aws.ToString(ipRange.Description)
+			rules = append(rules, cidr)
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			cidr := rule
+			cidr.CIDR = aws.ToString(ipRange.CidrIpv6)
+			cidr.Description = aws.ToString(ipRange.Description)
+			rules = append(rules, cidr)
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			group := rule
+			group.SourceGroupID = aws.ToString(pair.GroupId)
+			group.Description = aws.ToString(pair.Description)
+			rules = append(rules, group)
+		}
+	}
+	return rules
+}
+
+// ipPermissions converts the rules with direction to EC2 IP permissions, one
+// per rule.
+func ipPermissions(rules []services.FirewallRule, direction string) []types.IpPermission {
+	var permissions []types.IpPermission
+	for _, rule := range rules {
+		if rule.Direction != direction {
+			continue
+		}
+
+		var permission types.IpPermission
+		switch rule.Protocol {
+		case "tcp", "udp":
+			toPort := rule.ToPort
+			if toPort == 0 {
+				toPort = rule.FromPort
+			}
+			permission.IpProtocol = aws.String(rule.Protocol)
+			permission.FromPort = aws.Int32(rule.FromPort)
+			permission.ToPort = aws.Int32(toPort)
+		case "icmp":
+			// -1 matches every ICMP type and code
+			permission.IpProtocol = aws.String("icmp")
+			permission.FromPort = aws.Int32(-1)
+			permission.ToPort = aws.Int32(-1)
+		case "all":
+			permission.IpProtocol = aws.String("-1")
+		}
+
+		var description *string
+		if rule.Description != "" {
+			description = aws.String(rule.Description)
+		}
+		switch {
+		case rule.SourceGroupID != "":
+			permission.UserIdGroupPairs = []types.UserIdGroupPair{{GroupId: aws.String(rule.SourceGroupID), Description: description}}
+		case strings.Contains(rule.CIDR, ":"):
+			permission.Ipv6Ranges = []types.Ipv6Range{{CidrIpv6: aws.String(rule.CIDR), Description: description}}
+		default:
+			permission.IpRanges = []types.IpRange{{CidrIp: aws.String(rule.CIDR), Description: description}}
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+// AddRules authorizes rules on a security group. Ingress and egress rules are
+// authorized in separate calls, ingress first, so if the egress call fails
+// the ingress rules have still been added.
+func (s *SecurityGroupsServiceImpl) AddRules(ctx context.Context, groupID string, rules []services.FirewallRule) error {
+	// Validate input
+	if groupID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "groupID", "security group ID cannot be empty")
+	}
+	if err := cloudsdk.ValidateFirewallRules("aws", "compute", rules); err != nil {
+		return err
+	}
+
+	if ingress := ipPermissions(rules, "ingress"); len(ingress) > 0 {
+		input := &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: ingress,
+		}
+		err := s.send(ctx, "AuthorizeSecurityGroupIngress", input, func(ctx context.Context, dryRun bool) (interface{}, error) {
+			call := *input
+			call.DryRun = aws.Bool(dryRun)
+			return s.client.AuthorizeSecurityGroupIngress(ctx, &call, ec2Options(ctx)...)
+		})
+		if err != nil {
+			return wrapAWSError(err, "aws", "compute", "AddSecurityGroupRules")
+		}
+	}
+
+	if egress := ipPermissions(rules, "egress"); len(egress) > 0 {
+		input := &ec2.AuthorizeSecurityGroupEgressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: egress,
+		}
+		err := s.send(ctx, "AuthorizeSecurityGroupEgress", input, func(ctx context.Context, dryRun bool) (interface{}, error) {
+			call := *input
+			call.DryRun = aws.Bool(dryRun)
+			return s.client.AuthorizeSecurityGroupEgress(ctx, &call, ec2Options(ctx)...)
+		})
+		if err != nil {
+			return wrapAWSError(err, "aws", "compute", "AddSecurityGroupRules")
+		}
+	}
+
+	return nil
+}
+
+// RevokeRules revokes rules from a security group, ingress then egress like
+// AddRules. EC2 reports rules the group doesn't have either as an error or
+// as unknown permissions in its response; both are ErrResourceNotFound.
+func (s *SecurityGroupsServiceImpl) RevokeRules(ctx context.Context, groupID string, rules []services.FirewallRule) error {
+	// Validate input
+	if groupID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "groupID", "security group ID cannot be empty")
+	}
+	if err := cloudsdk.ValidateFirewallRules("aws", "compute", rules); err != nil {
+		return err
+	}
+
+	if ingress := ipPermissions(rules, "ingress"); len(ingress) > 0 {
+		input := &ec2.RevokeSecurityGroupIngressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: ingress,
+		}
+		var unknown []types.IpPermission
+		err := s.send(ctx, "RevokeSecurityGroupIngress", input, func(ctx context.Context, dryRun bool) (interface{}, error) {
+			call := *input
+			call.DryRun = aws.Bool(dryRun)
+			resp, err := s.client.RevokeSecurityGroupIngress(ctx, &call, ec2Options(ctx)...)
+			if resp != nil {
+				unknown = resp.UnknownIpPermissions
+			}
+			return resp, err
+		})
+		if err != nil {
+			return wrapAWSError(err, "aws", "compute", "RevokeSecurityGroupRules")
+		}
+		if len(unknown) > 0 {
+			return unknownRulesError(groupID, "ingress", unknown)
+		}
+	}
+
+	if egress := ipPermissions(rules, "egress"); len(egress) > 0 {
+		input := &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: egress,
+		}
+		var unknown []types.IpPermission
+		err := s.send(ctx, "RevokeSecurityGroupEgress", input, func(ctx context.Context, dryRun bool) (interface{}, error) {
+			call := *input
+			call.DryRun = aws.Bool(dryRun)
+			resp, err := s.client.RevokeSecurityGroupEgress(ctx, &call, ec2Options(ctx)...)
+			if resp != nil {
+				unknown = resp.UnknownIpPermissions
+			}
+			return resp, err
+		})
+		if err != nil {
+			return wrapAWSError(err, "aws", "compute", "RevokeSecurityGroupRules")
+		}
+		if len(unknown) > 0 {
+			return unknownRulesError(groupID, "egress", unknown)
+		}
+	}
+
+	return nil
+}
+
+// unknownRulesError reports the rules EC2 didn't find on a security group
+func unknownRulesError(groupID, direction string, unknown []types.IpPermission) error {
+	rules := rulesFromIPPermissions(direction, unknown)
+	id := fmt.Sprintf("%s %s", groupID, direction)
+	if len(rules) > 0 {
+		rule := rules[0]
+		peer := rule.CIDR
+		if peer == "" {
+			peer = rule.SourceGroupID
+		}
+		id = fmt.Sprintf("%s %s %s/%d-%d with %s", groupID, direction, rule.Protocol, rule.FromPort, rule.ToPort, peer)
+	}
+	return cloudsdk.NewResourceNotFoundError("aws", "compute", "security group rule", id).
+		WithSuggestions(
+			"Use SecurityGroups().Get to check the group's current rules",
+			"Rules only match when protocol, ports and CIDR or group are all equal",
+		)
+}
+
+// send makes a rule change in a security group, or plans it in a dry run.
+// call makes the request, with DryRun set in a dry run. Rule changes have no
+// client token, so they are not retried: a retry after a call that succeeded
+// but timed out would fail because the rules were already changed.
+func (s *SecurityGroupsServiceImpl) send(ctx context.Context, api string, input interface{}, call func(ctx context.Context, dryRun bool) (interface{}, error)) error {
+	operation := "AddSecurityGroupRules"
+	if strings.HasPrefix(api, "Revoke") {
+		operation = "RevokeSecurityGroupRules"
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, operation, api, input, func(ctx context.Context) error {
+			_, err := call(ctx, true)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, api, input)
+	resp, err := call(ctx, false)
+	logResponse(ctx, s.logger, api, resp, err)
+	return err
+}
+
+// Delete deletes a security group
+func (s *SecurityGroupsServiceImpl) Delete(ctx context.Context, id string) error {
+	// Validate input
+	if id == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "id", "security group ID cannot be empty")
+	}
+
+	input := &ec2.DeleteSecurityGroupInput{
+		GroupId: aws.String(id),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "DeleteSecurityGroup", "DeleteSecurityGroup", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.DeleteSecurityGroup(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "DeleteSecurityGroup", input)
+
+	var resp *ec2.DeleteSecurityGroupOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DeleteSecurityGroup", func(ctx context.Context) error {
+		resp, err = s.client.DeleteSecurityGroup(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DeleteSecurityGroup", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "DeleteSecurityGroup")
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package compute
+
+import (
+	"context"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// securityGroupEC2Client records the input of each security group call.
+type securityGroupEC2Client struct {
+	mockEC2Client
+	createInput    *ec2.CreateSecurityGroupInput
+	createCalls    int
+	describeInputs []ec2.DescribeSecurityGroupsInput
+	ingressInput   *ec2.AuthorizeSecurityGroupIngressInput
+	egressInput    *ec2.AuthorizeSecurityGroupEgressInput
+	revokeInput    *ec2.RevokeSecurityGroupIngressInput
+	deleteInput    *ec2.DeleteSecurityGroupInput
+	pages          []*ec2.DescribeSecurityGroupsOutput
+}
+
+func (m *securityGroupEC2Client) CreateSecurityGroup(ctx context.Context, input *ec2.CreateSecurityGroupInput, opts ...func(*ec2.Options)) (*ec2.CreateSecurityGroupOutput, error) {
+	m.createInput = input
+	m.createCalls++
+	if m.createSecurityGroupError != nil {
+		return nil, m.createSecurityGroupError
+	}
+	var tags []types.Tag
+	for _, spec := range input.TagSpecifications {
+		tags = append(tags, spec.Tags...)
+	}
+	return &ec2.CreateSecurityGroupOutput{GroupId: aws.String("sg-0123456789abcdef0"), Tags: tags}, nil
+}
+
+func (m *securityGroupEC2Client) DescribeSecurityGroups(ctx context.Context, input *ec2.DescribeSecurityGroupsInput, opts ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	m.describeInputs = append(m.describeInputs, *input)
+	if m.describeSecurityGroupsError != nil {
+		return nil, m.describeSecurityGroupsError
+	}
+	page := m.pages[0]
+	m.pages = m.pages[1:]
+	return page, nil
+}
+
+func (m *securityGroupEC2Client) AuthorizeSecurityGroupIngress(ctx context.Context, input *ec2.AuthorizeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	m.ingressInput = input
+	return &ec2.AuthorizeSecurityGroupIngressOutput{Return: aws.Bool(true)}, m.authorizeSecurityGroupError
+}
+
+func (m *securityGroupEC2Client) AuthorizeSecurityGroupEgress(ctx context.Context, input *ec2.AuthorizeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupEgressOutput, error) {
+	m.egressInput = input
+	return &ec2.AuthorizeSecurityGroupEgressOutput{Return: aws.Bool(true)}, m.authorizeSecurityGroupError
+}
+
+func (m *securityGroupEC2Client) RevokeSecurityGroupIngress(ctx context.Context, input *ec2.RevokeSecurityGroupIngressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	m.revokeInput = input
+	return m.mockEC2Client.RevokeSecurityGroupIngress(ctx, input, opts...)
+}
+
+func (m *securityGroupEC2Client) DeleteSecurityGroup(ctx context.Context, input *ec2.DeleteSecurityGroupInput, opts ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error) {
+	m.deleteInput = input
+	return &ec2.DeleteSecurityGroupOutput{}, m.deleteSecurityGroupError
+}
+
+func TestAWSSecurityGroups_Create(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := &securityGroupEC2Client{}
+	groups := NewWithClient(client).SecurityGroups()
+
+	group, err := groups.Create(ctx, &services.SecurityGroupConfig{
+		Name:      "web",
+		NetworkID: "vpc-0123456789abcdef0",
+		Tags:      map[string]string{"Team": "platform"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("sg-0123456789abcdef0", group.ID)
+	helper.AssertEqual("web", group.Description)
+	helper.AssertEqual("platform", group.Tags["Team"])
+	helper.AssertEqual(1, len(group.Rules))
+	helper.AssertEqual("egress", group.Rules[0].Direction)
+	helper.AssertEqual("web", aws.ToString(client.createInput.Description))
+	helper.AssertEqual("vpc-0123456789abcdef0", aws.ToString(client.createInput.VpcId))
+	helper.AssertEqual(types.ResourceTypeSecurityGroup, client.createInput.TagSpecifications[0].ResourceType)
+
+	_, err = groups.Create(ctx, &services.SecurityGroupConfig{})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	// A duplicate name is a conflict, and isn't retried
+	client.createCalls = 0
+	client.createSecurityGroupError = &smithy.GenericAPIError{Code: "InvalidGroup.Duplicate", Message: "The security group 'web' already exists for VPC 'vpc-0123456789abcdef0'"}
+	_, err = groups.Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	helper.AssertEqual(1, client.createCalls)
+}
+
+func TestAWSSecurityGroups_ListAndGet(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := &securityGroupEC2Client{pages: []*ec2.DescribeSecurityGroupsOutput{
+		{
+			SecurityGroups: []types.SecurityGroup{{
+				GroupId:   aws.String("sg-0000000000000002"),
+				GroupName: aws.String("db"),
+				VpcId:     aws.String("vpc-0123456789abcdef0"),
+				IpPermissions: []types.IpPermission{{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int32(5432),
+					ToPort:           aws.Int32(5432),
+					UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-0000000000000001")}},
+				}},
+			}},
+			NextToken: aws.String("page-2"),
+		},
+		{
+			SecurityGroups: []types.SecurityGroup{{
+				GroupId:   aws.String("sg-0000000000000001"),
+				GroupName: aws.String("web"),
+				IpPermissions: []types.IpPermission{{
+					IpProtocol: aws.String("tcp"),
+					FromPort:   aws.Int32(443),
+					ToPort:     aws.Int32(443),
+					IpRanges:   []types.IpRange{{CidrIp: aws.String("0.0.0.0/0"), Description: aws.String("HTTPS")}},
+					Ipv6Ranges: []types.Ipv6Range{{CidrIpv6: aws.String("::/0")}},
+				}},
+				IpPermissionsEgress: []types.IpPermission{{
+					IpProtocol: aws.String("-1"),
+					IpRanges:   []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+				}},
+			}},
+		},
+	}}
+	groups := NewWithClient(client).SecurityGroups()
+
+	list, err := groups.List(ctx, &services.SecurityGroupFilter{
+		NetworkID: "vpc-0123456789abcdef0",
+		Tags:      map[string]string{"Team": "platform"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(client.describeInputs))
+	helper.AssertEqual("page-2", aws.ToString(client.describeInputs[1].NextToken))
+	filters := client.describeInputs[0].Filters
+	helper.AssertEqual(2, len(filters))
+	helper.AssertEqual("vpc-id", aws.ToString(filters[0].Name))
+	helper.AssertEqual("tag:Team", aws.ToString(filters[1].Name))
+
+	helper.AssertEqual(2, len(list))
+	web := list[0]
+	helper.AssertEqual("web", web.Name)
+	helper.AssertEqual(3, len(web.Rules))
+	helper.AssertEqual(services.FirewallRule{Direction: "ingress", Protocol: "tcp", FromPort: 443, ToPort: 443, CIDR: "0.0.0.0/0", Description: "HTTPS"}, web.Rules[0])
+	helper.AssertEqual("::/0", web.Rules[1].CIDR)
+	helper.AssertEqual(services.FirewallRule{Direction: "egress", Protocol: "all", CIDR: "0.0.0.0/0"}, web.Rules[2])
+	helper.AssertEqual("sg-0000000000000001", list[1].Rules[0].SourceGroupID)
+
+	client.pages = []*ec2.DescribeSecurityGroupsOutput{{}}
+	_, err = groups.Get(ctx, "sg-0000000000000003")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	helper.AssertEqual("sg-0000000000000003", client.describeInputs[2].GroupIds[0])
+
+	client.describeSecurityGroupsError = &smithy.GenericAPIError{Code: "InvalidGroup.NotFound", Message: "The security group 'sg-0000000000000004' does not exist"}
+	_, err = groups.Get(ctx, "sg-0000000000000004")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	helper.AssertContains(err.Error(), "sg-0000000000000004")
+}
+
+func TestAWSSecurityGroups_Rules(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := &securityGroupEC2Client{}
+	groups := NewWithClient(client).SecurityGroups()
+
+	err := groups.AddRules(ctx, "sg-0123456789abcdef0", []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"},
+		{Direction: "ingress", Protocol: "icmp", CIDR: "::/0"},
+		{Direction: "egress", Protocol: "all", SourceGroupID: "sg-0000000000000001", Description: "Peers"},
+	})
+	helper.AssertNoError(err)
+
+	ingress := client.ingressInput.IpPermissions
+	helper.AssertEqual(2, len(ingress))
+	helper.AssertEqual(int32(443), aws.ToInt32(ingress[0].ToPort))
+	helper.AssertEqual("0.0.0.0/0", aws.ToString(ingress[0].IpRanges[0].CidrIp))
+	helper.AssertEqual(int32(-1), aws.ToInt32(ingress[1].FromPort))
+	helper.AssertEqual("::/0", aws.ToString(ingress[1].Ipv6Ranges[0].CidrIpv6))
+	egress := client.egressInput.IpPermissions
+	helper.AssertEqual(1, len(egress))
+	helper.AssertEqual("-1", aws.ToString(egress[0].IpProtocol))
+	helper.AssertEqual("Peers", aws.ToString(egress[0].UserIdGroupPairs[0].Description))
+
+	// Rules are validated before any call
+	client.ingressInput = nil
+	err = groups.AddRules(ctx, "sg-0123456789abcdef0", []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 443, ToPort: 80, CIDR: "0.0.0.0/0"},
+	})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	if client.ingressInput != nil {
+		t.Fatal("expected no call for invalid rules")
+	}
+
+	client.authorizeSecurityGroupError = &smithy.GenericAPIError{Code: "InvalidPermission.Duplicate", Message: "the specified rule already exists"}
+	err = groups.AddRules(ctx, "sg-0123456789abcdef0", []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"},
+	})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+
+	rule := services.FirewallRule{Direction: "ingress", Protocol: "udp", FromPort: 53, CIDR: "10.0.0.0/8"}
+	helper.AssertNoError(groups.RevokeRules(ctx, "sg-0123456789abcdef0", []services.FirewallRule{rule}))
+	helper.AssertEqual("udp", aws.ToString(client.revokeInput.IpPermissions[0].IpProtocol))
+
+	// EC2 reports rules the group doesn't have in its response
+	client.revokeSecurityGroupIngressResponse = &ec2.RevokeSecurityGroupIngressOutput{
+		Return:               aws.Bool(true),
+		UnknownIpPermissions: client.revokeInput.IpPermissions,
+	}
+	err = groups.RevokeRules(ctx, "sg-0123456789abcdef0", []services.FirewallRule{rule})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	helper.AssertContains(err.Error(), "10.0.0.0/8")
+}
+
+func TestAWSSecurityGroups_Delete(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := &securityGroupEC2Client{}
+	groups := NewWithClient(client).SecurityGroups()
+
+	helper.AssertNoError(groups.Delete(ctx, "sg-0123456789abcdef0"))
+	helper.AssertEqual("sg-0123456789abcdef0", aws.ToString(client.deleteInput.GroupId))
+	helper.AssertErrorCode(groups.Delete(ctx, ""), cloudsdk.ErrInvalidConfig)
+
+	client.deleteSecurityGroupError = &smithy.GenericAPIError{Code: "DependencyViolation", Message: "resource sg-0123456789abcdef0 has a dependent object"}
+	helper.AssertErrorCode(groups.Delete(ctx, "sg-0123456789abcdef0"), cloudsdk.ErrDependencyViolation)
+}
+
+func TestAWSSecurityGroups_DryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	client := &securityGroupEC2Client{}
+	client.createSecurityGroupError = &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."}
+	client.authorizeSecurityGroupError = client.createSecurityGroupError
+	groups := NewWithClient(client).SecurityGroups()
+	ctx, plan := cloudsdk.ContextWithPlan(context.Background())
+
+	group, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	helper.AssertNoError(err)
+	if group != nil {
+		t.Errorf("expected no security group from a dry run, got %+v", group)
+	}
+	helper.AssertEqual(true, aws.ToBool(client.createInput.DryRun))
+
+	err = groups.AddRules(ctx, "sg-0123456789abcdef0", []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 22, CIDR: "203.0.113.0/24"},
+		{Direction: "egress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, aws.ToBool(client.ingressInput.DryRun))
+
+	calls := plan.Calls()
+	helper.AssertEqual(3, len(calls))
+	helper.AssertEqual("CreateSecurityGroup", calls[0].API)
+	helper.AssertEqual(cloudsdk.OpCreateSecurityGroup, calls[0].Operation)
+	helper.AssertEqual("AuthorizeSecurityGroupIngress", calls[1].API)
+	helper.AssertEqual("AuthorizeSecurityGroupEgress", calls[2].API)
+	helper.AssertEqual(cloudsdk.OpAddSecurityGroupRules, calls[2].Operation)
+}
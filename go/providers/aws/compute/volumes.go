@@ -0,0 +1,467 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsidem"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// VolumesServiceImpl implements VolumesService with EBS volumes
+type VolumesServiceImpl struct {
+	client      EC2ClientInterface
+	logger      *slog.Logger
+	retryConfig RetryConfig
+}
+
+// Create creates a new EBS volume, tagged at creation
+func (s *VolumesServiceImpl) Create(ctx context.Context, config *services.VolumeConfig) (*services.Volume, error) {
+	// Validate input configuration
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
+	if config.SizeGiB == 0 && config.SnapshotID == "" {
+		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "SizeGiB", "size is required unless creating from a snapshot")
+	}
+
+	input := &ec2.CreateVolumeInput{
+		AvailabilityZone: aws.String(config.AvailabilityZone),
+		Iops:             config.Iops,
+		Throughput:       config.Throughput,
+		Encrypted:        config.Encrypted,
+	}
+	if config.SizeGiB > 0 {
+		input.Size = aws.Int32(config.SizeGiB)
+	}
+	if config.VolumeType != "" {
+		input.VolumeType = types.VolumeType(config.VolumeType)
+	}
+	if config.KmsKeyID != "" {
+		input.KmsKeyId = aws.String(config.KmsKeyID)
+	}
+	if config.SnapshotID != "" {
+		input.SnapshotId = aws.String(config.SnapshotID)
+	}
+	tags := make(map[string]string, len(config.Tags)+1)
+	if config.Name != "" {
+		tags["Name"] = config.Name
+	}
+	for key, value := range config.Tags {
+		tags[key] = value
+	}
+	if len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{{ResourceType: types.ResourceTypeVolume, Tags: ec2Tags(tags)}}
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "CreateVolume", "CreateVolume", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CreateVolume(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	// Every attempt sends the same client token, so a retry after a call
+	// that succeeded but timed out returns that volume instead of another one
+	input.ClientToken = aws.String(awsidem.Token(config.IdempotencyKey))
+
+	logRequest(ctx, s.logger, "CreateVolume", input)
+
+	var resp *ec2.CreateVolumeOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "CreateVolume", func(ctx context.Context) error {
+		resp, err = s.client.CreateVolume(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "CreateVolume", resp, retryErr)
+
+	if retryErr != nil {
+		return nil, wrapAWSError(retryErr, "aws", "compute", "CreateVolume")
+	}
+
+	volume := volumeFromEBS(types.Volume{
+		Attachments:      resp.Attachments,
+		AvailabilityZone: resp.AvailabilityZone,
+		CreateTime:       resp.CreateTime,
+		Encrypted:        resp.Encrypted,
+		Iops:             resp.Iops,
+		Size:             resp.Size,
+		SnapshotId:       resp.SnapshotId,
+		State:            resp.State,
+		Tags:             resp.Tags,
+		Throughput:       resp.Throughput,
+		VolumeId:         resp.VolumeId,
+		VolumeType:       resp.VolumeType,
+	})
+	if len(volume.Tags) == 0 && len(tags) > 0 {
+		// CreateVolume may not echo the tags it applied
+		volume.Tags = tags
+		volume.Name = config.Name
+	}
+	return volume, nil
+}
+
+// List lists EBS volumes, filtered by EC2 and following NextToken across
+// every page
+func (s *VolumesServiceImpl) List(ctx context.Context, filter *services.VolumeFilter) ([]*services.Volume, error) {
+	input := &ec2.DescribeVolumesInput{Filters: volumeFilters(filter)}
+
+	volumes := []*services.Volume{}
+	for {
+		resp, err := s.describeVolumes(ctx, input, "ListVolumes")
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range resp.Volumes {
+			volumes = append(volumes, volumeFromEBS(volume))
+		}
+		if aws.ToString(resp.NextToken) == "" {
+			return volumes, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
+
+// volumeFilters translates filter to DescribeVolumes filters.
+func volumeFilters(filter *services.VolumeFilter) []types.Filter {
+	if filter == nil {
+		return nil
+	}
+	var filters []types.Filter
+	if filter.VMID != "" {
+		filters = append(filters, types.Filter{Name: aws.String("attachment.instance-id"), Values: []string{filter.VMID}})
+	}
+	if filter.AvailabilityZone != "" {
+		filters = append(filters, types.Filter{Name: aws.String("availability-zone"), Values: []string{filter.AvailabilityZone}})
+	}
+	if len(filter.States) > 0 {
+		filters = append(filters, types.Filter{Name: aws.String("status"), Values: filter.States})
+	}
+	for _, key := range sortedKeys(filter.Tags) {
+		filters = append(filters, types.Filter{Name: aws.String("tag:" + key), Values: []string{filter.Tags[key]}})
+	}
+	return filters
+}
+
+// Get gets an EBS volume by ID
+func (s *VolumesServiceImpl) Get(ctx context.Context, id string) (*services.Volume, error) {
+	// Validate input
+	if id == "" {
+		return nil, cloudsdk.NewInvalidConfigError("aws", "compute", "id", "volume ID cannot be empty")
+	}
+
+	resp, err := s.describeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{id}}, "GetVolume")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Volumes) == 0 {
+		return nil, cloudsdk.NewResourceNotFoundError("aws", "compute", "volume", id)
+	}
+
+	return volumeFromEBS(resp.Volumes[0]), nil
+}
+
+// describeVolumes makes one DescribeVolumes call with retries, reporting
+// errors as operation.
+func (s *VolumesServiceImpl) describeVolumes(ctx context.Context, input *ec2.DescribeVolumesInput, operation string) (*ec2.DescribeVolumesOutput, error) {
+	logRequest(ctx, s.logger, "DescribeVolumes", input)
+
+	var resp *ec2.DescribeVolumesOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DescribeVolumes", func(ctx context.Context) error {
+		resp, err = s.client.DescribeVolumes(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DescribeVolumes", resp, retryErr)
+
+	if retryErr != nil {
+		return nil, wrapAWSError(retryErr, "aws", "compute", operation)
+	}
+	return resp, nil
+}
+
+// volumeFromEBS converts an EBS volume to a Volume.
+func volumeFromEBS(v types.Volume) *services.Volume {
+	volume := &services.Volume{
+		ID:               aws.ToString(v.VolumeId),
+		State:            string(v.State),
+		SizeGiB:          aws.ToInt32(v.Size),
+		VolumeType:       string(v.VolumeType),
+		Iops:             aws.ToInt32(v.Iops),
+		Throughput:       aws.ToInt32(v.Throughput),
+		AvailabilityZone: aws.ToString(v.AvailabilityZone),
+		Encrypted:        aws.ToBool(v.Encrypted),
+		SnapshotID:       aws.ToString(v.SnapshotId),
+		Tags:             tagMap(v.Tags),
+	}
+	if v.CreateTime != nil {
+		volume.CreateTime = v.CreateTime.String()
+	}
+	for _, attachment := range v.Attachments {
+		volume.Attachments = append(volume.Attachments, services.VolumeAttachment{
+			VolumeID:            aws.ToString(attachment.VolumeId),
+			VMID:                aws.ToString(attachment.InstanceId),
+			Device:              aws.ToString(attachment.Device),
+			State:               string(attachment.State),
+			DeleteOnTermination: aws.ToBool(attachment.DeleteOnTermination),
+		})
+	}
+	volume.Name = volume.Tags["Name"]
+	return volume
+}
+
+// Attach attaches an EBS volume to an instance
+func (s *VolumesServiceImpl) Attach(ctx context.Context, volumeID, vmID, device string) error {
+	// Validate input
+	if volumeID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "volumeID", "volume ID cannot be empty")
+	}
+	if vmID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "vmID", "instance ID cannot be empty")
+	}
+	if device == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "device", "device name cannot be empty")
+	}
+
+	input := &ec2.AttachVolumeInput{
+		VolumeId:   aws.String(volumeID),
+		InstanceId: aws.String(vmID),
+		Device:     aws.String(device),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "AttachVolume", "AttachVolume", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.AttachVolume(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "AttachVolume", input)
+
+	var resp *ec2.AttachVolumeOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "AttachVolume", func(ctx context.Context) error {
+		resp, err = s.client.AttachVolume(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "AttachVolume", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "AttachVolume")
+	}
+
+	return nil
+}
+
+// Detach detaches an EBS volume from its instance
+func (s *VolumesServiceImpl) Detach(ctx context.Context, volumeID string) error {
+	// Validate input
+	if volumeID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "volumeID", "volume ID cannot be empty")
+	}
+
+	input := &ec2.DetachVolumeInput{
+		VolumeId: aws.String(volumeID),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "DetachVolume", "DetachVolume", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.DetachVolume(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "DetachVolume", input)
+
+	var resp *ec2.DetachVolumeOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DetachVolume", func(ctx context.Context) error {
+		resp, err = s.client.DetachVolume(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DetachVolume", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "DetachVolume")
+	}
+
+	return nil
+}
+
+// Resize grows an EBS volume with ModifyVolume. The volume is looked up
+// first, because EC2 rejects both shrinking and "resizing" to the current
+// size with a generic error.
+func (s *VolumesServiceImpl) Resize(ctx context.Context, volumeID string, sizeGiB int32) error {
+	// Validate input
+	if volumeID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "volumeID", "volume ID cannot be empty")
+	}
+
+	volume, err := s.Get(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	if sizeGiB == volume.SizeGiB {
+		return nil
+	}
+	if sizeGiB < volume.SizeGiB {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "SizeGiB",
+			fmt.Sprintf("volumes can't shrink: %d GiB is smaller than the current %d GiB", sizeGiB, volume.SizeGiB)).
+			WithSuggestions(
+				"Create a smaller volume and copy the data to it",
+			)
+	}
+
+	input := &ec2.ModifyVolumeInput{
+		VolumeId: aws.String(volumeID),
+		Size:     aws.Int32(sizeGiB),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "ResizeVolume", "ModifyVolume", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.ModifyVolume(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "ModifyVolume", input)
+
+	var resp *ec2.ModifyVolumeOutput
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "ModifyVolume", func(ctx context.Context) error {
+		resp, err = s.client.ModifyVolume(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "ModifyVolume", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "ResizeVolume")
+	}
+
+	return nil
+}
+
+// Snapshot starts an EBS snapshot of a volume
+func (s *VolumesServiceImpl) Snapshot(ctx context.Context, config *services.VolumeSnapshotConfig) (*services.VolumeSnapshot, error) {
+	// Validate input configuration
+	if err := cloudsdk.ValidateConfig("aws", "compute", config); err != nil {
+		return nil, err
+	}
+
+	input := &ec2.CreateSnapshotInput{
+		VolumeId: aws.String(config.VolumeID),
+	}
+	if config.Description != "" {
+		input.Description = aws.String(config.Description)
+	}
+	if len(config.Tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{{ResourceType: types.ResourceTypeSnapshot, Tags: ec2Tags(config.Tags)}}
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return nil, planEC2Call(ctx, s.logger, "SnapshotVolume", "CreateSnapshot", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.CreateSnapshot(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "CreateSnapshot", input)
+
+	// CreateSnapshot has no client token, so it is not retried: a retry
+	// after a call that succeeded but timed out would start a second snapshot
+	resp, err := s.client.CreateSnapshot(ctx, input, ec2Options(ctx)...)
+
+	logResponse(ctx, s.logger, "CreateSnapshot", resp, err)
+
+	if err != nil {
+		return nil, wrapAWSError(err, "aws", "compute", "SnapshotVolume")
+	}
+
+	snapshot := &services.VolumeSnapshot{
+		ID:          aws.ToString(resp.SnapshotId),
+		VolumeID:    aws.ToString(resp.VolumeId),
+		State:       string(resp.State),
+		Progress:    aws.ToString(resp.Progress),
+		SizeGiB:     aws.ToInt32(resp.VolumeSize),
+		Description: aws.ToString(resp.Description),
+		Tags:        tagMap(resp.Tags),
+	}
+	if resp.StartTime != nil {
+		snapshot.StartTime = resp.StartTime.String()
+	}
+	if snapshot.Tags == nil {
+		snapshot.Tags = config.Tags
+	}
+	return snapshot, nil
+}
+
+// Delete deletes an EBS volume
+func (s *VolumesServiceImpl) Delete(ctx context.Context, volumeID string) error {
+	// Validate input
+	if volumeID == "" {
+		return cloudsdk.NewInvalidConfigError("aws", "compute", "volumeID", "volume ID cannot be empty")
+	}
+
+	input := &ec2.DeleteVolumeInput{
+		VolumeId: aws.String(volumeID),
+	}
+
+	if cloudsdk.IsDryRun(ctx) {
+		return planEC2Call(ctx, s.logger, "DeleteVolume", "DeleteVolume", input, func(ctx context.Context) error {
+			dryRun := *input
+			dryRun.DryRun = aws.Bool(true)
+			_, err := s.client.DeleteVolume(ctx, &dryRun, ec2Options(ctx)...)
+			return err
+		})
+	}
+
+	logRequest(ctx, s.logger, "DeleteVolume", input)
+
+	var resp *ec2.DeleteVolumeOutput
+	var err error
+
+	// Execute with retry logic
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "compute", "DeleteVolume", func(ctx context.Context) error {
+		resp, err = s.client.DeleteVolume(ctx, input, ec2Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "DeleteVolume", resp, retryErr)
+
+	if retryErr != nil {
+		return wrapAWSError(retryErr, "aws", "compute", "DeleteVolume")
+	}
+
+	return nil
+}
@@ -0,0 +1,354 @@
+package compute
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// ebsEC2Client is a fake EBS that keeps its volumes and records the input of
+// each call. DescribeVolumes serves one volume per page.
+type ebsEC2Client struct {
+	mockEC2Client
+	volumes map[string]*types.Volume
+	order   []string
+
+	createInput   *ec2.CreateVolumeInput
+	describes     []*ec2.DescribeVolumesInput
+	attachInput   *ec2.AttachVolumeInput
+	detachInput   *ec2.DetachVolumeInput
+	modifyInputs  []*ec2.ModifyVolumeInput
+	snapshotInput *ec2.CreateSnapshotInput
+	deleteInput   *ec2.DeleteVolumeInput
+}
+
+func newEBSEC2Client(volumes ...types.Volume) *ebsEC2Client {
+	m := &ebsEC2Client{volumes: make(map[string]*types.Volume)}
+	for i := range volumes {
+		id := aws.ToString(volumes[i].VolumeId)
+		m.volumes[id] = &volumes[i]
+		m.order = append(m.order, id)
+	}
+	return m
+}
+
+func (m *ebsEC2Client) CreateVolume(ctx context.Context, input *ec2.CreateVolumeInput, opts ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+	m.createInput = input
+	if m.createVolumeError != nil {
+		return nil, m.createVolumeError
+	}
+	return &ec2.CreateVolumeOutput{
+		VolumeId:         aws.String("vol-0123456789abcdef0"),
+		AvailabilityZone: input.AvailabilityZone,
+		Size:             input.Size,
+		VolumeType:       input.VolumeType,
+		Iops:             input.Iops,
+		Encrypted:        input.Encrypted,
+		State:            types.VolumeStateCreating,
+		CreateTime:       aws.Time(time.Now()),
+	}, nil
+}
+
+func (m *ebsEC2Client) DescribeVolumes(ctx context.Context, input *ec2.DescribeVolumesInput, opts ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	m.describes = append(m.describes, input)
+	if len(input.VolumeIds) > 0 {
+		output := &ec2.DescribeVolumesOutput{}
+		for _, id := range input.VolumeIds {
+			volume, ok := m.volumes[id]
+			if !ok {
+				return nil, &smithy.GenericAPIError{Code: "InvalidVolume.NotFound", Message: "The volume '" + id + "' does not exist."}
+			}
+			output.Volumes = append(output.Volumes, *volume)
+		}
+		return output, nil
+	}
+
+	i := 0
+	for i < len(m.order) && m.order[i] != aws.ToString(input.NextToken) && input.NextToken != nil {
+		i++
+	}
+	output := &ec2.DescribeVolumesOutput{}
+	if i < len(m.order) {
+		output.Volumes = []types.Volume{*m.volumes[m.order[i]]}
+	}
+	if i+1 < len(m.order) {
+		output.NextToken = aws.String(m.order[i+1])
+	}
+	return output, nil
+}
+
+func (m *ebsEC2Client) AttachVolume(ctx context.Context, input *ec2.AttachVolumeInput, opts ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error) {
+	m.attachInput = input
+	return &ec2.AttachVolumeOutput{}, m.attachVolumeError
+}
+
+func (m *ebsEC2Client) DetachVolume(ctx context.Context, input *ec2.DetachVolumeInput, opts ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error) {
+	m.detachInput = input
+	return &ec2.DetachVolumeOutput{}, m.detachVolumeError
+}
+
+func (m *ebsEC2Client) ModifyVolume(ctx context.Context, input *ec2.ModifyVolumeInput, opts ...func(*ec2.Options)) (*ec2.ModifyVolumeOutput, error) {
+	m.modifyInputs = append(m.modifyInputs, input)
+	return &ec2.ModifyVolumeOutput{}, m.modifyVolumeError
+}
+
+func (m *ebsEC2Client) CreateSnapshot(ctx context.Context, input *ec2.CreateSnapshotInput, opts ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
+	m.snapshotInput = input
+	if m.createSnapshotError != nil {
+		return nil, m.createSnapshotError
+	}
+	return &ec2.CreateSnapshotOutput{
+		SnapshotId:  aws.String("snap-0123456789abcdef0"),
+		VolumeId:    input.VolumeId,
+		VolumeSize:  m.volumes[aws.ToString(input.VolumeId)].Size,
+		Description: input.Description,
+		State:       types.SnapshotStatePending,
+		Progress:    aws.String("0%"),
+	}, nil
+}
+
+func (m *ebsEC2Client) DeleteVolume(ctx context.Context, input *ec2.DeleteVolumeInput, opts ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	m.deleteInput = input
+	return &ec2.DeleteVolumeOutput{}, m.deleteVolumeError
+}
+
+// ebsVolume returns an available gp3 volume in us-east-1a.
+func ebsVolume(id string, sizeGiB int32) types.Volume {
+	return types.Volume{
+		VolumeId:         aws.String(id),
+		AvailabilityZone: aws.String("us-east-1a"),
+		Size:             aws.Int32(sizeGiB),
+		VolumeType:       types.VolumeTypeGp3,
+		State:            types.VolumeStateAvailable,
+		Tags:             []types.Tag{{Key: aws.String("Name"), Value: aws.String("data-" + id)}},
+	}
+}
+
+func TestAWSVolumes_Create(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newEBSEC2Client()
+	volumes := NewWithClient(client).Volumes()
+
+	volume, err := volumes.Create(ctx, &services.VolumeConfig{
+		Name:             "data-node-1",
+		AvailabilityZone: "us-east-1a",
+		SizeGiB:          500,
+		VolumeType:       "gp3",
+		Iops:             aws.Int32(4000),
+		Encrypted:        aws.Bool(true),
+		KmsKeyID:         "alias/ebs",
+		Tags:             map[string]string{"Environment": "production"},
+		IdempotencyKey:   "data-node-1",
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("vol-0123456789abcdef0", volume.ID)
+	helper.AssertEqual("creating", volume.State)
+	helper.AssertEqual(int32(500), volume.SizeGiB)
+	helper.AssertEqual(int32(4000), volume.Iops)
+	helper.AssertEqual(true, volume.Encrypted)
+	helper.AssertEqual("data-node-1", volume.Name)
+	helper.AssertEqual("production", volume.Tags["Environment"])
+
+	input := client.createInput
+	helper.AssertEqual("us-east-1a", aws.ToString(input.AvailabilityZone))
+	helper.AssertEqual(types.VolumeTypeGp3, input.VolumeType)
+	helper.AssertEqual("alias/ebs", aws.ToString(input.KmsKeyId))
+	helper.AssertEqual(true, aws.ToString(input.ClientToken) != "")
+	helper.AssertEqual(types.ResourceTypeVolume, input.TagSpecifications[0].ResourceType)
+	helper.AssertEqual(2, len(input.TagSpecifications[0].Tags))
+
+	// A size is needed unless the volume is created from a snapshot
+	_, err = volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	_, err = volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SnapshotID: "snap-0123456789abcdef0"})
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, client.createInput.Size == nil)
+
+	client.createVolumeError = &smithy.GenericAPIError{Code: "VolumeLimitExceeded", Message: "You have exceeded your maximum gp3 storage limit."}
+	_, err = volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SizeGiB: 100})
+	helper.AssertErrorCode(err, cloudsdk.ErrQuotaExceeded)
+}
+
+func TestAWSVolumes_ListAndGet(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	attached := ebsVolume("vol-2", 200)
+	attached.State = types.VolumeStateInUse
+	attached.Attachments = []types.VolumeAttachment{{
+		VolumeId:   aws.String("vol-2"),
+		InstanceId: aws.String("i-1234567890abcdef0"),
+		Device:     aws.String("/dev/sdf"),
+		State:      types.VolumeAttachmentStateAttached,
+	}}
+	client := newEBSEC2Client(ebsVolume("vol-1", 100), attached, ebsVolume("vol-3", 300))
+	volumes := NewWithClient(client).Volumes()
+
+	// Every page is fetched
+	list, err := volumes.List(ctx, &services.VolumeFilter{
+		VMID:   "i-1234567890abcdef0",
+		States: []string{"in-use"},
+		Tags:   map[string]string{"Team": "data"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(3, len(list))
+	helper.AssertEqual(3, len(client.describes))
+	helper.AssertEqual("data-vol-1", list[0].Name)
+
+	filters := client.describes[0].Filters
+	helper.AssertEqual(3, len(filters))
+	helper.AssertEqual("attachment.instance-id", aws.ToString(filters[0].Name))
+	helper.AssertEqual("status", aws.ToString(filters[1].Name))
+	helper.AssertEqual("tag:Team", aws.ToString(filters[2].Name))
+
+	volume, err := volumes.Get(ctx, "vol-2")
+	helper.AssertNoError(err)
+	helper.AssertEqual("in-use", volume.State)
+	helper.AssertEqual(1, len(volume.Attachments))
+	helper.AssertEqual("i-1234567890abcdef0", volume.Attachments[0].VMID)
+	helper.AssertEqual("attached", volume.Attachments[0].State)
+
+	_, err = volumes.Get(ctx, "vol-missing")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+	helper.AssertContains(err.Error(), "vol-missing")
+}
+
+func TestAWSVolumes_AttachDetachDelete(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newEBSEC2Client(ebsVolume("vol-1", 100))
+	volumes := NewWithClient(client).Volumes()
+
+	helper.AssertNoError(volumes.Attach(ctx, "vol-1", "i-1234567890abcdef0", "/dev/sdf"))
+	helper.AssertEqual("vol-1", aws.ToString(client.attachInput.VolumeId))
+	helper.AssertEqual("i-1234567890abcdef0", aws.ToString(client.attachInput.InstanceId))
+	helper.AssertEqual("/dev/sdf", aws.ToString(client.attachInput.Device))
+
+	err := volumes.Attach(ctx, "vol-1", "i-1234567890abcdef0", "")
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	client.attachVolumeError = &smithy.GenericAPIError{Code: "VolumeInUse", Message: "vol-1 is already attached to an instance"}
+	err = volumes.Attach(ctx, "vol-1", "i-1234567890abcdef0", "/dev/sdg")
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidState)
+
+	helper.AssertNoError(volumes.Detach(ctx, "vol-1"))
+	helper.AssertEqual("vol-1", aws.ToString(client.detachInput.VolumeId))
+
+	client.deleteVolumeError = &smithy.GenericAPIError{Code: "VolumeInUse", Message: "Volume vol-1 is currently attached to i-1234567890abcdef0"}
+	err = volumes.Delete(ctx, "vol-1")
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidState)
+	client.deleteVolumeError = nil
+	helper.AssertNoError(volumes.Delete(ctx, "vol-1"))
+	helper.AssertEqual("vol-1", aws.ToString(client.deleteInput.VolumeId))
+}
+
+func TestAWSVolumes_Resize(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newEBSEC2Client(ebsVolume("vol-1", 100))
+	volumes := NewWithClient(client).Volumes()
+
+	helper.AssertNoError(volumes.Resize(ctx, "vol-1", 200))
+	helper.AssertEqual(1, len(client.modifyInputs))
+	helper.AssertEqual(int32(200), aws.ToInt32(client.modifyInputs[0].Size))
+
+	// Resizing to the current size does nothing, and volumes can't shrink
+	helper.AssertNoError(volumes.Resize(ctx, "vol-1", 100))
+	err := volumes.Resize(ctx, "vol-1", 50)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	helper.AssertEqual(1, len(client.modifyInputs))
+
+	client.modifyVolumeError = &smithy.GenericAPIError{Code: "IncorrectModificationState", Message: "Volume vol-1 is already being modified"}
+	err = volumes.Resize(ctx, "vol-1", 300)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidState)
+}
+
+func TestAWSVolumes_Snapshot(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	client := newEBSEC2Client(ebsVolume("vol-1", 100))
+	volumes := NewWithClient(client).Volumes()
+
+	snapshot, err := volumes.Snapshot(ctx, &services.VolumeSnapshotConfig{
+		VolumeID:    "vol-1",
+		Description: "nightly backup",
+		Tags:        map[string]string{"Schedule": "nightly"},
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("snap-0123456789abcdef0", snapshot.ID)
+	helper.AssertEqual("pending", snapshot.State)
+	helper.AssertEqual(int32(100), snapshot.SizeGiB)
+	helper.AssertEqual("nightly", snapshot.Tags["Schedule"])
+	helper.AssertEqual(types.ResourceTypeSnapshot, client.snapshotInput.TagSpecifications[0].ResourceType)
+
+	_, err = volumes.Snapshot(ctx, &services.VolumeSnapshotConfig{})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+}
+
+func TestAWSVolumes_DryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	client := newEBSEC2Client(ebsVolume("vol-1", 100))
+	client.createVolumeError = &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."}
+	client.modifyVolumeError = &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."}
+	volumes := NewWithClient(client).Volumes()
+	ctx, plan := cloudsdk.ContextWithPlan(context.Background())
+
+	volume, err := volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SizeGiB: 100})
+	helper.AssertNoError(err)
+	if volume != nil {
+		t.Errorf("expected no volume from a dry run, got %+v", volume)
+	}
+	helper.AssertEqual(true, aws.ToBool(client.createInput.DryRun))
+
+	// The volume is read to check the new size, but only ModifyVolume is planned
+	helper.AssertNoError(volumes.Resize(ctx, "vol-1", 200))
+
+	calls := plan.Calls()
+	helper.AssertEqual(2, len(calls))
+	helper.AssertEqual("CreateVolume", calls[0].API)
+	helper.AssertEqual(cloudsdk.OpCreateVolume, calls[0].Operation)
+	helper.AssertEqual("ModifyVolume", calls[1].API)
+	helper.AssertEqual(cloudsdk.OpResizeVolume, calls[1].Operation)
+}
+
+func TestAWSCompute_VMVolumes(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	vm := vmFromInstance(types.Instance{
+		InstanceId: aws.String("i-1234567890abcdef0"),
+		BlockDeviceMappings: []types.InstanceBlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/xvda"),
+				Ebs: &types.EbsInstanceBlockDevice{
+					VolumeId:            aws.String("vol-root"),
+					Status:              types.AttachmentStatusAttached,
+					DeleteOnTermination: aws.Bool(true),
+				},
+			},
+			{
+				DeviceName: aws.String("/dev/sdf"),
+				Ebs:        &types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-data"), Status: types.AttachmentStatusAttaching},
+			},
+		},
+	})
+	helper.AssertEqual(2, len(vm.Volumes))
+	helper.AssertEqual("vol-root", vm.Volumes[0].VolumeID)
+	helper.AssertEqual("i-1234567890abcdef0", vm.Volumes[0].VMID)
+	helper.AssertEqual(true, vm.Volumes[0].DeleteOnTermination)
+	helper.AssertEqual("/dev/sdf", vm.Volumes[1].Device)
+	helper.AssertEqual("attaching", vm.Volumes[1].State)
+}
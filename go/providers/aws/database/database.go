@@ -4,121 +4,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"regexp"
+	"log/slog"
+	"sort"
 	"strings"
-	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awserr"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsidem"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/aws/smithy-go"
 )
 
-// RetryConfig defines retry behavior for AWS RDS operations
-type RetryConfig struct {
-	MaxAttempts   int
-	InitialDelay  time.Duration
-	MaxDelay      time.Duration
-	BackoffFactor float64
-}
+// RetryConfig defines retry behavior for AWS operations.
+// It is the shared cloudsdk.RetryPolicy.
+type RetryConfig = cloudsdk.RetryPolicy
 
 // DefaultRetryConfig provides sensible defaults for retry behavior
-var DefaultRetryConfig = RetryConfig{
-	MaxAttempts:   3,
-	InitialDelay:  100 * time.Millisecond,
-	MaxDelay:      5 * time.Second,
-	BackoffFactor: 2.0,
-}
-
-// retryWithBackoff executes a function with exponential backoff retry logic
-func retryWithBackoff(ctx context.Context, config RetryConfig, operation func() error) error {
-	var lastErr error
-	delay := config.InitialDelay
-
-	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
-		if attempt > 1 {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
-			}
-		}
-
-		err := operation()
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-		if !isRetryableRDSError(err) {
-			return err
-		}
-
-		if attempt < config.MaxAttempts {
-			delay = time.Duration(float64(delay) * config.BackoffFactor)
-			if delay > config.MaxDelay {
-				delay = config.MaxDelay
-			}
-			log.Printf("AWS Database: Retrying operation (attempt %d/%d) after %v due to: %v",
-				attempt+1, config.MaxAttempts, delay, err)
-		}
-	}
-
-	return lastErr
-}
-
-// isRetryableRDSError determines if an RDS error should be retried
-func isRetryableRDSError(err error) bool {
-	if err == nil {
-		return false
-	}
+var DefaultRetryConfig = cloudsdk.DefaultRetryPolicy
 
-	// Check for context cancellation/timeout - don't retry
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-		return false
-	}
-
-	// Check for AWS-specific retryable errors
-	var ae smithy.APIError
-	if errors.As(err, &ae) {
-		code := ae.ErrorCode()
-		switch code {
-		case "Throttling", "ThrottlingException", "RequestLimitExceeded":
-			return true
-		case "InternalError", "InternalFailure", "ServiceUnavailable":
-			return true
-		case "RequestTimeout", "RequestTimeoutException":
-			return true
-		}
-	}
-
-	// Check error message for common transient issues
-	errMsg := strings.ToLower(err.Error())
-	transientMessages := []string{
-		"connection reset",
-		"connection refused",
-		"timeout",
-		"temporary failure",
-		"service unavailable",
-		"internal error",
-	}
-
-	for _, msg := range transientMessages {
-		if strings.Contains(errMsg, msg) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// wrapRDSError converts RDS errors to CloudError with helpful context
+// wrapRDSError converts RDS errors to CloudError with helpful context, including
+// the request ID, error code, HTTP status and attempt count of the failure
 func wrapRDSError(err error, provider, service, operation string) error {
 	if err == nil {
 		return nil
 	}
+	return awserr.Annotate(classifyRDSError(err, provider, service, operation), err)
+}
+
+// classifyRDSError maps an RDS error to the CloudError code and suggestions for it
+func classifyRDSError(err error, provider, service, operation string) *cloudsdk.CloudError {
 
 	// Handle context errors
 	if errors.Is(err, context.Canceled) {
@@ -165,7 +84,7 @@ func wrapRDSError(err error, provider, service, operation string) error {
 					"Ensure the instance hasn't been deleted",
 				)
 
-		case "DBInstanceAlreadyExistsFault":
+		case "DBInstanceAlreadyExists", "DBInstanceAlreadyExistsFault":
 			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "Database instance already exists", provider, service, operation).
 				WithCause(err).
 				WithSuggestions(
@@ -224,17 +143,39 @@ func wrapRDSError(err error, provider, service, operation string) error {
 					"Wait and retry later when capacity becomes available",
 				)
 
-		case "DBInstanceLimitExceeded":
-			return cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "Database instance limit exceeded", provider, service, operation).
+		case "DBInstanceLimitExceeded", "InstanceQuotaExceeded", "InstanceQuotaExceededFault",
+			"StorageQuotaExceeded", "StorageQuotaExceededFault", "SnapshotQuotaExceeded", "SnapshotQuotaExceededFault":
+			return cloudsdk.NewQuotaExceededError(provider, service, operation, message).
+				WithCause(err).
+				WithSuggestions(
+					"Request a limit increase through AWS Service Quotas",
+					"Delete unused database instances or snapshots to free up capacity",
+				)
+
+		case "InvalidDBInstanceState", "InvalidDBInstanceStateFault":
+			return cloudsdk.NewInvalidStateError(provider, service, operation, extractDBInstanceIDFromError(message), "").
 				WithCause(err).
 				WithSuggestions(
-					"Request a limit increase from AWS Support",
-					"Delete unused database instances to free up capacity",
-					"Use different instance classes with available capacity",
+					"Use GetDB to check the database status",
+					"Wait for the database to become available before retrying",
+				)
+
+		case "InvalidParameterCombination":
+			if strings.Contains(strings.ToLower(message), "deletion protection") {
+				return cloudsdk.NewPreconditionFailedError(provider, service, operation, message).
+					WithCause(err).
+					WithSuggestions(
+						"Disable deletion protection on the database before deleting it",
+					)
+			}
+			return cloudsdk.NewInvalidConfigError(provider, service, "Parameter", message).
+				WithSuggestions(
+					"Check that the combination of parameters is supported",
+					"Refer to AWS RDS documentation for valid values",
 				)
 
 		case "Throttling", "RequestLimitExceeded":
-			return cloudsdk.NewRateLimitError(provider, service, operation, 0).
+			return cloudsdk.NewRateLimitError(provider, service, operation, awsretry.RetryAfter(err)).
 				WithCause(err).
 				WithSuggestions(
 					"Reduce the frequency of API calls",
@@ -277,61 +218,45 @@ func extractDBInstanceIDFromError(message string) string {
 	return "unknown"
 }
 
-// validateDBConfig validates database configuration parameters
+// validateDBConfig validates a database configuration against DBConfig's
+// validate tags and the RDS rules they don't cover, returning every invalid
+// field at once.
 func validateDBConfig(config *services.DBConfig) error {
-	if config == nil {
-		return fmt.Errorf("database configuration cannot be nil")
-	}
-
-	// Validate required fields
-	if config.Name == "" {
-		return fmt.Errorf("database instance identifier is required")
-	}
-	if config.Engine == "" {
-		return fmt.Errorf("database engine is required")
-	}
-	if config.InstanceClass == "" {
-		return fmt.Errorf("database instance class is required")
-	}
-	if config.MasterUsername == "" {
-		return fmt.Errorf("master username is required")
-	}
-	if config.MasterPassword == "" {
-		return fmt.Errorf("master password is required")
-	}
-
-	// Validate DB instance identifier format
-	if err := validateDBInstanceIdentifier(config.Name); err != nil {
-		return fmt.Errorf("invalid database instance identifier: %w", err)
-	}
-
-	// Validate engine
-	if err := validateEngine(config.Engine); err != nil {
-		return fmt.Errorf("invalid database engine: %w", err)
-	}
-
-	// Validate master username
-	if err := validateMasterUsername(config.MasterUsername, config.Engine); err != nil {
-		return fmt.Errorf("invalid master username: %w", err)
+	err := cloudsdk.ValidateConfig("aws", "database", config)
+	var errs cloudsdk.ValidationErrors
+	if config == nil || (err != nil && !errors.As(err, &errs)) {
+		return err
 	}
 
-	// Validate master password
-	if err := validateMasterPassword(config.MasterPassword, config.Engine); err != nil {
-		return fmt.Errorf("invalid master password: %w", err)
+	invalid := make(map[string]bool, len(errs))
+	for _, fieldErr := range errs {
+		invalid[fieldErr.Field] = true
+	}
+	for _, check := range []struct {
+		field string
+		err   error
+	}{
+		{"Name", validateDBInstanceIdentifier(config.Name)},
+		{"MasterUsername", validateMasterUsername(config.MasterUsername, config.Engine)},
+		{"MasterPassword", validateMasterPassword(config.MasterPassword, config.Engine)},
+	} {
+		// Fields that already failed a generic rule are reported once
+		if check.err != nil && !invalid[check.field] {
+			errs = append(errs, cloudsdk.NewInvalidConfigError("aws", "database", check.field, check.err.Error()))
+		}
 	}
 
-	// Validate allocated storage
-	if config.AllocatedStorage < 20 {
-		return fmt.Errorf("allocated storage must be at least 20 GB")
+	if len(errs) == 0 {
+		return nil
 	}
-
-	return nil
+	return errs
 }
 
-// validateDBInstanceIdentifier validates RDS instance identifier format
+// validateDBInstanceIdentifier checks the RDS instance identifier rules
+// that go beyond a hostname
 func validateDBInstanceIdentifier(identifier string) error {
-	if len(identifier) < 1 || len(identifier) > 63 {
-		return fmt.Errorf("must be 1-63 characters long")
+	if identifier == "" {
+		return nil
 	}
 
 	// Must start with a letter
@@ -339,17 +264,6 @@ func validateDBInstanceIdentifier(identifier string) error {
 		return fmt.Errorf("must start with a letter")
 	}
 
-	// Can contain letters, numbers, and hyphens
-	validChars := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
-	if !validChars.MatchString(identifier) {
-		return fmt.Errorf("can only contain letters, numbers, and hyphens")
-	}
-
-	// Cannot end with a hyphen
-	if strings.HasSuffix(identifier, "-") {
-		return fmt.Errorf("cannot end with a hyphen")
-	}
-
 	// Cannot contain consecutive hyphens
 	if strings.Contains(identifier, "--") {
 		return fmt.Errorf("cannot contain consecutive hyphens")
@@ -358,39 +272,8 @@ func validateDBInstanceIdentifier(identifier string) error {
 	return nil
 }
 
-// validateEngine validates database engine
-func validateEngine(engine string) error {
-	validEngines := map[string]bool{
-		"mysql":             true,
-		"postgres":          true,
-		"oracle-ee":         true,
-		"oracle-se2":        true,
-		"oracle-se1":        true,
-		"oracle-se":         true,
-		"sqlserver-ee":      true,
-		"sqlserver-se":      true,
-		"sqlserver-ex":      true,
-		"sqlserver-web":     true,
-		"mariadb":           true,
-		"aurora":            true,
-		"aurora-mysql":      true,
-		"aurora-postgresql": true,
-	}
-
-	if !validEngines[strings.ToLower(engine)] {
-		return fmt.Errorf("unsupported engine '%s'. Valid engines: mysql, postgres, oracle-ee, sqlserver-ex, mariadb, etc.", engine)
-	}
-
-	return nil
-}
-
-// validateMasterUsername validates master username based on engine
+// validateMasterUsername rejects usernames reserved by the engine
 func validateMasterUsername(username, engine string) error {
-	if len(username) < 1 || len(username) > 63 {
-		return fmt.Errorf("must be 1-63 characters long")
-	}
-
-	// Engine-specific validations
 	switch strings.ToLower(engine) {
 	case "mysql", "mariadb":
 		if username == "root" {
@@ -406,62 +289,89 @@ func validateMasterUsername(username, engine string) error {
 		}
 	}
 
-	// General validation - must start with letter
-	if !((username[0] >= 'a' && username[0] <= 'z') || (username[0] >= 'A' && username[0] <= 'Z')) {
-		return fmt.Errorf("must start with a letter")
-	}
-
 	return nil
 }
 
-// validateMasterPassword validates master password based on engine
+// validateMasterPassword checks the engine-specific password length limits
 func validateMasterPassword(password, engine string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("must be at least 8 characters long")
-	}
-
-	// Engine-specific length limits
 	switch strings.ToLower(engine) {
 	case "mysql", "mariadb":
 		if len(password) > 41 {
 			return fmt.Errorf("must be no more than 41 characters for MySQL/MariaDB")
 		}
-	case "postgres":
-		if len(password) > 128 {
-			return fmt.Errorf("must be no more than 128 characters for PostgreSQL")
-		}
 	case "oracle-ee", "oracle-se2", "oracle-se1", "oracle-se":
 		if len(password) > 30 {
 			return fmt.Errorf("must be no more than 30 characters for Oracle")
 		}
 	}
 
-	// Check for printable ASCII characters
-	for _, r := range password {
-		if r < 32 || r > 126 {
-			return fmt.Errorf("must contain only printable ASCII characters")
-		}
+	return nil
+}
+
+// rdsOptions returns the per-request overrides for the call's
+// services.CallOptions: a Region option sends the request to that region
+func rdsOptions(ctx context.Context) []func(*rds.Options) {
+	region := services.CallOptionsFromContext(ctx).Region
+	if region == "" {
+		return nil
 	}
+	return []func(*rds.Options){func(o *rds.Options) {
+		o.Region = region
+	}}
+}
 
-	return nil
+// logRequest logs RDS API requests at debug level, with secrets redacted
+func logRequest(ctx context.Context, logger *slog.Logger, operation string, input interface{}) {
+	awslog.Request(ctx, logger, "database", operation, redactInput(input))
 }
 
-// logRequest logs RDS API requests for debugging (when debug is enabled)
-func logRequest(operation string, input interface{}, debug bool) {
-	if debug {
-		log.Printf("AWS Database: %s request: %+v", operation, input)
+// redactInput returns a copy of an RDS input with secrets masked for logging
+func redactInput(input interface{}) interface{} {
+	switch in := input.(type) {
+	case *rds.CreateDBInstanceInput:
+		if in != nil && in.MasterUserPassword != nil {
+			masked := *in
+			masked.MasterUserPassword = aws.String(awslog.Redacted)
+			return &masked
+		}
 	}
+	return input
 }
 
-// logResponse logs RDS API responses for debugging (when debug is enabled)
-func logResponse(operation string, output interface{}, err error, debug bool) {
-	if debug {
-		if err != nil {
-			log.Printf("AWS Database: %s error: %v", operation, err)
-		} else {
-			log.Printf("AWS Database: %s response: %+v", operation, output)
+// rdsTags converts tags to RDS tags, sorted by key. The idempotency key tag is
+// left out, since CreateDB sets it from DBConfig.IdempotencyKey.
+func rdsTags(tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		if key != cloudsdk.IdempotencyKeyTag {
+			keys = append(keys, key)
 		}
 	}
+	sort.Strings(keys)
+
+	var rdsTags []types.Tag
+	for _, key := range keys {
+		rdsTags = append(rdsTags, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return rdsTags
+}
+
+// recordPlannedCall adds an RDS call to the dry run's plan, with the master
+// password redacted. RDS has no way to check permissions without making the
+// call, so none is sent.
+func recordPlannedCall(ctx context.Context, operation, api string, input interface{}) {
+	cloudsdk.RecordPlannedCall(ctx, cloudsdk.PlannedCall{
+		Provider:  "aws",
+		Service:   cloudsdk.ServiceDatabase,
+		Operation: operation,
+		API:       api,
+		Input:     redactInput(input),
+	})
+}
+
+// logResponse logs RDS API responses and errors at debug level
+func logResponse(ctx context.Context, logger *slog.Logger, operation string, output interface{}, err error) {
+	awslog.Response(ctx, logger, "database", operation, output, err)
 }
 
 // RDSClientInterface defines methods we need from RDS client for testing
@@ -474,50 +384,64 @@ type RDSClientInterface interface {
 // AWSDatabase implements the Database interface for AWS
 type AWSDatabase struct {
 	client      RDSClientInterface
-	debug       bool
+	logger      *slog.Logger
 	retryConfig RetryConfig
 }
 
-// New creates a new AWSDatabase instance with real AWS client
-func New(cfg aws.Config) services.Database {
-	client := rds.NewFromConfig(cfg)
-	return &AWSDatabase{
-		client:      client,
-		debug:       false,
-		retryConfig: DefaultRetryConfig,
+// Option configures an AWSDatabase created by New or NewWithClient
+type Option func(*AWSDatabase)
+
+// WithLogger sets the logger for RDS requests, responses and retries.
+// Requests and responses are logged at debug level with the master password
+// redacted. A nil logger means slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *AWSDatabase) {
+		d.logger = logger
 	}
 }
 
+// New creates a new AWSDatabase instance with real AWS client
+func New(cfg aws.Config, opts ...Option) services.Database {
+	return newAWSDatabase(rds.NewFromConfig(cfg), DefaultRetryConfig, opts)
+}
+
 // NewWithClient creates a new AWSDatabase instance with custom client (for testing)
-func NewWithClient(client RDSClientInterface) services.Database {
-	return &AWSDatabase{
-		client:      client,
-		debug:       false,
-		retryConfig: DefaultRetryConfig,
-	}
+func NewWithClient(client RDSClientInterface, opts ...Option) services.Database {
+	return newAWSDatabase(client, DefaultRetryConfig, opts)
 }
 
-// NewWithOptions creates a new AWSDatabase instance with custom options
+// NewWithOptions creates a new AWSDatabase instance with custom options.
+// When debug is true, everything is logged to stderr, including requests and
+// responses.
 func NewWithOptions(cfg aws.Config, debug bool, retryConfig *RetryConfig) services.Database {
-	client := rds.NewFromConfig(cfg)
-
 	finalRetryConfig := DefaultRetryConfig
 	if retryConfig != nil {
 		finalRetryConfig = *retryConfig
 	}
 
-	return &AWSDatabase{
+	var opts []Option
+	if debug {
+		opts = append(opts, WithLogger(awslog.DebugLogger()))
+	}
+	return newAWSDatabase(rds.NewFromConfig(cfg), finalRetryConfig, opts)
+}
+
+func newAWSDatabase(client RDSClientInterface, retryConfig RetryConfig, opts []Option) *AWSDatabase {
+	d := &AWSDatabase{
 		client:      client,
-		debug:       debug,
-		retryConfig: finalRetryConfig,
+		retryConfig: retryConfig,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // CreateDB creates a new RDS instance
 func (d *AWSDatabase) CreateDB(ctx context.Context, config *services.DBConfig) (*services.DBInstance, error) {
 	// Validate input configuration
 	if err := validateDBConfig(config); err != nil {
-		return nil, cloudsdk.NewInvalidConfigError("aws", "database", "config", err.Error())
+		return nil, err
 	}
 
 	input := &rds.CreateDBInstanceInput{
@@ -540,28 +464,40 @@ func (d *AWSDatabase) CreateDB(ctx context.Context, config *services.DBConfig) (
 	if config.StorageEncrypted != nil {
 		input.StorageEncrypted = config.StorageEncrypted
 	}
+	input.Tags = rdsTags(config.Tags)
 
-	// Mask sensitive data in logs
-	logInput := map[string]interface{}{
-		"DBInstanceIdentifier": config.Name,
-		"DBInstanceClass":      config.InstanceClass,
-		"Engine":               config.Engine,
-		"MasterUsername":       config.MasterUsername,
-		"MasterUserPassword":   "[REDACTED]",
-		"AllocatedStorage":     config.AllocatedStorage,
+	if cloudsdk.IsDryRun(ctx) {
+		recordPlannedCall(ctx, "CreateDB", "CreateDBInstance", input)
+		return nil, nil
 	}
-	logRequest("CreateDBInstance", logInput, d.debug)
+
+	// RDS has no client tokens, so the key is stored in a tag. A retry that
+	// finds the identifier taken checks the tag to tell whether an earlier
+	// attempt created the instance
+	key := awsidem.Token(config.IdempotencyKey)
+	input.Tags = append(input.Tags, types.Tag{
+		Key:   aws.String(cloudsdk.IdempotencyKeyTag),
+		Value: aws.String(key),
+	})
+
+	logRequest(ctx, d.logger, "CreateDBInstance", input)
 
 	var resp *rds.CreateDBInstanceOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, d.retryConfig, func() error {
-		resp, err = d.client.CreateDBInstance(ctx, input)
+	retryErr := awsretry.Do(ctx, d.logger, d.retryConfig, "database", "CreateDBInstance", func(ctx context.Context) error {
+		resp, err = d.client.CreateDBInstance(ctx, input, rdsOptions(ctx)...)
 		return err
 	})
 
-	logResponse("CreateDBInstance", resp, retryErr, d.debug)
+	logResponse(ctx, d.logger, "CreateDBInstance", resp, retryErr)
+
+	if isDBInstanceAlreadyExists(retryErr) {
+		if inst := d.findIdempotentDB(ctx, config.Name, key); inst != nil {
+			resp, retryErr = &rds.CreateDBInstanceOutput{DBInstance: inst}, nil
+		}
+	}
 
 	if retryErr != nil {
 		return nil, wrapRDSError(retryErr, "aws", "database", "CreateDB")
@@ -597,22 +533,64 @@ func (d *AWSDatabase) CreateDB(ctx context.Context, config *services.DBConfig) (
 	return dbInstance, nil
 }
 
+// isDBInstanceAlreadyExists reports whether err is RDS's error for an
+// instance identifier that is taken
+func isDBInstanceAlreadyExists(err error) bool {
+	var ae smithy.APIError
+	if !errors.As(err, &ae) {
+		return false
+	}
+	code := ae.ErrorCode()
+	return code == "DBInstanceAlreadyExists" || code == "DBInstanceAlreadyExistsFault"
+}
+
+// findIdempotentDB returns the instance named name if it carries the
+// idempotency key tag with value key, or nil if it doesn't or can't be read
+func (d *AWSDatabase) findIdempotentDB(ctx context.Context, name, key string) *types.DBInstance {
+	input := &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(name),
+	}
+
+	logRequest(ctx, d.logger, "DescribeDBInstances", input)
+
+	var resp *rds.DescribeDBInstancesOutput
+	var err error
+
+	retryErr := awsretry.Do(ctx, d.logger, d.retryConfig, "database", "DescribeDBInstances", func(ctx context.Context) error {
+		resp, err = d.client.DescribeDBInstances(ctx, input, rdsOptions(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, d.logger, "DescribeDBInstances", resp, retryErr)
+
+	if retryErr != nil || resp == nil || len(resp.DBInstances) == 0 {
+		return nil
+	}
+	inst := resp.DBInstances[0]
+	for _, tag := range inst.TagList {
+		if aws.ToString(tag.Key) == cloudsdk.IdempotencyKeyTag && aws.ToString(tag.Value) == key {
+			return &inst
+		}
+	}
+	return nil
+}
+
 // ListDBs lists all RDS instances
 func (d *AWSDatabase) ListDBs(ctx context.Context) ([]*services.DBInstance, error) {
 	input := &rds.DescribeDBInstancesInput{}
 
-	logRequest("DescribeDBInstances", input, d.debug)
+	logRequest(ctx, d.logger, "DescribeDBInstances", input)
 
 	var resp *rds.DescribeDBInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, d.retryConfig, func() error {
-		resp, err = d.client.DescribeDBInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, d.logger, d.retryConfig, "database", "DescribeDBInstances", func(ctx context.Context) error {
+		resp, err = d.client.DescribeDBInstances(ctx, input, rdsOptions(ctx)...)
 		return err
 	})
 
-	logResponse("DescribeDBInstances", resp, retryErr, d.debug)
+	logResponse(ctx, d.logger, "DescribeDBInstances", resp, retryErr)
 
 	if retryErr != nil {
 		return nil, wrapRDSError(retryErr, "aws", "database", "ListDBs")
@@ -654,18 +632,18 @@ func (d *AWSDatabase) GetDB(ctx context.Context, id string) (*services.DBInstanc
 		DBInstanceIdentifier: aws.String(id),
 	}
 
-	logRequest("DescribeDBInstances", input, d.debug)
+	logRequest(ctx, d.logger, "DescribeDBInstances", input)
 
 	var resp *rds.DescribeDBInstancesOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, d.retryConfig, func() error {
-		resp, err = d.client.DescribeDBInstances(ctx, input)
+	retryErr := awsretry.Do(ctx, d.logger, d.retryConfig, "database", "DescribeDBInstances", func(ctx context.Context) error {
+		resp, err = d.client.DescribeDBInstances(ctx, input, rdsOptions(ctx)...)
 		return err
 	})
 
-	logResponse("DescribeDBInstances", resp, retryErr, d.debug)
+	logResponse(ctx, d.logger, "DescribeDBInstances", resp, retryErr)
 
 	if retryErr != nil {
 		return nil, wrapRDSError(retryErr, "aws", "database", "GetDB")
@@ -708,18 +686,23 @@ func (d *AWSDatabase) DeleteDB(ctx context.Context, id string) error {
 		DeleteAutomatedBackups: aws.Bool(true), // Delete automated backups
 	}
 
-	logRequest("DeleteDBInstance", input, d.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		recordPlannedCall(ctx, "DeleteDB", "DeleteDBInstance", input)
+		return nil
+	}
+
+	logRequest(ctx, d.logger, "DeleteDBInstance", input)
 
 	var resp *rds.DeleteDBInstanceOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, d.retryConfig, func() error {
-		resp, err = d.client.DeleteDBInstance(ctx, input)
+	retryErr := awsretry.Do(ctx, d.logger, d.retryConfig, "database", "DeleteDBInstance", func(ctx context.Context) error {
+		resp, err = d.client.DeleteDBInstance(ctx, input, rdsOptions(ctx)...)
 		return err
 	})
 
-	logResponse("DeleteDBInstance", resp, retryErr, d.debug)
+	logResponse(ctx, d.logger, "DeleteDBInstance", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapRDSError(retryErr, "aws", "database", "DeleteDB")
@@ -1,7 +1,12 @@
 package database
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +20,7 @@ import (
 type mockRDSClient struct {
 	createDBInstanceResponse    *rds.CreateDBInstanceOutput
 	createDBInstanceError       error
+	createDBInstanceInput       *rds.CreateDBInstanceInput
 	describeDBInstancesResponse *rds.DescribeDBInstancesOutput
 	describeDBInstancesError    error
 	deleteDBInstanceResponse    *rds.DeleteDBInstanceOutput
@@ -22,6 +28,7 @@ type mockRDSClient struct {
 }
 
 func (m *mockRDSClient) CreateDBInstance(ctx context.Context, input *rds.CreateDBInstanceInput, opts ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error) {
+	m.createDBInstanceInput = input
 	return m.createDBInstanceResponse, m.createDBInstanceError
 }
 
@@ -66,6 +73,106 @@ func TestAWSDatabase_CreateDB(t *testing.T) {
 	helper.AssertContains(db.Endpoint, "test-db.rds.amazonaws.com")
 }
 
+func TestAWSDatabase_CreateDBValidation(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	// The RDS client is never reached
+	mockClient := &mockRDSClient{createDBInstanceError: fmt.Errorf("unexpected CreateDBInstance call")}
+	database := NewWithClient(mockClient)
+
+	config := cloudsdktesting.GenerateDBConfig("2-db")
+	config.MasterUsername = "postgres"
+	config.AllocatedStorage = 10
+
+	_, err := database.CreateDB(context.Background(), config)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	// Tag rules and RDS rules are reported together
+	var errs cloudsdk.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected cloudsdk.ValidationErrors, got %v", err)
+	}
+	helper.AssertEqual(3, len(errs))
+	helper.AssertEqual("AllocatedStorage", errs[0].Field)
+	helper.AssertEqual("Name", errs[1].Field)
+	helper.AssertEqual("MasterUsername", errs[2].Field)
+	helper.AssertContains(errs[2].Message, "not allowed for PostgreSQL")
+}
+
+func TestAWSDatabase_LoggingRedactsPassword(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mockClient := &mockRDSClient{
+		createDBInstanceResponse: &rds.CreateDBInstanceOutput{
+			DBInstance: &types.DBInstance{
+				DBInstanceIdentifier: stringPtr("test-db"),
+				DBInstanceStatus:     stringPtr("creating"),
+			},
+		},
+	}
+	database := NewWithClient(mockClient, WithLogger(logger))
+	config := cloudsdktesting.GenerateDBConfig("test-db")
+
+	_, err := database.CreateDB(context.Background(), config)
+	helper.AssertNoError(err)
+
+	// Configurations logged by callers are redacted too
+	logger.Debug("created database", "config", config)
+
+	if strings.Contains(buf.String(), config.MasterPassword) {
+		t.Fatalf("master password was logged: %s", buf.String())
+	}
+	helper.AssertContains(buf.String(), "operation=CreateDBInstance")
+	helper.AssertEqual(2, strings.Count(buf.String(), "[REDACTED]"))
+}
+
+func TestAWSDatabase_CreateDBIdempotency(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	config := cloudsdktesting.GenerateDBConfig("test-db")
+	config.IdempotencyKey = "deploy-42-db"
+	keyTag := types.Tag{Key: stringPtr(cloudsdk.IdempotencyKeyTag), Value: stringPtr("deploy-42-db")}
+
+	// The key is sent as a tag of the new instance, after the config's tags
+	mockClient := &mockRDSClient{
+		createDBInstanceResponse: &rds.CreateDBInstanceOutput{
+			DBInstance: &types.DBInstance{DBInstanceIdentifier: stringPtr("test-db"), DBInstanceStatus: stringPtr("creating")},
+		},
+	}
+	_, err := NewWithClient(mockClient).CreateDB(ctx, config)
+	helper.AssertNoError(err)
+	tags := mockClient.createDBInstanceInput.Tags
+	helper.AssertEqual(len(config.Tags)+1, len(tags))
+	helper.AssertEqual(config.Tags[*tags[0].Key], *tags[0].Value)
+	helper.AssertEqual(cloudsdk.IdempotencyKeyTag, *tags[len(tags)-1].Key)
+	helper.AssertEqual("deploy-42-db", *tags[len(tags)-1].Value)
+
+	// Repeating the call returns the instance carrying the key
+	mockClient = &mockRDSClient{
+		createDBInstanceError: &types.DBInstanceAlreadyExistsFault{},
+		describeDBInstancesResponse: &rds.DescribeDBInstancesOutput{
+			DBInstances: []types.DBInstance{{
+				DBInstanceIdentifier: stringPtr("test-db"),
+				DBInstanceStatus:     stringPtr("creating"),
+				Engine:               stringPtr("mysql"),
+				TagList:              []types.Tag{keyTag},
+			}},
+		},
+	}
+	db, err := NewWithClient(mockClient).CreateDB(ctx, config)
+	helper.AssertNoError(err)
+	helper.AssertEqual("test-db", db.ID)
+	helper.AssertEqual("creating", db.Status)
+
+	// An instance created with another key is still a conflict
+	config.IdempotencyKey = "deploy-43-db"
+	_, err = NewWithClient(mockClient).CreateDB(ctx, config)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+}
+
 func TestAWSDatabase_CreateDB_ErrorScenarios(t *testing.T) {
 	helper := cloudsdktesting.NewTestHelper(t)
 
@@ -0,0 +1,38 @@
+// Package awserr adds the details of an AWS API error, such as its request
+// ID and error code, to the CloudError the compute, storage and database
+// services map it to.
+package awserr
+
+import (
+	"errors"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
+	"github.com/aws/smithy-go"
+)
+
+// Annotate records on cloudErr the AWS request ID, error code and HTTP
+// status of err, and how many attempts awsretry.Do made. err is kept as
+// the cause if cloudErr has none.
+func Annotate(cloudErr *cloudsdk.CloudError, err error) *cloudsdk.CloudError {
+	if cloudErr == nil || err == nil {
+		return cloudErr
+	}
+	if cloudErr.Cause == nil {
+		cloudErr.Cause = err
+	}
+	if requestID := awslog.RequestID(nil, err); requestID != "" {
+		cloudErr.Context.RequestID = requestID
+	}
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		cloudErr.Context.ProviderCode = ae.ErrorCode()
+	}
+	var withStatus interface{ HTTPStatusCode() int }
+	if errors.As(err, &withStatus) {
+		cloudErr.Context.StatusCode = withStatus.HTTPStatusCode()
+	}
+	cloudErr.Context.Attempts = awsretry.Attempts(err)
+	return cloudErr
+}
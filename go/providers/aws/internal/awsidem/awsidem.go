@@ -0,0 +1,23 @@
+// Package awsidem supplies the tokens with which the AWS compute, storage and
+// database services make create operations safe to retry.
+package awsidem
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Token returns key, or a random token if key is empty. Services send the
+// token with every attempt of a call, so awsretry.Do's retries are idempotent
+// even when the caller didn't pass an IdempotencyKey.
+func Token(key string) string {
+	if key != "" {
+		return key
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand doesn't fail on supported platforms
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,164 @@
+// Package awslog holds the structured logging helpers shared by the AWS
+// compute, storage and database services.
+//
+// Records are written with log/slog. Request and response bodies are logged
+// at debug level, retries at warn level, so the handler's level decides how
+// much detail is kept. Callers redact secrets from inputs before logging them.
+package awslog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/middleware"
+	smithymiddleware "github.com/aws/smithy-go/middleware"
+)
+
+// Redacted replaces secret values in log records.
+const Redacted = "[REDACTED]"
+
+// Logger returns logger, or slog.Default() if logger is nil. The helpers
+// below accept a nil logger and resolve it this way on every call, so a
+// service created before slog.SetDefault still follows the new default.
+func Logger(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// DebugLogger returns a logger that writes every record, including request
+// and response bodies, to stderr. It backs the Debug options.
+func DebugLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// Request logs an AWS API request at debug level.
+func Request(ctx context.Context, logger *slog.Logger, service, operation string, input interface{}) {
+	logger = Logger(logger)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, "aws request",
+		slog.String("service", service),
+		slog.String("operation", operation),
+		slog.Any("input", payload{input}),
+	)
+}
+
+// Response logs the outcome of an AWS API call at debug level.
+func Response(ctx context.Context, logger *slog.Logger, service, operation string, output interface{}, err error) {
+	logger = Logger(logger)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("service", service),
+		slog.String("operation", operation),
+	}
+	if requestID := RequestID(output, err); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logger.LogAttrs(ctx, slog.LevelDebug, "aws error", attrs...)
+		return
+	}
+	attrs = append(attrs, slog.Any("output", payload{output}))
+	logger.LogAttrs(ctx, slog.LevelDebug, "aws response", attrs...)
+}
+
+// Attempt logs one attempt made by a retry loop. Failed attempts that will be
+// retried are logged at warn level, everything else at debug level.
+func Attempt(ctx context.Context, logger *slog.Logger, service, operation string, attempt int, duration time.Duration, err error, retryDelay time.Duration) {
+	logger = Logger(logger)
+	level := slog.LevelDebug
+	if err != nil && retryDelay > 0 {
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("service", service),
+		slog.String("operation", operation),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+	}
+	if requestID := RequestID(nil, err); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if err == nil {
+		logger.LogAttrs(ctx, level, "aws attempt succeeded", attrs...)
+		return
+	}
+	attrs = append(attrs, slog.String("error", err.Error()))
+	if retryDelay > 0 {
+		attrs = append(attrs, slog.Duration("retry_after", retryDelay))
+		logger.LogAttrs(ctx, level, "aws attempt failed, retrying", attrs...)
+		return
+	}
+	logger.LogAttrs(ctx, level, "aws attempt failed", attrs...)
+}
+
+// payload logs an SDK input or output as JSON, so pointer fields show their
+// values rather than addresses with both the text and JSON handlers.
+type payload struct {
+	v interface{}
+}
+
+func (p payload) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(p.v)
+	if err != nil {
+		return json.Marshal(fmt.Sprintf("%+v", p.v))
+	}
+	return data, nil
+}
+
+func (p payload) MarshalText() ([]byte, error) {
+	return p.MarshalJSON()
+}
+
+// RequestID returns the AWS request ID of a call, taken from err when the
+// call failed and from the output's ResultMetadata otherwise.
+func RequestID(output interface{}, err error) string {
+	if err != nil {
+		var withID interface{ ServiceRequestID() string }
+		if errors.As(err, &withID) {
+			return withID.ServiceRequestID()
+		}
+		return ""
+	}
+	if output == nil {
+		return ""
+	}
+
+	// Every SDK output struct carries its metadata in a ResultMetadata field.
+	v := reflect.ValueOf(output)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	field := v.FieldByName("ResultMetadata")
+	if !field.IsValid() || !field.CanInterface() {
+		return ""
+	}
+	metadata, ok := field.Interface().(smithymiddleware.Metadata)
+	if !ok {
+		return ""
+	}
+	requestID, _ := middleware.GetRequestIDMetadata(metadata)
+	return requestID
+}
@@ -0,0 +1,135 @@
+// Package awsretry runs AWS API calls under a cloudsdk.RetryPolicy for the
+// compute, storage and database services. It recognizes AWS error codes and
+// Retry-After headers, starts a span per attempt and logs every attempt.
+package awsretry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// retryableCodes are the AWS error codes for throttling and transient
+// service failures, across EC2, S3 and RDS.
+var retryableCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"SlowDown":                               true,
+	"ProvisionedThroughputExceededException": true,
+	"PriorRequestNotComplete":                true,
+	"BandwidthLimitExceeded":                 true,
+	"EC2ThrottledException":                  true,
+	"InternalError":                          true,
+	"InternalFailure":                        true,
+	"InternalServerError":                    true,
+	"ServiceUnavailable":                     true,
+	"RequestTimeout":                         true,
+	"RequestTimeoutException":                true,
+}
+
+// IsRetryable reports whether an AWS API error is worth retrying. It checks
+// the AWS error code first and falls back to cloudsdk.IsRetryable, which
+// covers HTTP status codes and network timeouts.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var ae smithy.APIError
+	if errors.As(err, &ae) && retryableCodes[ae.ErrorCode()] {
+		return true
+	}
+	return cloudsdk.IsRetryable(err)
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of the
+// HTTP response behind err, or zero if there is none.
+func RetryAfter(err error) time.Duration {
+	var re *smithyhttp.ResponseError
+	if !errors.As(err, &re) || re.Response == nil || re.Response.Response == nil {
+		return cloudsdk.RetryAfterHint(err)
+	}
+	value := strings.TrimSpace(re.Response.Header.Get("Retry-After"))
+	if value == "" {
+		return cloudsdk.RetryAfterHint(err)
+	}
+	if seconds, parseErr := strconv.Atoi(value); parseErr == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, parseErr := http.ParseTime(value); parseErr == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// attemptsError records how many attempts Do made before failing.
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string { return e.err.Error() }
+
+func (e *attemptsError) Unwrap() error { return e.err }
+
+// Attempts returns how many attempts Do made before returning err. Errors
+// that didn't come from Do count as a single attempt.
+func Attempts(err error) int {
+	var ae *attemptsError
+	if errors.As(err, &ae) {
+		return ae.attempts
+	}
+	return 1
+}
+
+// Do calls fn under policy, or under the policy carried in ctx by a Client.
+// Each attempt runs in a span named "aws.<service>.<apiName>" and is logged
+// to logger. Errors returned by Do report their attempt count to Attempts.
+func Do(ctx context.Context, logger *slog.Logger, policy cloudsdk.RetryPolicy, service, apiName string, fn func(ctx context.Context) error) error {
+	attempts := 0
+	opts := cloudsdk.RetryOptions{
+		Retryable:  IsRetryable,
+		RetryAfter: RetryAfter,
+		OnAttempt: func(ctx context.Context, attempt cloudsdk.RetryAttempt) {
+			awslog.Attempt(ctx, logger, service, apiName, attempt.Number, attempt.Duration, attempt.Err, attempt.Delay)
+		},
+	}
+	err := cloudsdk.Retry(ctx, policy, opts, func(ctx context.Context, attempt int) error {
+		attempts = attempt
+		ctx, span := cloudsdk.StartSpan(ctx, "aws."+service+"."+apiName,
+			cloudsdk.StringAttr(cloudsdk.AttrProvider, "aws"),
+			cloudsdk.StringAttr(cloudsdk.AttrService, service),
+			cloudsdk.StringAttr(cloudsdk.AttrOperation, apiName),
+			cloudsdk.IntAttr(cloudsdk.AttrAttempt, attempt),
+		)
+		defer span.End()
+
+		err := fn(ctx)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	})
+	if err != nil && attempts > 0 {
+		return &attemptsError{err: err, attempts: attempts}
+	}
+	return err
+}
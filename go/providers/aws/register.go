@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+)
+
+func init() {
+	cloudsdk.Register("aws", newFromURL)
+}
+
+// newFromURL builds an AWSProvider from a provider URL such as
+// "aws://us-east-1?profile=prod&timeout=30s&retries=5&debug=true".
+//
+// Supported parameters:
+//   - profile: AWS profile name (see WithProfile)
+//   - timeout: API call timeout as a Go duration (see WithTimeout)
+//   - retries: maximum retry attempts (see WithRetryMaxAttempts)
+//   - debug:   enable debug logging (see WithDebug)
+//
+// Explicit credentials are intentionally not accepted in URLs; use
+// environment variables or profiles instead.
+func newFromURL(region string, params url.Values) (cloudsdk.Provider, error) {
+	var options []Option
+
+	for key, values := range params {
+		value := values[len(values)-1]
+
+		switch key {
+		case "profile":
+			options = append(options, WithProfile(value))
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, cloudsdk.NewInvalidConfigError("aws", "", "timeout", fmt.Sprintf("invalid duration '%s'", value)).
+					WithSuggestions("Use a Go duration such as '30s' or '2m'")
+			}
+			options = append(options, WithTimeout(timeout))
+		case "retries":
+			retries, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, cloudsdk.NewInvalidConfigError("aws", "", "retries", fmt.Sprintf("invalid integer '%s'", value)).
+					WithSuggestions("Use a whole number of attempts such as '3'")
+			}
+			options = append(options, WithRetryMaxAttempts(retries))
+		case "debug":
+			debug, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, cloudsdk.NewInvalidConfigError("aws", "", "debug", fmt.Sprintf("invalid boolean '%s'", value)).
+					WithSuggestions("Use 'true' or 'false'")
+			}
+			if debug {
+				options = append(options, WithDebug())
+			}
+		default:
+			return nil, cloudsdk.NewInvalidConfigError("aws", "", key, "unknown provider URL parameter").
+				WithSuggestions("Supported parameters are: profile, timeout, retries, debug")
+		}
+	}
+
+	return New(region, options...)
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"sort"
 	"strings"
-	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awserr"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awslog"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws/internal/awsretry"
 	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -17,109 +20,24 @@ import (
 	"github.com/aws/smithy-go"
 )
 
-// RetryConfig defines retry behavior for AWS S3 operations
-type RetryConfig struct {
-	MaxAttempts   int
-	InitialDelay  time.Duration
-	MaxDelay      time.Duration
-	BackoffFactor float64
-}
+// RetryConfig defines retry behavior for AWS operations.
+// It is the shared cloudsdk.RetryPolicy.
+type RetryConfig = cloudsdk.RetryPolicy
 
 // DefaultRetryConfig provides sensible defaults for retry behavior
-var DefaultRetryConfig = RetryConfig{
-	MaxAttempts:   3,
-	InitialDelay:  100 * time.Millisecond,
-	MaxDelay:      5 * time.Second,
-	BackoffFactor: 2.0,
-}
-
-// retryWithBackoff executes a function with exponential backoff retry logic
-func retryWithBackoff(ctx context.Context, config RetryConfig, operation func() error) error {
-	var lastErr error
-	delay := config.InitialDelay
-
-	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
-		if attempt > 1 {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
-			}
-		}
-
-		err := operation()
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-		if !isRetryableS3Error(err) {
-			return err
-		}
-
-		if attempt < config.MaxAttempts {
-			delay = time.Duration(float64(delay) * config.BackoffFactor)
-			if delay > config.MaxDelay {
-				delay = config.MaxDelay
-			}
-			log.Printf("AWS Storage: Retrying operation (attempt %d/%d) after %v due to: %v",
-				attempt+1, config.MaxAttempts, delay, err)
-		}
-	}
-
-	return lastErr
-}
-
-// isRetryableS3Error determines if an S3 error should be retried
-func isRetryableS3Error(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	// Check for context cancellation/timeout - don't retry
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-		return false
-	}
+var DefaultRetryConfig = cloudsdk.DefaultRetryPolicy
 
-	// Check for AWS-specific retryable errors
-	var ae smithy.APIError
-	if errors.As(err, &ae) {
-		code := ae.ErrorCode()
-		switch code {
-		case "Throttling", "ThrottlingException", "RequestLimitExceeded", "SlowDown":
-			return true
-		case "InternalError", "InternalFailure", "ServiceUnavailable":
-			return true
-		case "RequestTimeout", "RequestTimeoutException":
-			return true
-		}
-	}
-
-	// Check error message for common transient issues
-	errMsg := strings.ToLower(err.Error())
-	transientMessages := []string{
-		"connection reset",
-		"connection refused",
-		"timeout",
-		"temporary failure",
-		"service unavailable",
-		"internal error",
-	}
-
-	for _, msg := range transientMessages {
-		if strings.Contains(errMsg, msg) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// wrapS3Error converts S3 errors to CloudError with helpful context
+// wrapS3Error converts S3 errors to CloudError with helpful context, including
+// the request ID, error code, HTTP status and attempt count of the failure
 func wrapS3Error(err error, provider, service, operation string) error {
 	if err == nil {
 		return nil
 	}
+	return awserr.Annotate(classifyS3Error(err, provider, service, operation), err)
+}
+
+// classifyS3Error maps an S3 error to the CloudError code and suggestions for it
+func classifyS3Error(err error, provider, service, operation string) *cloudsdk.CloudError {
 
 	// Handle context errors
 	if errors.Is(err, context.Canceled) {
@@ -193,7 +111,7 @@ func wrapS3Error(err error, provider, service, operation string) error {
 				)
 
 		case "SlowDown", "RequestLimitExceeded":
-			return cloudsdk.NewRateLimitError(provider, service, operation, 0).
+			return cloudsdk.NewRateLimitError(provider, service, operation, awsretry.RetryAfter(err)).
 				WithCause(err).
 				WithSuggestions(
 					"Reduce the frequency of API calls",
@@ -201,6 +119,32 @@ func wrapS3Error(err error, provider, service, operation string) error {
 					"Consider using S3 Transfer Acceleration",
 				)
 
+		case "BucketNotEmpty":
+			return cloudsdk.NewDependencyViolationError(provider, service, operation, message).
+				WithCause(err).
+				WithSuggestions(
+					"Delete all objects and object versions in the bucket first",
+				)
+
+		case "TooManyBuckets":
+			return cloudsdk.NewQuotaExceededError(provider, service, operation, message).
+				WithCause(err).
+				WithSuggestions(
+					"Delete unused buckets",
+					"Request a bucket limit increase through AWS Service Quotas",
+				)
+
+		case "InvalidObjectState":
+			return cloudsdk.NewInvalidStateError(provider, service, operation, "", "").
+				WithCause(err).
+				WithSuggestions(
+					"Restore archived objects before reading them",
+				)
+
+		case "PreconditionFailed":
+			return cloudsdk.NewPreconditionFailedError(provider, service, operation, message).
+				WithCause(err)
+
 		case "EntityTooLarge":
 			return cloudsdk.NewInvalidConfigError(provider, service, "ObjectSize", "Object too large").
 				WithSuggestions(
@@ -252,22 +196,39 @@ func extractBucketNameFromError(message string) string {
 	return "unknown"
 }
 
-// logRequest logs S3 API requests for debugging (when debug is enabled)
-func logRequest(operation string, input interface{}, debug bool) {
-	if debug {
-		log.Printf("AWS Storage: %s request: %+v", operation, input)
+// s3Options returns the per-request overrides for the call's
+// services.CallOptions: a Region option sends the request to that region
+func s3Options(ctx context.Context) []func(*s3.Options) {
+	region := services.CallOptionsFromContext(ctx).Region
+	if region == "" {
+		return nil
 	}
+	return []func(*s3.Options){func(o *s3.Options) {
+		o.Region = region
+	}}
 }
 
-// logResponse logs S3 API responses for debugging (when debug is enabled)
-func logResponse(operation string, output interface{}, err error, debug bool) {
-	if debug {
-		if err != nil {
-			log.Printf("AWS Storage: %s error: %v", operation, err)
-		} else {
-			log.Printf("AWS Storage: %s response: %+v", operation, output)
-		}
-	}
+// logRequest logs S3 API requests at debug level. The S3 inputs built here
+// carry no secrets; object bodies are replaced before logging.
+func logRequest(ctx context.Context, logger *slog.Logger, operation string, input interface{}) {
+	awslog.Request(ctx, logger, "storage", operation, input)
+}
+
+// logResponse logs S3 API responses and errors at debug level
+func logResponse(ctx context.Context, logger *slog.Logger, operation string, output interface{}, err error) {
+	awslog.Response(ctx, logger, "storage", operation, output, err)
+}
+
+// recordPlannedCall adds an S3 call to the dry run's plan. S3 has no way to
+// check permissions without making the call, so none is sent.
+func recordPlannedCall(ctx context.Context, operation, api string, input interface{}) {
+	cloudsdk.RecordPlannedCall(ctx, cloudsdk.PlannedCall{
+		Provider:  "aws",
+		Service:   cloudsdk.ServiceStorage,
+		Operation: operation,
+		API:       api,
+		Input:     input,
+	})
 }
 
 // S3ClientInterface defines methods we need from S3 client for testing
@@ -276,6 +237,8 @@ type S3ClientInterface interface {
 	ListBuckets(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
 	DeleteBucket(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
 	PutBucketVersioning(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error)
+	PutBucketTagging(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error)
+	GetBucketTagging(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
 	PutObject(ctx context.Context, input *s3.PutObjectInput, opts ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 	GetObject(ctx context.Context, input *s3.GetObjectInput, opts ...func(*s3.Options)) (*s3.GetObjectOutput, error)
 	DeleteObject(ctx context.Context, input *s3.DeleteObjectInput, opts ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
@@ -285,91 +248,127 @@ type S3ClientInterface interface {
 // AWSStorage implements the Storage interface for AWS
 type AWSStorage struct {
 	client      S3ClientInterface
-	debug       bool
+	logger      *slog.Logger
 	retryConfig RetryConfig
 }
 
-// New creates a new AWSStorage instance with real AWS client
-func New(cfg aws.Config) services.Storage {
-	client := s3.NewFromConfig(cfg)
-	return &AWSStorage{
-		client:      client,
-		debug:       false,
-		retryConfig: DefaultRetryConfig,
+// Option configures an AWSStorage created by New or NewWithClient
+type Option func(*AWSStorage)
+
+// WithLogger sets the logger for S3 requests, responses and retries.
+// Requests and responses are logged at debug level. A nil logger means
+// slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *AWSStorage) {
+		s.logger = logger
 	}
 }
 
+// New creates a new AWSStorage instance with real AWS client
+func New(cfg aws.Config, opts ...Option) services.Storage {
+	return newAWSStorage(s3.NewFromConfig(cfg), DefaultRetryConfig, opts)
+}
+
 // NewWithClient creates a new AWSStorage instance with custom client (for testing)
-func NewWithClient(client S3ClientInterface) services.Storage {
-	return &AWSStorage{
-		client:      client,
-		debug:       false,
-		retryConfig: DefaultRetryConfig,
-	}
+func NewWithClient(client S3ClientInterface, opts ...Option) services.Storage {
+	return newAWSStorage(client, DefaultRetryConfig, opts)
 }
 
-// NewWithOptions creates a new AWSStorage instance with custom options
+// NewWithOptions creates a new AWSStorage instance with custom options.
+// When debug is true, everything is logged to stderr, including requests and
+// responses.
 func NewWithOptions(cfg aws.Config, debug bool, retryConfig *RetryConfig) services.Storage {
-	client := s3.NewFromConfig(cfg)
-
 	finalRetryConfig := DefaultRetryConfig
 	if retryConfig != nil {
 		finalRetryConfig = *retryConfig
 	}
 
-	return &AWSStorage{
+	var opts []Option
+	if debug {
+		opts = append(opts, WithLogger(awslog.DebugLogger()))
+	}
+	return newAWSStorage(s3.NewFromConfig(cfg), finalRetryConfig, opts)
+}
+
+func newAWSStorage(client S3ClientInterface, retryConfig RetryConfig, opts []Option) *AWSStorage {
+	s := &AWSStorage{
 		client:      client,
-		debug:       debug,
-		retryConfig: finalRetryConfig,
+		retryConfig: retryConfig,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // CreateBucket creates a new S3 bucket
 func (s *AWSStorage) CreateBucket(ctx context.Context, config *services.BucketConfig) error {
 	// Validate input configuration
-	if config == nil {
-		return cloudsdk.NewInvalidConfigError("aws", "storage", "config", "bucket configuration cannot be nil")
-	}
-	if config.Name == "" {
-		return cloudsdk.NewInvalidConfigError("aws", "storage", "Name", "bucket name is required")
-	}
-
-	// Validate bucket name format
-	if err := validateBucketName(config.Name); err != nil {
-		return cloudsdk.NewInvalidConfigError("aws", "storage", "Name", err.Error()).
-			WithSuggestions(
-				"Bucket names must be 3-63 characters long",
-				"Use only lowercase letters, numbers, and hyphens",
-				"Start and end with a letter or number",
-				"Don't use periods or underscores",
-			)
+	if err := cloudsdk.ValidateConfig("aws", "storage", config); err != nil {
+		return err
 	}
 
 	input := &s3.CreateBucketInput{
 		Bucket: aws.String(config.Name),
 	}
 
-	// Add region configuration if specified and not us-east-1 (default)
-	if config.Region != "" && config.Region != "us-east-1" {
+	// Add region configuration if specified and not us-east-1 (default).
+	// A bucket created with a Region call option is placed in that region.
+	region := config.Region
+	if region == "" {
+		region = services.CallOptionsFromContext(ctx).Region
+	}
+	if region != "" && region != "us-east-1" {
 		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
-			LocationConstraint: types.BucketLocationConstraint(config.Region),
+			LocationConstraint: types.BucketLocationConstraint(region),
 		}
 	}
 
-	logRequest("CreateBucket", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		recordPlannedCall(ctx, "CreateBucket", "CreateBucket", input)
+		if config.Versioning != nil && *config.Versioning {
+			recordPlannedCall(ctx, "CreateBucket", "PutBucketVersioning", &s3.PutBucketVersioningInput{
+				Bucket: aws.String(config.Name),
+				VersioningConfiguration: &types.VersioningConfiguration{
+					Status: types.BucketVersioningStatusEnabled,
+				},
+			})
+		}
+		if len(config.Tags) > 0 || config.IdempotencyKey != "" {
+			recordPlannedCall(ctx, "CreateBucket", "PutBucketTagging", bucketTaggingInput(config))
+		}
+		return nil
+	}
+
+	logRequest(ctx, s.logger, "CreateBucket", input)
 
 	var resp *s3.CreateBucketOutput
 	var err error
+	attempts := 0
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.CreateBucket(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "CreateBucket", func(ctx context.Context) error {
+		attempts++
+		resp, err = s.client.CreateBucket(ctx, input, s3Options(ctx)...)
+		if attempts > 1 && isBucketAlreadyOwnedByYou(err) {
+			// An earlier attempt created the bucket but its response was lost
+			return nil
+		}
 		return err
 	})
 
-	logResponse("CreateBucket", resp, retryErr, s.debug)
+	logResponse(ctx, s.logger, "CreateBucket", resp, retryErr)
 
-	if retryErr != nil {
+	switch {
+	case retryErr == nil:
+		if len(config.Tags) > 0 || config.IdempotencyKey != "" {
+			if err := s.tagBucket(ctx, config); err != nil {
+				return err
+			}
+		}
+	case config.IdempotencyKey != "" && isBucketAlreadyOwnedByYou(retryErr) && s.hasIdempotencyKey(ctx, config):
+		// A previous CreateBucket call with the same key created the bucket
+	default:
 		return wrapS3Error(retryErr, "aws", "storage", "CreateBucket")
 	}
 
@@ -382,18 +381,22 @@ func (s *AWSStorage) CreateBucket(ctx context.Context, config *services.BucketCo
 			},
 		}
 
-		logRequest("PutBucketVersioning", versioningInput, s.debug)
+		logRequest(ctx, s.logger, "PutBucketVersioning", versioningInput)
 
 		var versioningResp *s3.PutBucketVersioningOutput
-		versioningRetryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-			versioningResp, err = s.client.PutBucketVersioning(ctx, versioningInput)
+		versioningRetryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "PutBucketVersioning", func(ctx context.Context) error {
+			versioningResp, err = s.client.PutBucketVersioning(ctx, versioningInput, s3Options(ctx)...)
 			return err
 		})
 
-		logResponse("PutBucketVersioning", versioningResp, versioningRetryErr, s.debug)
+		logResponse(ctx, s.logger, "PutBucketVersioning", versioningResp, versioningRetryErr)
 
 		if versioningRetryErr != nil {
-			log.Printf("AWS Storage: Warning - failed to enable versioning for bucket %s: %v", config.Name, versioningRetryErr)
+			awslog.Logger(s.logger).WarnContext(ctx, "failed to enable bucket versioning",
+				slog.String("service", "storage"),
+				slog.String("bucket", config.Name),
+				slog.String("error", versioningRetryErr.Error()),
+			)
 			// Don't fail the entire operation if versioning fails
 		}
 	}
@@ -401,77 +404,121 @@ func (s *AWSStorage) CreateBucket(ctx context.Context, config *services.BucketCo
 	return nil
 }
 
-// validateBucketName validates S3 bucket naming rules
-func validateBucketName(name string) error {
-	if len(name) < 3 || len(name) > 63 {
-		return fmt.Errorf("bucket name must be between 3 and 63 characters")
-	}
+// isBucketAlreadyOwnedByYou reports whether err is S3's error for creating
+// a bucket that the caller's account already owns
+func isBucketAlreadyOwnedByYou(err error) bool {
+	var ae smithy.APIError
+	return errors.As(err, &ae) && ae.ErrorCode() == "BucketAlreadyOwnedByYou"
+}
 
-	// Check for valid characters and format
-	for i, r := range name {
-		if i == 0 || i == len(name)-1 {
-			// First and last character must be alphanumeric
-			if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
-				return fmt.Errorf("bucket name must start and end with a letter or number")
-			}
-		} else {
-			// Middle characters can be alphanumeric or hyphen
-			if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-') {
-				return fmt.Errorf("bucket name can only contain lowercase letters, numbers, and hyphens")
-			}
+// bucketTaggingInput returns the request that sets the tags of the bucket
+// named by config: config.Tags and, if set, config's idempotency key.
+// PutBucketTagging replaces a bucket's whole tag set, so every tag is sent in
+// one request.
+func bucketTaggingInput(config *services.BucketConfig) *s3.PutBucketTaggingInput {
+	keys := make([]string, 0, len(config.Tags))
+	for key := range config.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tagSet := make([]types.Tag, 0, len(keys)+1)
+	for _, key := range keys {
+		if key == cloudsdk.IdempotencyKeyTag {
+			continue
 		}
+		tagSet = append(tagSet, types.Tag{Key: aws.String(key), Value: aws.String(config.Tags[key])})
+	}
+	if config.IdempotencyKey != "" {
+		tagSet = append(tagSet, types.Tag{
+			Key:   aws.String(cloudsdk.IdempotencyKeyTag),
+			Value: aws.String(config.IdempotencyKey),
+		})
 	}
 
-	// Check for consecutive hyphens or periods
-	if strings.Contains(name, "--") || strings.Contains(name, "..") {
-		return fmt.Errorf("bucket name cannot contain consecutive hyphens or periods")
-	}
-
-	// Check for IP address format
-	if strings.Count(name, ".") == 3 {
-		parts := strings.Split(name, ".")
-		if len(parts) == 4 {
-			allNumeric := true
-			for _, part := range parts {
-				if len(part) == 0 || len(part) > 3 {
-					allNumeric = false
-					break
-				}
-				for _, r := range part {
-					if r < '0' || r > '9' {
-						allNumeric = false
-						break
-					}
-				}
-				if !allNumeric {
-					break
-				}
-			}
-			if allNumeric {
-				return fmt.Errorf("bucket name cannot be formatted as an IP address")
-			}
-		}
+	return &s3.PutBucketTaggingInput{
+		Bucket:  aws.String(config.Name),
+		Tagging: &types.Tagging{TagSet: tagSet},
 	}
+}
+
+// tagBucket sets the tags of the bucket just created: config.Tags and
+// config's idempotency key. CreateBucket takes no tags, so they are set by a
+// second call. S3 has no client tokens, so the key's tag is how a repeated
+// CreateBucket call recognizes the bucket as its own. If tagging fails the
+// bucket exists without its tags and a retry can't claim it, so the error is
+// returned rather than logged.
+func (s *AWSStorage) tagBucket(ctx context.Context, config *services.BucketConfig) error {
+	input := bucketTaggingInput(config)
+
+	logRequest(ctx, s.logger, "PutBucketTagging", input)
 
+	var resp *s3.PutBucketTaggingOutput
+	var err error
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "PutBucketTagging", func(ctx context.Context) error {
+		resp, err = s.client.PutBucketTagging(ctx, input, s3Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "PutBucketTagging", resp, retryErr)
+
+	if retryErr != nil {
+		cloudErr := classifyS3Error(retryErr, "aws", "storage", "CreateBucket")
+		cloudErr.Message = fmt.Sprintf("Bucket %s was created but not tagged: %s", config.Name, cloudErr.Message)
+		cloudErr.Suggestions = append([]string{
+			"Delete the bucket before retrying the create",
+			"Verify your IAM user/role has the s3:PutBucketTagging permission",
+		}, cloudErr.Suggestions...)
+		return awserr.Annotate(cloudErr, retryErr)
+	}
 	return nil
 }
 
+// hasIdempotencyKey reports whether the bucket named by config carries
+// config's idempotency key in its tags
+func (s *AWSStorage) hasIdempotencyKey(ctx context.Context, config *services.BucketConfig) bool {
+	input := &s3.GetBucketTaggingInput{
+		Bucket: aws.String(config.Name),
+	}
+
+	logRequest(ctx, s.logger, "GetBucketTagging", input)
+
+	var resp *s3.GetBucketTaggingOutput
+	var err error
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "GetBucketTagging", func(ctx context.Context) error {
+		resp, err = s.client.GetBucketTagging(ctx, input, s3Options(ctx)...)
+		return err
+	})
+
+	logResponse(ctx, s.logger, "GetBucketTagging", resp, retryErr)
+
+	if retryErr != nil {
+		return false
+	}
+	for _, tag := range resp.TagSet {
+		if aws.ToString(tag.Key) == cloudsdk.IdempotencyKeyTag && aws.ToString(tag.Value) == config.IdempotencyKey {
+			return true
+		}
+	}
+	return false
+}
+
 // ListBuckets lists all S3 buckets
 func (s *AWSStorage) ListBuckets(ctx context.Context) ([]string, error) {
 	input := &s3.ListBucketsInput{}
 
-	logRequest("ListBuckets", input, s.debug)
+	logRequest(ctx, s.logger, "ListBuckets", input)
 
 	var resp *s3.ListBucketsOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.ListBuckets(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "ListBuckets", func(ctx context.Context) error {
+		resp, err = s.client.ListBuckets(ctx, input, s3Options(ctx)...)
 		return err
 	})
 
-	logResponse("ListBuckets", resp, retryErr, s.debug)
+	logResponse(ctx, s.logger, "ListBuckets", resp, retryErr)
 
 	if retryErr != nil {
 		return nil, wrapS3Error(retryErr, "aws", "storage", "ListBuckets")
@@ -496,18 +543,23 @@ func (s *AWSStorage) DeleteBucket(ctx context.Context, name string) error {
 		Bucket: aws.String(name),
 	}
 
-	logRequest("DeleteBucket", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		recordPlannedCall(ctx, "DeleteBucket", "DeleteBucket", input)
+		return nil
+	}
+
+	logRequest(ctx, s.logger, "DeleteBucket", input)
 
 	var resp *s3.DeleteBucketOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.DeleteBucket(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "DeleteBucket", func(ctx context.Context) error {
+		resp, err = s.client.DeleteBucket(ctx, input, s3Options(ctx)...)
 		return err
 	})
 
-	logResponse("DeleteBucket", resp, retryErr, s.debug)
+	logResponse(ctx, s.logger, "DeleteBucket", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapS3Error(retryErr, "aws", "storage", "DeleteBucket")
@@ -528,13 +580,29 @@ func (s *AWSStorage) PutObject(ctx context.Context, bucket, key string, body io.
 		return cloudsdk.NewInvalidConfigError("aws", "storage", "body", "object body cannot be nil")
 	}
 
+	if cloudsdk.IsDryRun(ctx) {
+		// The body isn't read, so it can still be uploaded afterwards
+		recordPlannedCall(ctx, "PutObject", "PutObject", &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		return nil
+	}
+
 	// Wrap the reader with progress tracking if it's a large upload
 	var wrappedBody io.Reader = body
-	if s.debug {
+	logger := awslog.Logger(s.logger)
+	if logger.Enabled(ctx, slog.LevelDebug) {
 		wrappedBody = &progressReader{
 			reader: body,
 			onProgress: func(bytesRead int64) {
-				log.Printf("AWS Storage: PutObject progress - uploaded %d bytes to %s/%s", bytesRead, bucket, key)
+				logger.DebugContext(ctx, "aws upload progress",
+					slog.String("service", "storage"),
+					slog.String("operation", "PutObject"),
+					slog.String("bucket", bucket),
+					slog.String("key", key),
+					slog.Int64("bytes", bytesRead),
+				)
 			},
 		}
 	}
@@ -545,22 +613,22 @@ func (s *AWSStorage) PutObject(ctx context.Context, bucket, key string, body io.
 		Body:   wrappedBody,
 	}
 
-	logRequest("PutObject", map[string]interface{}{
+	logRequest(ctx, s.logger, "PutObject", map[string]interface{}{
 		"Bucket": bucket,
 		"Key":    key,
 		"Body":   "[BINARY DATA]",
-	}, s.debug)
+	})
 
 	var resp *s3.PutObjectOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.PutObject(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "PutObject", func(ctx context.Context) error {
+		resp, err = s.client.PutObject(ctx, input, s3Options(ctx)...)
 		return err
 	})
 
-	logResponse("PutObject", resp, retryErr, s.debug)
+	logResponse(ctx, s.logger, "PutObject", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapS3Error(retryErr, "aws", "storage", "PutObject")
@@ -599,22 +667,18 @@ func (s *AWSStorage) GetObject(ctx context.Context, bucket, key string) (io.Read
 		Key:    aws.String(key),
 	}
 
-	logRequest("GetObject", input, s.debug)
+	logRequest(ctx, s.logger, "GetObject", input)
 
 	var resp *s3.GetObjectOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.GetObject(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "GetObject", func(ctx context.Context) error {
+		resp, err = s.client.GetObject(ctx, input, s3Options(ctx)...)
 		return err
 	})
 
-	logResponse("GetObject", map[string]interface{}{
-		"ContentLength": resp.ContentLength,
-		"ContentType":   resp.ContentType,
-		"LastModified":  resp.LastModified,
-	}, retryErr, s.debug)
+	logResponse(ctx, s.logger, "GetObject", resp, retryErr)
 
 	if retryErr != nil {
 		return nil, wrapS3Error(retryErr, "aws", "storage", "GetObject")
@@ -637,18 +701,23 @@ func (s *AWSStorage) DeleteObject(ctx context.Context, bucket, key string) error
 		Key:    aws.String(key),
 	}
 
-	logRequest("DeleteObject", input, s.debug)
+	if cloudsdk.IsDryRun(ctx) {
+		recordPlannedCall(ctx, "DeleteObject", "DeleteObject", input)
+		return nil
+	}
+
+	logRequest(ctx, s.logger, "DeleteObject", input)
 
 	var resp *s3.DeleteObjectOutput
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.DeleteObject(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "DeleteObject", func(ctx context.Context) error {
+		resp, err = s.client.DeleteObject(ctx, input, s3Options(ctx)...)
 		return err
 	})
 
-	logResponse("DeleteObject", resp, retryErr, s.debug)
+	logResponse(ctx, s.logger, "DeleteObject", resp, retryErr)
 
 	if retryErr != nil {
 		return wrapS3Error(retryErr, "aws", "storage", "DeleteObject")
@@ -667,18 +736,18 @@ func (s *AWSStorage) ListObjects(ctx context.Context, bucket string) ([]*service
 		Bucket: aws.String(bucket),
 	}
 
-	logRequest("ListObjectsV2", input, s.debug)
+	logRequest(ctx, s.logger, "ListObjectsV2", input)
 
 	var resp *s3.ListObjectsV2Output
 	var err error
 
 	// Execute with retry logic
-	retryErr := retryWithBackoff(ctx, s.retryConfig, func() error {
-		resp, err = s.client.ListObjectsV2(ctx, input)
+	retryErr := awsretry.Do(ctx, s.logger, s.retryConfig, "storage", "ListObjectsV2", func(ctx context.Context) error {
+		resp, err = s.client.ListObjectsV2(ctx, input, s3Options(ctx)...)
 		return err
 	})
 
-	logResponse("ListObjectsV2", resp, retryErr, s.debug)
+	logResponse(ctx, s.logger, "ListObjectsV2", resp, retryErr)
 
 	if retryErr != nil {
 		return nil, wrapS3Error(retryErr, "aws", "storage", "ListObjects")
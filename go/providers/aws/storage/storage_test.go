@@ -1,15 +1,20 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"log/slog"
 	"strings"
 	"testing"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
 	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 )
 
 // mockS3Client is a mock implementation of the S3 client
@@ -28,6 +33,12 @@ type mockS3Client struct {
 	deleteObjectError    error
 	listObjectsResponse  *s3.ListObjectsV2Output
 	listObjectsError     error
+
+	// Tagging
+	putBucketTaggingInput    *s3.PutBucketTaggingInput
+	putBucketTaggingError    error
+	getBucketTaggingResponse *s3.GetBucketTaggingOutput
+	getBucketTaggingError    error
 }
 
 func (m *mockS3Client) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
@@ -75,6 +86,114 @@ func TestAWSStorage_CreateBucket(t *testing.T) {
 
 	err := storage.CreateBucket(context.Background(), config)
 	helper.AssertNoError(err)
+
+	// The bucket's tags are set after it is created
+	if mockClient.putBucketTaggingInput == nil {
+		t.Fatal("expected the bucket to be tagged")
+	}
+	tagSet := mockClient.putBucketTaggingInput.Tagging.TagSet
+	helper.AssertEqual(len(config.Tags), len(tagSet))
+	for _, tag := range tagSet {
+		helper.AssertEqual(config.Tags[aws.ToString(tag.Key)], aws.ToString(tag.Value))
+	}
+}
+
+func (m *mockS3Client) PutBucketTagging(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+	m.putBucketTaggingInput = input
+	return &s3.PutBucketTaggingOutput{}, m.putBucketTaggingError
+}
+
+func (m *mockS3Client) GetBucketTagging(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	return m.getBucketTaggingResponse, m.getBucketTaggingError
+}
+
+func TestAWSStorage_CreateBucketIdempotency(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	config := cloudsdktesting.GenerateBucketConfig("test-bucket")
+	config.Tags = map[string]string{"Team": "platform"}
+	config.IdempotencyKey = "deploy-42-assets"
+
+	// The key is stored in the new bucket's tags, next to its other tags
+	mockClient := &mockS3Client{createBucketResponse: &s3.CreateBucketOutput{}}
+	helper.AssertNoError(NewWithClient(mockClient).CreateBucket(ctx, config))
+	if mockClient.putBucketTaggingInput == nil {
+		t.Fatal("expected the idempotency key to be tagged")
+	}
+	tagSet := mockClient.putBucketTaggingInput.Tagging.TagSet
+	helper.AssertEqual(2, len(tagSet))
+	helper.AssertEqual("Team", aws.ToString(tagSet[0].Key))
+	helper.AssertEqual("platform", aws.ToString(tagSet[0].Value))
+	helper.AssertEqual(cloudsdk.IdempotencyKeyTag, aws.ToString(tagSet[1].Key))
+	helper.AssertEqual("deploy-42-assets", aws.ToString(tagSet[1].Value))
+
+	// A bucket that couldn't be tagged can't be claimed by a retry, so the
+	// failure is returned
+	mockClient = &mockS3Client{
+		createBucketResponse:  &s3.CreateBucketOutput{},
+		putBucketTaggingError: &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"},
+	}
+	err := NewWithClient(mockClient).CreateBucket(ctx, config)
+	helper.AssertErrorCode(err, cloudsdk.ErrAuthorization)
+	helper.AssertContains(err.Error(), "was created but not tagged")
+
+	// Repeating the call finds the bucket by its tag
+	mockClient = &mockS3Client{
+		createBucketError: &types.BucketAlreadyOwnedByYou{},
+		getBucketTaggingResponse: &s3.GetBucketTaggingOutput{
+			TagSet: []types.Tag{{Key: aws.String(cloudsdk.IdempotencyKeyTag), Value: aws.String("deploy-42-assets")}},
+		},
+	}
+	helper.AssertNoError(NewWithClient(mockClient).CreateBucket(ctx, config))
+
+	// A bucket created with another key is still a conflict
+	config.IdempotencyKey = "deploy-43-assets"
+	err = NewWithClient(mockClient).CreateBucket(ctx, config)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+}
+
+func TestAWSStorage_LoggingRedactsReplication(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	var buf bytes.Buffer
+	// The JSON handler writes nested configs out in full, where the text
+	// handler would only print their pointers
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mockClient := &mockS3Client{createBucketResponse: &s3.CreateBucketOutput{}}
+	storage := NewWithClient(mockClient, WithLogger(logger))
+	config := cloudsdktesting.GenerateBucketConfig("test-bucket")
+
+	err := storage.CreateBucket(context.Background(), config)
+	helper.AssertNoError(err)
+
+	// Configurations logged by callers have the replication role and KMS
+	// key redacted
+	role := "arn:aws:iam::123456789012:role/replication"
+	kmsKeyID := "arn:aws:kms:us-west-2:123456789012:key/replica"
+	config.ReplicationConfig = &services.ReplicationConfiguration{
+		Role: role,
+		Rules: []services.ReplicationRule{{
+			ID:     "replicate-all",
+			Status: "Enabled",
+			Destination: services.ReplicationDestination{
+				Bucket:                  "arn:aws:s3:::test-bucket-replica",
+				EncryptionConfiguration: &services.ReplicationEncryptionConfiguration{ReplicaKmsKeyID: kmsKeyID},
+			},
+		}},
+	}
+	logger.Debug("created bucket", "config", config)
+
+	if strings.Contains(buf.String(), role) || strings.Contains(buf.String(), kmsKeyID) {
+		t.Fatalf("replication role or KMS key was logged: %s", buf.String())
+	}
+	helper.AssertContains(buf.String(), `"operation":"CreateBucket"`)
+	helper.AssertContains(buf.String(), "arn:aws:s3:::test-bucket-replica")
+	helper.AssertEqual(2, strings.Count(buf.String(), "[REDACTED]"))
+
+	// Logging the config must not mask the caller's copy
+	helper.AssertEqual(role, config.ReplicationConfig.Role)
+	helper.AssertEqual(kmsKeyID, config.ReplicationConfig.Rules[0].Destination.EncryptionConfiguration.ReplicaKmsKeyID)
 }
 
 func TestAWSStorage_CreateBucket_ErrorScenarios(t *testing.T) {
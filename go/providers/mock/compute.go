@@ -3,6 +3,8 @@ package mock
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
@@ -20,6 +22,10 @@ type MockCompute struct {
 //
 // Error injection:
 //   - Configure errors using WithError("CreateVM", error)
+//   - Invalid configurations return cloudsdk.ValidationErrors, as real providers do
+//   - Returns ErrQuotaExceeded once the WithQuota("CreateVM", n) limit is reached
+//   - Repeating a call with the same IdempotencyKey returns the first call's VM,
+//     or ErrResourceConflict if the configuration differs
 //   - Common test scenarios: authentication, authorization, resource conflicts
 //
 // Example:
@@ -42,26 +48,59 @@ func (m *MockCompute) CreateVM(ctx context.Context, config *services.VMConfig) (
 		return nil, err
 	}
 
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		m.provider.recordOperation("CreateVM", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if prior, found, err := m.provider.checkIdempotency("CreateVM", "compute", config.IdempotencyKey, config); found {
+		vm, _ := prior.(*services.VM)
+		m.provider.recordOperation("CreateVM", []interface{}{config}, vm, err)
+		return vm, err
+	}
+
+	if err := m.provider.checkQuota("CreateVM", "compute", len(m.provider.vmState)); err != nil {
+		m.provider.recordOperation("CreateVM", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
 	// Check if we have a configured response for this VM name
 	if vm, exists := m.provider.vmResponses[config.Name]; exists {
 		// Store in state for later retrieval
 		m.provider.vmState[vm.ID] = vm
+		m.provider.recordIdempotency("CreateVM", config.IdempotencyKey, config, vm)
 		m.provider.recordOperation("CreateVM", []interface{}{config}, vm, nil)
 		return vm, nil
 	}
 
 	// Generate a realistic mock VM
 	vm := &services.VM{
-		ID:         generateVMID(),
-		Name:       config.Name,
-		State:      "running",
-		PublicIP:   "203.0.113." + fmt.Sprintf("%d", time.Now().Unix()%254+1),
-		PrivateIP:  "10.0.1." + fmt.Sprintf("%d", time.Now().Unix()%254+1),
-		LaunchTime: time.Now().Format(time.RFC3339),
+		ID:                 generateVMID(),
+		Name:               config.Name,
+		State:              "running",
+		PublicIP:           "203.0.113." + fmt.Sprintf("%d", time.Now().Unix()%254+1),
+		PrivateIP:          "10.0.1." + fmt.Sprintf("%d", time.Now().Unix()%254+1),
+		LaunchTime:         time.Now().Format(time.RFC3339),
+		InstanceType:       config.InstanceType,
+		SubnetID:           config.SubnetID,
+		Tags:               make(map[string]string, len(config.Tags)),
+		KeyName:            config.KeyName,
+		SecurityGroups:     append([]string(nil), config.SecurityGroups...),
+		PlacementGroup:     config.PlacementGroup,
+		IamInstanceProfile: config.IamInstanceProfile,
+		Monitoring:         config.Monitoring != nil && *config.Monitoring,
+		EbsOptimized:       config.EbsOptimized != nil && *config.EbsOptimized,
+	}
+	for key, value := range config.Tags {
+		vm.Tags[key] = value
+	}
+	if config.AssignPublicIP != nil && !*config.AssignPublicIP {
+		vm.PublicIP = ""
 	}
 
 	// Store in state
 	m.provider.vmState[vm.ID] = vm
+	m.provider.recordIdempotency("CreateVM", config.IdempotencyKey, config, vm)
 
 	m.provider.recordOperation("CreateVM", []interface{}{config}, vm, nil)
 	return vm, nil
@@ -130,6 +169,96 @@ func (m *MockCompute) ListVMs(ctx context.Context) ([]*services.VM, error) {
 	return vms, nil
 }
 
+// defaultVMPageSize is the page size of ListVMsWithOptions when none is given.
+const defaultVMPageSize = 1000
+
+// ListVMsWithOptions returns one page of the mock virtual machines that match
+// opts, ordered by ID. The cursor is the ID of the last VM of the previous
+// page, so VMs created or deleted between pages don't shift later pages.
+//
+// Error injection:
+//   - Configure errors using WithError("ListVMsWithOptions", error)
+//   - Invalid options return cloudsdk.ValidationErrors, as real providers do
+//
+// Example:
+//
+//	page, err := mockCompute.ListVMsWithOptions(ctx, &services.ListVMsOptions{
+//	    States:   []string{"running"},
+//	    PageSize: 10,
+//	})
+func (m *MockCompute) ListVMsWithOptions(ctx context.Context, opts *services.ListVMsOptions) (*services.VMPage, error) {
+	m.provider.applyDelay("ListVMsWithOptions")
+
+	if err := m.provider.checkError("ListVMsWithOptions"); err != nil {
+		m.provider.recordOperation("ListVMsWithOptions", []interface{}{opts}, nil, err)
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &services.ListVMsOptions{}
+	}
+	if err := cloudsdk.ValidateConfig("mock", "compute", opts); err != nil {
+		m.provider.recordOperation("ListVMsWithOptions", []interface{}{opts}, nil, err)
+		return nil, err
+	}
+
+	vms := make([]*services.VM, 0, len(m.provider.vmState))
+	for _, vm := range m.provider.vmState {
+		if vm.ID > opts.Cursor && matchesVMOptions(vm, opts) {
+			vms = append(vms, vm)
+		}
+	}
+	sort.Slice(vms, func(i, j int) bool { return vms[i].ID < vms[j].ID })
+
+	pageSize := int(opts.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultVMPageSize
+	}
+	page := &services.VMPage{VMs: vms}
+	if len(vms) > pageSize {
+		page.VMs = vms[:pageSize]
+		page.NextCursor = page.VMs[pageSize-1].ID
+	}
+
+	m.provider.recordOperation("ListVMsWithOptions", []interface{}{opts}, page, nil)
+	return page, nil
+}
+
+// matchesVMOptions reports whether vm passes every filter in opts.
+func matchesVMOptions(vm *services.VM, opts *services.ListVMsOptions) bool {
+	if len(opts.States) > 0 {
+		if !containsString(opts.States, vm.State) {
+			return false
+		}
+	} else if vm.State == "terminated" {
+		return false
+	}
+	if len(opts.InstanceTypes) > 0 && !containsString(opts.InstanceTypes, vm.InstanceType) {
+		return false
+	}
+	if len(opts.SubnetIDs) > 0 && !containsString(opts.SubnetIDs, vm.SubnetID) {
+		return false
+	}
+	if !strings.HasPrefix(vm.Name, opts.NamePrefix) {
+		return false
+	}
+	for key, value := range opts.Tags {
+		if tag, ok := vm.Tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteVM removes a mock virtual machine from the state.
 // Returns an error if the VM doesn't exist.
 //
@@ -158,6 +287,13 @@ func (m *MockCompute) DeleteVM(ctx context.Context, id string) error {
 		return err
 	}
 
+	// Detach its volumes, as terminating an instance does
+	for _, volume := range m.provider.volumeState {
+		if len(volume.Attachments) > 0 && volume.Attachments[0].VMID == id {
+			m.provider.detachVolume(volume)
+		}
+	}
+
 	// Remove from state
 	delete(m.provider.vmState, id)
 
@@ -171,7 +307,7 @@ func (m *MockCompute) DeleteVM(ctx context.Context, id string) error {
 // Error injection:
 //   - Configure errors using WithError("StartVM", error)
 //   - Automatically returns ErrResourceNotFound for non-existent VMs
-//   - Returns ErrInvalidConfig if VM is already running
+//   - Returns ErrInvalidState if VM is already running
 //
 // Example:
 //
@@ -197,7 +333,7 @@ func (m *MockCompute) StartVM(ctx context.Context, id string) error {
 
 	// Check if already running
 	if vm.State == "running" {
-		err := cloudsdk.NewInvalidConfigError("mock", "compute", "state", "VM is already running")
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "StartVM", id, vm.State)
 		m.provider.recordOperation("StartVM", []interface{}{id}, nil, err)
 		return err
 	}
@@ -215,7 +351,7 @@ func (m *MockCompute) StartVM(ctx context.Context, id string) error {
 // Error injection:
 //   - Configure errors using WithError("StopVM", error)
 //   - Automatically returns ErrResourceNotFound for non-existent VMs
-//   - Returns ErrInvalidConfig if VM is already stopped
+//   - Returns ErrInvalidState if VM is already stopped
 //
 // Example:
 //
@@ -241,7 +377,7 @@ func (m *MockCompute) StopVM(ctx context.Context, id string) error {
 
 	// Check if already stopped
 	if vm.State == "stopped" {
-		err := cloudsdk.NewInvalidConfigError("mock", "compute", "state", "VM is already stopped")
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "StopVM", id, vm.State)
 		m.provider.recordOperation("StopVM", []interface{}{id}, nil, err)
 		return err
 	}
@@ -253,6 +389,128 @@ func (m *MockCompute) StopVM(ctx context.Context, id string) error {
 	return nil
 }
 
+// RebootVM reboots a mock virtual machine. The VM stays running.
+// Returns an error if the VM doesn't exist or isn't running.
+//
+// Error injection:
+//   - Configure errors using WithError("RebootVM", error)
+//   - Automatically returns ErrResourceNotFound for non-existent VMs
+//   - Returns ErrInvalidState if VM is not running
+func (m *MockCompute) RebootVM(ctx context.Context, id string) error {
+	m.provider.applyDelay("RebootVM")
+
+	if err := m.provider.checkError("RebootVM"); err != nil {
+		m.provider.recordOperation("RebootVM", []interface{}{id}, nil, err)
+		return err
+	}
+
+	vm, exists := m.provider.vmState[id]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "VM", id)
+		m.provider.recordOperation("RebootVM", []interface{}{id}, nil, err)
+		return err
+	}
+
+	if vm.State != "running" {
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "RebootVM", id, vm.State)
+		m.provider.recordOperation("RebootVM", []interface{}{id}, nil, err)
+		return err
+	}
+
+	m.provider.recordOperation("RebootVM", []interface{}{id}, nil, nil)
+	return nil
+}
+
+// ResizeVM changes the instance type of a mock virtual machine, going
+// through the same stop, modify and start steps as a real provider. State
+// changes take effect immediately.
+//
+// Error injection:
+//   - Configure errors using WithError("ResizeVM", error)
+//   - Fail a single step with WithError("ResizeVM/"+step, error), where step
+//     is services.ResizeStepStop, ResizeStepModify or ResizeStepStart. The VM
+//     is then rolled back and a *services.ResizeError returned
+//   - Fail the rollback too with WithError("ResizeVM/rollback", error)
+//   - Automatically returns ErrResourceNotFound for non-existent VMs
+//   - Returns ErrInvalidState if VM is neither running nor stopped
+//
+// Example:
+//
+//	// Simulate a lack of capacity for the new instance type
+//	provider := mock.New("us-east-1").
+//	    WithError("ResizeVM/"+services.ResizeStepStart, cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, ...))
+func (m *MockCompute) ResizeVM(ctx context.Context, id string, instanceType string) error {
+	args := []interface{}{id, instanceType}
+	m.provider.applyDelay("ResizeVM")
+
+	if err := m.provider.checkError("ResizeVM"); err != nil {
+		m.provider.recordOperation("ResizeVM", args, nil, err)
+		return err
+	}
+
+	if instanceType == "" {
+		err := cloudsdk.NewInvalidConfigError("mock", "compute", "InstanceType", "instance type is required")
+		m.provider.recordOperation("ResizeVM", args, nil, err)
+		return err
+	}
+
+	vm, exists := m.provider.vmState[id]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "VM", id)
+		m.provider.recordOperation("ResizeVM", args, nil, err)
+		return err
+	}
+
+	if vm.InstanceType == instanceType {
+		m.provider.recordOperation("ResizeVM", args, nil, nil)
+		return nil
+	}
+
+	if vm.State != "running" && vm.State != "stopped" {
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "ResizeVM", id, vm.State)
+		m.provider.recordOperation("ResizeVM", args, nil, err)
+		return err
+	}
+
+	from, wasRunning := vm.InstanceType, vm.State == "running"
+	fail := func(step string, err error) error {
+		resizeErr := &services.ResizeError{VMID: id, From: from, To: instanceType, Step: step, Err: err}
+		if rollbackErr := m.provider.checkError("ResizeVM/rollback"); rollbackErr != nil {
+			resizeErr.RollbackErr = rollbackErr
+		} else {
+			vm.InstanceType = from
+			if wasRunning {
+				vm.State = "running"
+			}
+			resizeErr.RolledBack = true
+		}
+		m.provider.recordOperation("ResizeVM", args, nil, resizeErr)
+		return resizeErr
+	}
+
+	if wasRunning {
+		if err := m.provider.checkError("ResizeVM/" + services.ResizeStepStop); err != nil {
+			return fail(services.ResizeStepStop, err)
+		}
+		vm.State = "stopped"
+	}
+
+	if err := m.provider.checkError("ResizeVM/" + services.ResizeStepModify); err != nil {
+		return fail(services.ResizeStepModify, err)
+	}
+	vm.InstanceType = instanceType
+
+	if wasRunning {
+		if err := m.provider.checkError("ResizeVM/" + services.ResizeStepStart); err != nil {
+			return fail(services.ResizeStepStart, err)
+		}
+		vm.State = "running"
+	}
+
+	m.provider.recordOperation("ResizeVM", args, nil, nil)
+	return nil
+}
+
 // InstanceTypes returns the mock instance types service
 func (m *MockCompute) InstanceTypes() services.InstanceTypesService {
 	return &MockInstanceTypesService{provider: m.provider}
@@ -398,7 +656,8 @@ type MockSpotInstancesService struct {
 	provider *MockProvider
 }
 
-// Request requests a mock spot instance
+// Request requests a mock spot instance. Repeating a request with the same
+// IdempotencyKey returns the first request.
 func (s *MockSpotInstancesService) Request(ctx context.Context, config *services.SpotInstanceConfig) (*services.SpotInstanceRequest, error) {
 	s.provider.applyDelay("RequestSpotInstances")
 	if err := s.provider.checkError("RequestSpotInstances"); err != nil {
@@ -406,6 +665,23 @@ func (s *MockSpotInstancesService) Request(ctx context.Context, config *services
 		return nil, err
 	}
 
+	if config == nil {
+		err := cloudsdk.NewInvalidConfigError("mock", "compute", "config", "spot instance configuration cannot be nil")
+		s.provider.recordOperation("RequestSpotInstances", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("RequestSpotInstances", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if prior, found, err := s.provider.checkIdempotency("RequestSpotInstances", "compute", config.IdempotencyKey, config); found {
+		request, _ := prior.(*services.SpotInstanceRequest)
+		s.provider.recordOperation("RequestSpotInstances", []interface{}{config}, request, err)
+		return request, err
+	}
+
 	request := &services.SpotInstanceRequest{
 		SpotInstanceRequestId: fmt.Sprintf("sir-%016x", time.Now().UnixNano()),
 		State:                 "active",
@@ -415,11 +691,14 @@ func (s *MockSpotInstancesService) Request(ctx context.Context, config *services
 		InstanceId:            generateVMID(),
 	}
 
+	s.provider.spotState[request.SpotInstanceRequestId] = request
+	s.provider.recordIdempotency("RequestSpotInstances", config.IdempotencyKey, config, request)
 	s.provider.recordOperation("RequestSpotInstances", []interface{}{config}, request, nil)
 	return request, nil
 }
 
-// Describe describes mock spot instance requests
+// Describe describes the mock spot instance requests with the given IDs, or
+// all requests if requestIds is empty. Unknown IDs are skipped.
 func (s *MockSpotInstancesService) Describe(ctx context.Context, requestIds []string) ([]*services.SpotInstanceRequest, error) {
 	s.provider.applyDelay("DescribeSpotInstanceRequests")
 	if err := s.provider.checkError("DescribeSpotInstanceRequests"); err != nil {
@@ -427,15 +706,16 @@ func (s *MockSpotInstancesService) Describe(ctx context.Context, requestIds []st
 		return nil, err
 	}
 
-	requests := []*services.SpotInstanceRequest{
-		{
-			SpotInstanceRequestId: "sir-1234567890abcdef0",
-			State:                 "active",
-			Status:                "fulfilled",
-			SpotPrice:             "0.05",
-			CreateTime:            time.Now().Add(-1 * time.Hour).String(),
-			InstanceId:            "i-1234567890abcdef0",
-		},
+	requests := []*services.SpotInstanceRequest{}
+	if len(requestIds) == 0 {
+		for _, request := range s.provider.spotState {
+			requests = append(requests, request)
+		}
+	}
+	for _, id := range requestIds {
+		if request, exists := s.provider.spotState[id]; exists {
+			requests = append(requests, request)
+		}
 	}
 
 	s.provider.recordOperation("DescribeSpotInstanceRequests", []interface{}{requestIds}, requests, nil)
@@ -450,6 +730,11 @@ func (s *MockSpotInstancesService) Cancel(ctx context.Context, requestId string)
 		return err
 	}
 
+	if request, exists := s.provider.spotState[requestId]; exists {
+		request.State = "cancelled"
+		request.Status = "request-canceled-and-instance-running"
+	}
+
 	s.provider.recordOperation("CancelSpotInstanceRequests", []interface{}{requestId}, nil, nil)
 	return nil
 }
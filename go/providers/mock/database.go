@@ -20,6 +20,10 @@ type MockDatabase struct {
 //
 // Error injection:
 //   - Configure errors using WithError("CreateDB", error)
+//   - Invalid configurations return cloudsdk.ValidationErrors, as real providers do
+//   - Returns ErrQuotaExceeded once the WithQuota("CreateDB", n) limit is reached
+//   - Repeating a call with the same IdempotencyKey returns the first call's
+//     database, or ErrResourceConflict if the configuration differs
 //   - Common test scenarios: authentication, authorization, resource conflicts
 //
 // Example:
@@ -42,10 +46,27 @@ func (m *MockDatabase) CreateDB(ctx context.Context, config *services.DBConfig)
 		return nil, err
 	}
 
+	if err := cloudsdk.ValidateConfig("mock", "database", config); err != nil {
+		m.provider.recordOperation("CreateDB", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if prior, found, err := m.provider.checkIdempotency("CreateDB", "database", config.IdempotencyKey, config); found {
+		db, _ := prior.(*services.DBInstance)
+		m.provider.recordOperation("CreateDB", []interface{}{config}, db, err)
+		return db, err
+	}
+
+	if err := m.provider.checkQuota("CreateDB", "database", len(m.provider.dbState)); err != nil {
+		m.provider.recordOperation("CreateDB", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
 	// Check if we have a configured response for this database name
 	if db, exists := m.provider.dbResponses[config.Name]; exists {
 		// Store in state for later retrieval
 		m.provider.dbState[db.ID] = db
+		m.provider.recordIdempotency("CreateDB", config.IdempotencyKey, config, db)
 		m.provider.recordOperation("CreateDB", []interface{}{config}, db, nil)
 		return db, nil
 	}
@@ -72,12 +93,16 @@ func (m *MockDatabase) CreateDB(ctx context.Context, config *services.DBConfig)
 		Name:       config.Name,
 		Engine:     fmt.Sprintf("%s-%s", config.Engine, getDefaultEngineVersion(config.Engine)),
 		Status:     "available",
-		Endpoint:   fmt.Sprintf("%s:%d", generateEndpoint(config.Name, m.provider.region), getDefaultPort(config.Engine)),
+		Endpoint:   fmt.Sprintf("%s:%d", generateEndpoint(config.Name, m.provider.regionFor(ctx)), getDefaultPort(config.Engine)),
 		LaunchTime: time.Now().Format(time.RFC3339),
 	}
 
 	// Store in state
 	m.provider.dbState[db.ID] = db
+	if config.DeletionProtection != nil && *config.DeletionProtection {
+		m.provider.protectedDB[db.ID] = true
+	}
+	m.provider.recordIdempotency("CreateDB", config.IdempotencyKey, config, db)
 
 	m.provider.recordOperation("CreateDB", []interface{}{config}, db, nil)
 	return db, nil
@@ -175,6 +200,13 @@ func (m *MockDatabase) DeleteDB(ctx context.Context, id string) error {
 		return err
 	}
 
+	if m.provider.protectedDB[id] {
+		err := cloudsdk.NewPreconditionFailedError("mock", "database", "DeleteDB",
+			fmt.Sprintf("database instance '%s' has deletion protection enabled", id))
+		m.provider.recordOperation("DeleteDB", []interface{}{id}, nil, err)
+		return err
+	}
+
 	// Remove from state
 	delete(m.provider.dbState, id)
 
@@ -0,0 +1,353 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// mockAccountID is the account that owns the resources the mock creates.
+// Owner "self" in an ImageFilter means this account.
+const mockAccountID = "123456789012"
+
+// defaultImages returns the public images every mock provider starts with:
+// two Amazon Linux releases for x86_64, one for arm64 and an Ubuntu image.
+func defaultImages() map[string]*services.Image {
+	images := []*services.Image{
+		{
+			ID:           "ami-0a1b2c3d4e5f60001",
+			Name:         "al2023-ami-2023.5.20240701.0-kernel-6.1-x86_64",
+			OwnerID:      "137112412989",
+			OwnerAlias:   "amazon",
+			Architecture: "x86_64",
+			CreationDate: "2024-07-01T18:25:33Z",
+		},
+		{
+			ID:           "ami-0a1b2c3d4e5f60002",
+			Name:         "al2023-ami-2023.6.20241010.0-kernel-6.1-x86_64",
+			OwnerID:      "137112412989",
+			OwnerAlias:   "amazon",
+			Architecture: "x86_64",
+			CreationDate: "2024-10-10T20:14:05Z",
+		},
+		{
+			ID:           "ami-0a1b2c3d4e5f60003",
+			Name:         "al2023-ami-2023.6.20241010.0-kernel-6.1-arm64",
+			OwnerID:      "137112412989",
+			OwnerAlias:   "amazon",
+			Architecture: "arm64",
+			CreationDate: "2024-10-10T20:14:07Z",
+		},
+		{
+			ID:           "ami-0a1b2c3d4e5f60004",
+			Name:         "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20241002",
+			OwnerID:      "099720109477",
+			Architecture: "x86_64",
+			CreationDate: "2024-10-02T09:41:12Z",
+		},
+	}
+
+	state := make(map[string]*services.Image, len(images))
+	for _, image := range images {
+		image.State = "available"
+		image.Public = true
+		image.RootDeviceType = "ebs"
+		state[image.ID] = image
+	}
+	return state
+}
+
+// WithImage adds an image to the mock provider, on top of the default public
+// Amazon Linux and Ubuntu images. Unlike images made with Create, it is kept
+// by Reset. An image without an OwnerID is owned by the mock account, so an
+// ImageFilter needs Owners "self" or the default owner to find it.
+//
+// Example:
+//
+//	provider := mock.New("us-east-1").WithImage(&services.Image{
+//	    ID:           "ami-0123456789abcdef0",
+//	    Name:         "golden-web-2024-10-01",
+//	    Architecture: "x86_64",
+//	    State:        "available",
+//	    CreationDate: "2024-10-01T00:00:00Z",
+//	})
+func (m *MockProvider) WithImage(image *services.Image) *MockProvider {
+	if image.OwnerID == "" {
+		image.OwnerID = mockAccountID
+	}
+	m.imageResponses = append(m.imageResponses, image)
+	m.imageState[image.ID] = image
+	return m
+}
+
+// MockImagesService implements the services.ImagesService interface for
+// testing. Images are kept in the provider's state, and images made with
+// Create are "available" at once.
+type MockImagesService struct {
+	provider *MockProvider
+}
+
+// Images returns the mock images service
+func (m *MockCompute) Images() services.ImagesService {
+	return &MockImagesService{provider: m.provider}
+}
+
+// List returns the mock images that match filter, newest first.
+//
+// Error injection:
+//   - Configure errors using WithError("ListImages", error)
+func (s *MockImagesService) List(ctx context.Context, filter *services.ImageFilter) ([]*services.Image, error) {
+	s.provider.applyDelay("ListImages")
+
+	if err := s.provider.checkError("ListImages"); err != nil {
+		s.provider.recordOperation("ListImages", []interface{}{filter}, nil, err)
+		return nil, err
+	}
+
+	images := s.provider.listImages(filter)
+
+	s.provider.recordOperation("ListImages", []interface{}{filter}, images, nil)
+	return images, nil
+}
+
+// listImages returns the images that match filter, newest first
+func (m *MockProvider) listImages(filter *services.ImageFilter) []*services.Image {
+	if filter == nil {
+		filter = &services.ImageFilter{}
+	}
+	images := []*services.Image{}
+	for _, image := range m.imageState {
+		if matchesImageFilter(image, filter) {
+			images = append(images, image)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].CreationDate != images[j].CreationDate {
+			return images[i].CreationDate > images[j].CreationDate
+		}
+		return images[i].ID < images[j].ID
+	})
+	return images
+}
+
+// matchesImageFilter reports whether image matches every filter that is set
+func matchesImageFilter(image *services.Image, filter *services.ImageFilter) bool {
+	if len(filter.ImageIDs) > 0 && !containsString(filter.ImageIDs, image.ID) {
+		return false
+	}
+	owners := filter.Owners
+	if len(owners) == 0 && len(filter.ImageIDs) == 0 {
+		owners = []string{"self"}
+	}
+	if len(owners) > 0 && !containsString(owners, image.OwnerID) && !containsString(owners, image.OwnerAlias) &&
+		!(containsString(owners, "self") && image.OwnerID == mockAccountID) {
+		return false
+	}
+	if filter.NamePattern != "" && !matchGlob(filter.NamePattern, image.Name) {
+		return false
+	}
+	if filter.Architecture != "" && image.Architecture != filter.Architecture {
+		return false
+	}
+	if len(filter.States) > 0 && !containsString(filter.States, image.State) {
+		return false
+	}
+	if !filter.CreatedAfter.IsZero() || !filter.CreatedBefore.IsZero() {
+		created, err := time.Parse(time.RFC3339, image.CreationDate)
+		if err != nil {
+			return false
+		}
+		if !filter.CreatedAfter.IsZero() && created.Before(filter.CreatedAfter) {
+			return false
+		}
+		if !filter.CreatedBefore.IsZero() && created.After(filter.CreatedBefore) {
+			return false
+		}
+	}
+	for key, value := range filter.Tags {
+		if tag, ok := image.Tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+	return true
+}
+
+// matchGlob reports whether name matches pattern, where "*" matches any run
+// of characters and "?" any single character, as in EC2 filters
+func matchGlob(pattern, name string) bool {
+	p, n := 0, 0
+	star, match := -1, 0
+	for n < len(name) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == name[n]):
+			p++
+			n++
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, n
+			p++
+		case star >= 0:
+			// Let the last star match one more character
+			match++
+			p, n = star+1, match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// Get retrieves a mock image by ID.
+//
+// Error injection:
+//   - Configure errors using WithError("GetImage", error)
+//   - Automatically returns ErrResourceNotFound for non-existent images
+func (s *MockImagesService) Get(ctx context.Context, id string) (*services.Image, error) {
+	s.provider.applyDelay("GetImage")
+
+	if err := s.provider.checkError("GetImage"); err != nil {
+		s.provider.recordOperation("GetImage", []interface{}{id}, nil, err)
+		return nil, err
+	}
+
+	image, exists := s.provider.imageState[id]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Image", id)
+		s.provider.recordOperation("GetImage", []interface{}{id}, nil, err)
+		return nil, err
+	}
+
+	s.provider.recordOperation("GetImage", []interface{}{id}, image, nil)
+	return image, nil
+}
+
+// Latest returns the newest available mock image that matches filter.
+//
+// Error injection:
+//   - Configure errors using WithError("LatestImage", error)
+//   - Automatically returns ErrResourceNotFound if no available image matches
+func (s *MockImagesService) Latest(ctx context.Context, filter *services.ImageFilter) (*services.Image, error) {
+	s.provider.applyDelay("LatestImage")
+
+	if err := s.provider.checkError("LatestImage"); err != nil {
+		s.provider.recordOperation("LatestImage", []interface{}{filter}, nil, err)
+		return nil, err
+	}
+
+	available := services.ImageFilter{}
+	if filter != nil {
+		available = *filter
+	}
+	available.States = []string{"available"}
+
+	images := s.provider.listImages(&available)
+	if len(images) == 0 {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Image", available.NamePattern)
+		s.provider.recordOperation("LatestImage", []interface{}{filter}, nil, err)
+		return nil, err
+	}
+
+	s.provider.recordOperation("LatestImage", []interface{}{filter}, images[0], nil)
+	return images[0], nil
+}
+
+// Create creates an available mock image from a mock VM.
+//
+// Error injection:
+//   - Configure errors using WithError("CreateImage", error)
+//   - Invalid configurations return cloudsdk.ValidationErrors
+//   - Automatically returns ErrResourceNotFound for non-existent VMs
+//   - Returns ErrResourceConflict if the account already has an image with the name
+func (s *MockImagesService) Create(ctx context.Context, config *services.CreateImageConfig) (*services.Image, error) {
+	s.provider.applyDelay("CreateImage")
+
+	if err := s.provider.checkError("CreateImage"); err != nil {
+		s.provider.recordOperation("CreateImage", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("CreateImage", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if _, exists := s.provider.vmState[config.VMID]; !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "VM", config.VMID)
+		s.provider.recordOperation("CreateImage", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	for _, image := range s.provider.imageState {
+		if image.OwnerID == mockAccountID && image.Name == config.Name {
+			err := cloudsdk.NewCloudError(
+				cloudsdk.ErrResourceConflict,
+				fmt.Sprintf("Image name '%s' is already in use by %s", config.Name, image.ID),
+				"mock", "compute", "CreateImage",
+			).WithSuggestions(
+				"Choose a different image name, e.g. with a date suffix",
+				"Deregister the existing image first",
+			)
+			s.provider.recordOperation("CreateImage", []interface{}{config}, nil, err)
+			return nil, err
+		}
+	}
+
+	image := &services.Image{
+		ID:             fmt.Sprintf("ami-%017x", time.Now().UnixNano()),
+		Name:           config.Name,
+		Description:    config.Description,
+		OwnerID:        mockAccountID,
+		Architecture:   "x86_64",
+		State:          "available",
+		CreationDate:   time.Now().UTC().Format(time.RFC3339),
+		RootDeviceType: "ebs",
+		Tags:           make(map[string]string, len(config.Tags)),
+	}
+	for key, value := range config.Tags {
+		image.Tags[key] = value
+	}
+
+	s.provider.imageState[image.ID] = image
+	s.provider.recordOperation("CreateImage", []interface{}{config}, image, nil)
+	return image, nil
+}
+
+// Deregister removes a mock image owned by the mock account.
+//
+// Error injection:
+//   - Configure errors using WithError("DeregisterImage", error)
+//   - Automatically returns ErrResourceNotFound for non-existent images
+//   - Returns ErrAuthorization for images owned by other accounts
+func (s *MockImagesService) Deregister(ctx context.Context, id string) error {
+	s.provider.applyDelay("DeregisterImage")
+
+	if err := s.provider.checkError("DeregisterImage"); err != nil {
+		s.provider.recordOperation("DeregisterImage", []interface{}{id}, nil, err)
+		return err
+	}
+
+	image, exists := s.provider.imageState[id]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Image", id)
+		s.provider.recordOperation("DeregisterImage", []interface{}{id}, nil, err)
+		return err
+	}
+
+	if image.OwnerID != mockAccountID {
+		err := cloudsdk.NewAuthorizationError("mock", "compute", "DeregisterImage",
+			fmt.Errorf("image %s is owned by account %s", id, image.OwnerID))
+		s.provider.recordOperation("DeregisterImage", []interface{}{id}, nil, err)
+		return err
+	}
+
+	delete(s.provider.imageState, id)
+
+	s.provider.recordOperation("DeregisterImage", []interface{}{id}, nil, nil)
+	return nil
+}
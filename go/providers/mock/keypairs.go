@@ -0,0 +1,330 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// MockKeyPairsService implements the services.KeyPairsService interface for
+// testing. Create generates real keys, so the private key it returns can be
+// used with an SSH client or library.
+type MockKeyPairsService struct {
+	provider *MockProvider
+}
+
+// KeyPairs returns the mock key pairs service
+func (m *MockCompute) KeyPairs() services.KeyPairsService {
+	return &MockKeyPairsService{provider: m.provider}
+}
+
+// Create generates a mock key pair. ed25519 private keys are returned in
+// OpenSSH format and RSA private keys in PKCS #1 PEM format, as on AWS.
+//
+// Error injection:
+//   - Configure errors using WithError("CreateKeyPair", error)
+//   - Configure quotas using WithQuota("CreateKeyPair", limit)
+//   - Invalid configurations return cloudsdk.ValidationErrors
+//   - Returns ErrResourceConflict if a key pair with the name exists
+func (s *MockKeyPairsService) Create(ctx context.Context, config *services.KeyPairConfig) (*services.KeyPair, error) {
+	s.provider.applyDelay("CreateKeyPair")
+
+	if err := s.provider.checkError("CreateKeyPair"); err != nil {
+		s.provider.recordOperation("CreateKeyPair", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("CreateKeyPair", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := s.provider.checkKeyPairAvailable("CreateKeyPair", config.Name); err != nil {
+		s.provider.recordOperation("CreateKeyPair", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	keyType := config.KeyType
+	if keyType == "" {
+		keyType = "ed25519"
+	}
+
+	var publicKey, privateKey []byte
+	var err error
+	if keyType == "rsa" {
+		publicKey, privateKey, err = generateRSAKey()
+	} else {
+		publicKey, privateKey, err = generateEd25519Key(config.Name)
+	}
+	if err != nil {
+		cloudErr := cloudsdk.NewCloudError(cloudsdk.ErrProviderError, "failed to generate key pair", "mock", "compute", "CreateKeyPair").
+			WithCause(err)
+		s.provider.recordOperation("CreateKeyPair", []interface{}{config}, nil, cloudErr)
+		return nil, cloudErr
+	}
+
+	keyPair := s.provider.storeKeyPair(config.Name, keyType, publicKey, config.Tags)
+	created := *keyPair
+	created.PrivateKey = string(privateKey)
+
+	s.provider.recordOperation("CreateKeyPair", []interface{}{config}, &created, nil)
+	return &created, nil
+}
+
+// Import registers a public key in OpenSSH authorized_keys format as a mock
+// key pair.
+//
+// Error injection:
+//   - Configure errors using WithError("ImportKeyPair", error)
+//   - Configure quotas using WithQuota("ImportKeyPair", limit)
+//   - Invalid configurations return cloudsdk.ValidationErrors
+//   - Returns ErrInvalidConfig if the public key isn't an ed25519 or RSA key
+//   - Returns ErrResourceConflict if a key pair with the name exists
+func (s *MockKeyPairsService) Import(ctx context.Context, config *services.ImportKeyPairConfig) (*services.KeyPair, error) {
+	s.provider.applyDelay("ImportKeyPair")
+
+	if err := s.provider.checkError("ImportKeyPair"); err != nil {
+		s.provider.recordOperation("ImportKeyPair", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("ImportKeyPair", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	keyType, publicKey, err := parseAuthorizedKey(config.PublicKey)
+	if err != nil {
+		cloudErr := cloudsdk.NewInvalidConfigError("mock", "compute", "PublicKey", err.Error()).
+			WithSuggestions(
+				"Pass the contents of a .pub file, e.g. \"ssh-ed25519 AAAA... user@host\"",
+				"Only ed25519 and RSA keys are supported",
+			)
+		s.provider.recordOperation("ImportKeyPair", []interface{}{config}, nil, cloudErr)
+		return nil, cloudErr
+	}
+
+	if err := s.provider.checkKeyPairAvailable("ImportKeyPair", config.Name); err != nil {
+		s.provider.recordOperation("ImportKeyPair", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	keyPair := s.provider.storeKeyPair(config.Name, keyType, publicKey, config.Tags)
+
+	s.provider.recordOperation("ImportKeyPair", []interface{}{config}, keyPair, nil)
+	return keyPair, nil
+}
+
+// List returns every mock key pair, sorted by name.
+//
+// Error injection:
+//   - Configure errors using WithError("ListKeyPairs", error)
+func (s *MockKeyPairsService) List(ctx context.Context) ([]*services.KeyPair, error) {
+	s.provider.applyDelay("ListKeyPairs")
+
+	if err := s.provider.checkError("ListKeyPairs"); err != nil {
+		s.provider.recordOperation("ListKeyPairs", []interface{}{}, nil, err)
+		return nil, err
+	}
+
+	keyPairs := make([]*services.KeyPair, 0, len(s.provider.keyPairState))
+	for _, keyPair := range s.provider.keyPairState {
+		keyPairs = append(keyPairs, keyPair)
+	}
+	sort.Slice(keyPairs, func(i, j int) bool { return keyPairs[i].Name < keyPairs[j].Name })
+
+	s.provider.recordOperation("ListKeyPairs", []interface{}{}, keyPairs, nil)
+	return keyPairs, nil
+}
+
+// Delete removes a mock key pair by name. Like EC2, deleting a key pair that
+// doesn't exist succeeds.
+//
+// Error injection:
+//   - Configure errors using WithError("DeleteKeyPair", error)
+func (s *MockKeyPairsService) Delete(ctx context.Context, name string) error {
+	s.provider.applyDelay("DeleteKeyPair")
+
+	if err := s.provider.checkError("DeleteKeyPair"); err != nil {
+		s.provider.recordOperation("DeleteKeyPair", []interface{}{name}, nil, err)
+		return err
+	}
+
+	delete(s.provider.keyPairState, name)
+
+	s.provider.recordOperation("DeleteKeyPair", []interface{}{name}, nil, nil)
+	return nil
+}
+
+// checkKeyPairAvailable returns ErrResourceConflict if a key pair with name
+// exists, or ErrQuotaExceeded if the quota for operation is used up
+func (m *MockProvider) checkKeyPairAvailable(operation, name string) error {
+	if _, exists := m.keyPairState[name]; exists {
+		return cloudsdk.NewCloudError(
+			cloudsdk.ErrResourceConflict,
+			fmt.Sprintf("Key pair '%s' already exists", name),
+			"mock", "compute", operation,
+		).WithSuggestions(
+			"Choose a different key pair name",
+			"Delete the existing key pair first",
+		)
+	}
+	return m.checkQuota(operation, "compute", len(m.keyPairState))
+}
+
+// storeKeyPair adds a key pair for the SSH wire-format publicKey to the
+// provider's state and returns it
+func (m *MockProvider) storeKeyPair(name, keyType string, publicKey []byte, tags map[string]string) *services.KeyPair {
+	digest := sha256.Sum256(publicKey)
+	keyPair := &services.KeyPair{
+		ID:          fmt.Sprintf("key-%017x", time.Now().UnixNano()),
+		Name:        name,
+		KeyType:     keyType,
+		Fingerprint: "SHA256:" + base64.RawStdEncoding.EncodeToString(digest[:]),
+		PublicKey:   sshKeyAlgorithm(keyType) + " " + base64.StdEncoding.EncodeToString(publicKey) + " " + name,
+		CreateTime:  time.Now().UTC().Format(time.RFC3339),
+		Tags:        make(map[string]string, len(tags)),
+	}
+	for key, value := range tags {
+		keyPair.Tags[key] = value
+	}
+	m.keyPairState[name] = keyPair
+	return keyPair
+}
+
+// sshKeyAlgorithm returns the SSH algorithm name of a key type
+func sshKeyAlgorithm(keyType string) string {
+	if keyType == "rsa" {
+		return "ssh-rsa"
+	}
+	return "ssh-ed25519"
+}
+
+// parseAuthorizedKey parses a public key in OpenSSH authorized_keys format
+// and returns its key type and SSH wire-format key
+func parseAuthorizedKey(authorizedKey string) (keyType string, publicKey []byte, err error) {
+	fields := strings.Fields(authorizedKey)
+	if len(fields) < 2 {
+		return "", nil, fmt.Errorf("public key must be in OpenSSH format: \"<algorithm> <base64 key> [comment]\"")
+	}
+	publicKey, err = base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("public key is not valid base64: %v", err)
+	}
+
+	var algorithm []byte
+	if len(publicKey) >= 4 {
+		n := binary.BigEndian.Uint32(publicKey)
+		if uint64(n) <= uint64(len(publicKey)-4) {
+			algorithm = publicKey[4 : 4+n]
+		}
+	}
+	if string(algorithm) != fields[0] {
+		return "", nil, fmt.Errorf("public key algorithm %q doesn't match the key", fields[0])
+	}
+
+	switch fields[0] {
+	case "ssh-ed25519":
+		return "ed25519", publicKey, nil
+	case "ssh-rsa":
+		return "rsa", publicKey, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported public key algorithm %q", fields[0])
+	}
+}
+
+// generateEd25519Key generates an ed25519 key and returns its SSH
+// wire-format public key and its private key in unencrypted OpenSSH format
+func generateEd25519Key(comment string) (publicKey, privateKey []byte, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wire sshBuffer
+	wire.writeString([]byte("ssh-ed25519"))
+	wire.writeString(pub)
+	publicKey = wire.Bytes()
+
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, nil, err
+	}
+	var private sshBuffer
+	private.Write(check[:])
+	private.Write(check[:])
+	private.writeString([]byte("ssh-ed25519"))
+	private.writeString(pub)
+	private.writeString(priv)
+	private.writeString([]byte(comment))
+	for i := byte(1); private.Len()%8 != 0; i++ {
+		private.WriteByte(i)
+	}
+
+	var key sshBuffer
+	key.WriteString("openssh-key-v1\x00")
+	key.writeString([]byte("none")) // cipher
+	key.writeString([]byte("none")) // KDF
+	key.writeString(nil)            // KDF options
+	key.writeUint32(1)              // number of keys
+	key.writeString(publicKey)
+	key.writeString(private.Bytes())
+
+	return publicKey, pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: key.Bytes()}), nil
+}
+
+// generateRSAKey generates a 2048-bit RSA key and returns its SSH
+// wire-format public key and its private key in PKCS #1 PEM format
+func generateRSAKey() (publicKey, privateKey []byte, err error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wire sshBuffer
+	wire.writeString([]byte("ssh-rsa"))
+	wire.writeMPInt(big.NewInt(int64(priv.E)))
+	wire.writeMPInt(priv.N)
+
+	return wire.Bytes(), pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)}), nil
+}
+
+// sshBuffer builds SSH wire-format data (RFC 4251)
+type sshBuffer struct {
+	bytes.Buffer
+}
+
+func (b *sshBuffer) writeUint32(n uint32) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], n)
+	b.Write(length[:])
+}
+
+func (b *sshBuffer) writeString(s []byte) {
+	b.writeUint32(uint32(len(s)))
+	b.Write(s)
+}
+
+func (b *sshBuffer) writeMPInt(n *big.Int) {
+	magnitude := n.Bytes()
+	if len(magnitude) > 0 && magnitude[0]&0x80 != 0 {
+		// A leading zero keeps the number positive
+		magnitude = append([]byte{0}, magnitude...)
+	}
+	b.writeString(magnitude)
+}
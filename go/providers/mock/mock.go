@@ -60,6 +60,7 @@
 //
 //	provider := mock.New("us-east-1").
 //	    WithSupportedServices(cloudsdk.ServiceCompute, cloudsdk.ServiceStorage).
+//	    WithUnsupportedOperations(cloudsdk.OpRequestSpotInstances).
 //	    WithVMResponse("web-server", mockVM).
 //	    WithBucketResponse("my-bucket", mockBucket).
 //	    WithError("DeleteVM", mockError).
@@ -82,7 +83,9 @@
 package mock
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -95,18 +98,22 @@ import (
 // to enable comprehensive testing without real cloud dependencies.
 type MockProvider struct {
 	// Configuration
-	region            string
-	supportedServices []cloudsdk.ServiceType
+	region                string
+	supportedServices     []cloudsdk.ServiceType
+	unsupportedOperations map[string]bool
+	unsupportedFields     map[string][]string
 
 	// Response configuration
 	vmResponses     map[string]*services.VM
 	bucketResponses map[string]bool // true if bucket exists
 	dbResponses     map[string]*services.DBInstance
 	objectResponses map[string]map[string][]byte // bucket -> key -> data
+	imageResponses  []*services.Image
 
 	// Error injection
 	errors map[string]error
 	delays map[string]time.Duration
+	quotas map[string]int
 
 	// Operation recording
 	mu           sync.RWMutex
@@ -115,9 +122,25 @@ type MockProvider struct {
 	lastCallArgs map[string][]interface{}
 
 	// State management
-	vmState     map[string]*services.VM
-	bucketState map[string]*BucketState
-	dbState     map[string]*services.DBInstance
+	vmState            map[string]*services.VM
+	bucketState        map[string]*BucketState
+	dbState            map[string]*services.DBInstance
+	protectedDB        map[string]bool // IDs of databases with deletion protection
+	spotState          map[string]*services.SpotInstanceRequest
+	volumeState        map[string]*services.Volume
+	snapshotState      map[string]*services.VolumeSnapshot
+	imageState         map[string]*services.Image
+	keyPairState       map[string]*services.KeyPair // keyed by name
+	securityGroupState map[string]*services.SecurityGroup
+
+	// Idempotency keys of create operations, keyed by operation and key
+	idempotencyKeys map[string]idempotentCall
+}
+
+// idempotentCall is the first create call made with an idempotency key
+type idempotentCall struct {
+	config interface{}
+	result interface{}
 }
 
 // Operation represents a recorded operation for verification
@@ -156,18 +179,29 @@ func New(region string) *MockProvider {
 			cloudsdk.ServiceStorage,
 			cloudsdk.ServiceDatabase,
 		},
-		vmResponses:     make(map[string]*services.VM),
-		bucketResponses: make(map[string]bool),
-		dbResponses:     make(map[string]*services.DBInstance),
-		objectResponses: make(map[string]map[string][]byte),
-		errors:          make(map[string]error),
-		delays:          make(map[string]time.Duration),
-		operations:      make([]Operation, 0),
-		callCounts:      make(map[string]int),
-		lastCallArgs:    make(map[string][]interface{}),
-		vmState:         make(map[string]*services.VM),
-		bucketState:     make(map[string]*BucketState),
-		dbState:         make(map[string]*services.DBInstance),
+		unsupportedOperations: make(map[string]bool),
+		unsupportedFields:     make(map[string][]string),
+		vmResponses:           make(map[string]*services.VM),
+		bucketResponses:       make(map[string]bool),
+		dbResponses:           make(map[string]*services.DBInstance),
+		objectResponses:       make(map[string]map[string][]byte),
+		errors:                make(map[string]error),
+		delays:                make(map[string]time.Duration),
+		quotas:                make(map[string]int),
+		operations:            make([]Operation, 0),
+		callCounts:            make(map[string]int),
+		lastCallArgs:          make(map[string][]interface{}),
+		vmState:               make(map[string]*services.VM),
+		bucketState:           make(map[string]*BucketState),
+		dbState:               make(map[string]*services.DBInstance),
+		protectedDB:           make(map[string]bool),
+		spotState:             make(map[string]*services.SpotInstanceRequest),
+		volumeState:           make(map[string]*services.Volume),
+		snapshotState:         make(map[string]*services.VolumeSnapshot),
+		imageState:            defaultImages(),
+		keyPairState:          make(map[string]*services.KeyPair),
+		securityGroupState:    make(map[string]*services.SecurityGroup),
+		idempotencyKeys:       make(map[string]idempotentCall),
 	}
 }
 
@@ -184,6 +218,36 @@ func (m *MockProvider) WithSupportedServices(services ...cloudsdk.ServiceType) *
 	return m
 }
 
+// WithUnsupportedOperations removes operations from the mock provider's
+// Capabilities, so that a Client rejects them with ErrOperationNotSupported.
+// This allows testing code against providers that lack sub-features such as
+// spot instances or placement groups.
+//
+// Example:
+//
+//	provider := mock.New("us-east-1").
+//	    WithUnsupportedOperations(cloudsdk.OpRequestSpotInstances)
+func (m *MockProvider) WithUnsupportedOperations(operations ...string) *MockProvider {
+	for _, operation := range operations {
+		m.unsupportedOperations[operation] = true
+	}
+	return m
+}
+
+// WithUnsupportedFields marks configuration fields of an operation as
+// unsupported in the mock provider's Capabilities, so that a Client rejects
+// calls that set them with ErrOperationNotSupported.
+//
+// Example:
+//
+//	// Mock a provider without multi-AZ databases
+//	provider := mock.New("us-east-1").
+//	    WithUnsupportedFields(cloudsdk.OpCreateDB, "MultiAZ")
+func (m *MockProvider) WithUnsupportedFields(operation string, fields ...string) *MockProvider {
+	m.unsupportedFields[operation] = append(m.unsupportedFields[operation], fields...)
+	return m
+}
+
 // WithVMResponse configures a specific response for VM operations.
 // When a VM with the specified name is created or retrieved,
 // the mock provider will return the configured VM object.
@@ -268,6 +332,19 @@ func (m *MockProvider) WithDelay(operation string, delay time.Duration) *MockPro
 	return m
 }
 
+// WithQuota limits how many resources a create operation ("CreateVM",
+// "CreateBucket" or "CreateDB") may have in existence at once. Further
+// creates fail with ErrQuotaExceeded until resources are deleted.
+//
+// Example:
+//
+//	// Allow two VMs at a time
+//	provider := mock.New("us-east-1").WithQuota("CreateVM", 2)
+func (m *MockProvider) WithQuota(operation string, limit int) *MockProvider {
+	m.quotas[operation] = limit
+	return m
+}
+
 // recordOperation records an operation for later verification
 func (m *MockProvider) recordOperation(method string, args []interface{}, result interface{}, err error) {
 	m.mu.Lock()
@@ -294,6 +371,62 @@ func (m *MockProvider) checkError(operation string) error {
 	return nil
 }
 
+// checkQuota returns ErrQuotaExceeded if a create operation would go over
+// its configured quota, given how many resources already exist
+func (m *MockProvider) checkQuota(operation, service string, existing int) error {
+	limit, exists := m.quotas[operation]
+	if !exists || existing < limit {
+		return nil
+	}
+	return cloudsdk.NewQuotaExceededError("mock", service, operation,
+		fmt.Sprintf("%s is limited to %d resources", operation, limit))
+}
+
+// checkIdempotency looks up an earlier call of a create operation with the
+// same idempotency key. If there was one, found is true and it returns that
+// call's result, or ErrResourceConflict if config differs from the earlier
+// call's. Calls without a key are never found.
+func (m *MockProvider) checkIdempotency(operation, service, key string, config interface{}) (result interface{}, found bool, err error) {
+	if key == "" {
+		return nil, false, nil
+	}
+	m.mu.RLock()
+	call, found := m.idempotencyKeys[operation+"/"+key]
+	m.mu.RUnlock()
+	if !found {
+		return nil, false, nil
+	}
+	if !reflect.DeepEqual(call.config, config) {
+		return nil, true, cloudsdk.NewIdempotencyConflictError("mock", service, operation, key)
+	}
+	return call.result, true, nil
+}
+
+// recordIdempotency remembers a successful create call made with an
+// idempotency key, so that repeating it returns the same result
+func (m *MockProvider) recordIdempotency(operation, key string, config, result interface{}) {
+	if key == "" {
+		return
+	}
+	// Keep a copy, so that changes the caller makes to config afterwards
+	// don't count as a different configuration
+	configCopy := reflect.New(reflect.TypeOf(config).Elem())
+	configCopy.Elem().Set(reflect.ValueOf(config).Elem())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idempotencyKeys[operation+"/"+key] = idempotentCall{config: configCopy.Interface(), result: result}
+}
+
+// regionFor returns the region a call is made in: the region of its
+// services.CallOptions, if set, or else the provider's region
+func (m *MockProvider) regionFor(ctx context.Context) string {
+	if region := services.CallOptionsFromContext(ctx).Region; region != "" {
+		return region
+	}
+	return m.region
+}
+
 // applyDelay applies any configured delay for the operation
 func (m *MockProvider) applyDelay(operation string) {
 	if delay, exists := m.delays[operation]; exists {
@@ -344,6 +477,17 @@ func (m *MockProvider) Reset() {
 	m.vmState = make(map[string]*services.VM)
 	m.bucketState = make(map[string]*BucketState)
 	m.dbState = make(map[string]*services.DBInstance)
+	m.protectedDB = make(map[string]bool)
+	m.spotState = make(map[string]*services.SpotInstanceRequest)
+	m.volumeState = make(map[string]*services.Volume)
+	m.snapshotState = make(map[string]*services.VolumeSnapshot)
+	m.imageState = defaultImages()
+	for _, image := range m.imageResponses {
+		m.imageState[image.ID] = image
+	}
+	m.keyPairState = make(map[string]*services.KeyPair)
+	m.securityGroupState = make(map[string]*services.SecurityGroup)
+	m.idempotencyKeys = make(map[string]idempotentCall)
 }
 
 // Provider interface implementation
@@ -363,6 +507,28 @@ func (m *MockProvider) SupportedServices() []cloudsdk.ServiceType {
 	return m.supportedServices
 }
 
+// Capabilities returns every operation of the supported services except
+// those removed with WithUnsupportedOperations, and the fields marked with
+// WithUnsupportedFields.
+func (m *MockProvider) Capabilities() cloudsdk.Capabilities {
+	caps := cloudsdk.Capabilities{
+		Operations:        []string{},
+		UnsupportedFields: make(map[string][]string, len(m.unsupportedFields)),
+		RegionOverride:    true,
+	}
+	for _, service := range m.supportedServices {
+		for _, operation := range cloudsdk.ServiceOperations(service) {
+			if !m.unsupportedOperations[operation] {
+				caps.Operations = append(caps.Operations, operation)
+			}
+		}
+	}
+	for operation, fields := range m.unsupportedFields {
+		caps.UnsupportedFields[operation] = append([]string(nil), fields...)
+	}
+	return caps
+}
+
 // Compute returns the mock compute service
 func (m *MockProvider) Compute() services.Compute {
 	return &MockCompute{provider: m}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"net/url"
+	"strings"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+)
+
+// defaultRegion is used when a mock provider URL doesn't specify a region.
+const defaultRegion = "us-east-1"
+
+func init() {
+	cloudsdk.Register("mock", newFromURL)
+}
+
+// newFromURL builds a MockProvider from a provider URL such as
+// "mock://us-east-1?services=compute,storage".
+//
+// Supported parameters:
+//   - services: comma-separated list of supported services (see WithSupportedServices)
+func newFromURL(region string, params url.Values) (cloudsdk.Provider, error) {
+	if region == "" {
+		region = defaultRegion
+	}
+	provider := New(region)
+
+	for key, values := range params {
+		switch key {
+		case "services":
+			var supported []cloudsdk.ServiceType
+			for _, value := range values {
+				for _, name := range strings.Split(value, ",") {
+					name = strings.TrimSpace(name)
+					if name == "" {
+						continue
+					}
+					service := cloudsdk.ServiceType(name)
+					switch service {
+					case cloudsdk.ServiceCompute, cloudsdk.ServiceStorage, cloudsdk.ServiceDatabase:
+						supported = append(supported, service)
+					default:
+						return nil, cloudsdk.NewInvalidConfigError("mock", "", "services", "unknown service '"+name+"'").
+							WithSuggestions("Use a comma-separated list of: compute, storage, database")
+					}
+				}
+			}
+			provider.WithSupportedServices(supported...)
+		default:
+			return nil, cloudsdk.NewInvalidConfigError("mock", "", key, "unknown provider URL parameter").
+				WithSuggestions("Supported parameters are: services")
+		}
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,362 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// mockDefaultNetworkID is the network security groups are created in when
+// SecurityGroupConfig.NetworkID is empty.
+const mockDefaultNetworkID = "vpc-default"
+
+// MockSecurityGroupsService implements the services.SecurityGroupsService
+// interface for testing. Groups and their rules are kept in the provider's
+// state.
+type MockSecurityGroupsService struct {
+	provider *MockProvider
+}
+
+// SecurityGroups returns the mock security groups service
+func (m *MockCompute) SecurityGroups() services.SecurityGroupsService {
+	return &MockSecurityGroupsService{provider: m.provider}
+}
+
+// Create creates a mock security group that, as on AWS, allows all outbound
+// IPv4 traffic.
+//
+// Error injection:
+//   - Configure errors using WithError("CreateSecurityGroup", error)
+//   - Configure quotas using WithQuota("CreateSecurityGroup", limit)
+//   - Invalid configurations return cloudsdk.ValidationErrors
+//   - Returns ErrResourceConflict if the network has a group with the name
+func (s *MockSecurityGroupsService) Create(ctx context.Context, config *services.SecurityGroupConfig) (*services.SecurityGroup, error) {
+	s.provider.applyDelay("CreateSecurityGroup")
+
+	if err := s.provider.checkError("CreateSecurityGroup"); err != nil {
+		s.provider.recordOperation("CreateSecurityGroup", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("CreateSecurityGroup", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	networkID := config.NetworkID
+	if networkID == "" {
+		networkID = mockDefaultNetworkID
+	}
+
+	for _, group := range s.provider.securityGroupState {
+		if group.NetworkID == networkID && group.Name == config.Name {
+			err := cloudsdk.NewCloudError(
+				cloudsdk.ErrResourceConflict,
+				fmt.Sprintf("Security group '%s' already exists in %s as %s", config.Name, networkID, group.ID),
+				"mock", "compute", "CreateSecurityGroup",
+			).WithSuggestions(
+				"Choose a different security group name",
+				"Use SecurityGroups().List to find the existing group",
+			)
+			s.provider.recordOperation("CreateSecurityGroup", []interface{}{config}, nil, err)
+			return nil, err
+		}
+	}
+
+	if err := s.provider.checkQuota("CreateSecurityGroup", "compute", len(s.provider.securityGroupState)); err != nil {
+		s.provider.recordOperation("CreateSecurityGroup", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	description := config.Description
+	if description == "" {
+		description = config.Name
+	}
+	group := &services.SecurityGroup{
+		ID:          fmt.Sprintf("sg-%017x", time.Now().UnixNano()),
+		Name:        config.Name,
+		Description: description,
+		NetworkID:   networkID,
+		Rules: []services.FirewallRule{
+			{Direction: "egress", Protocol: "all", CIDR: "0.0.0.0/0"},
+		},
+		Tags: make(map[string]string, len(config.Tags)),
+	}
+	for key, value := range config.Tags {
+		group.Tags[key] = value
+	}
+
+	s.provider.securityGroupState[group.ID] = group
+	s.provider.recordOperation("CreateSecurityGroup", []interface{}{config}, group, nil)
+	return group, nil
+}
+
+// List returns the mock security groups that match filter, sorted by ID.
+//
+// Error injection:
+//   - Configure errors using WithError("ListSecurityGroups", error)
+func (s *MockSecurityGroupsService) List(ctx context.Context, filter *services.SecurityGroupFilter) ([]*services.SecurityGroup, error) {
+	s.provider.applyDelay("ListSecurityGroups")
+
+	if err := s.provider.checkError("ListSecurityGroups"); err != nil {
+		s.provider.recordOperation("ListSecurityGroups", []interface{}{filter}, nil, err)
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &services.SecurityGroupFilter{}
+	}
+	groups := []*services.SecurityGroup{}
+	for _, group := range s.provider.securityGroupState {
+		if matchesSecurityGroupFilter(group, filter) {
+			groups = append(groups, group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+
+	s.provider.recordOperation("ListSecurityGroups", []interface{}{filter}, groups, nil)
+	return groups, nil
+}
+
+// matchesSecurityGroupFilter reports whether group matches every filter that is set
+func matchesSecurityGroupFilter(group *services.SecurityGroup, filter *services.SecurityGroupFilter) bool {
+	if filter.Name != "" && group.Name != filter.Name {
+		return false
+	}
+	if filter.NetworkID != "" && group.NetworkID != filter.NetworkID {
+		return false
+	}
+	for key, value := range filter.Tags {
+		if tag, ok := group.Tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Get retrieves a mock security group by ID.
+//
+// Error injection:
+//   - Configure errors using WithError("GetSecurityGroup", error)
+//   - Automatically returns ErrResourceNotFound for non-existent groups
+func (s *MockSecurityGroupsService) Get(ctx context.Context, id string) (*services.SecurityGroup, error) {
+	s.provider.applyDelay("GetSecurityGroup")
+
+	if err := s.provider.checkError("GetSecurityGroup"); err != nil {
+		s.provider.recordOperation("GetSecurityGroup", []interface{}{id}, nil, err)
+		return nil, err
+	}
+
+	group, exists := s.provider.securityGroupState[id]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "SecurityGroup", id)
+		s.provider.recordOperation("GetSecurityGroup", []interface{}{id}, nil, err)
+		return nil, err
+	}
+
+	s.provider.recordOperation("GetSecurityGroup", []interface{}{id}, group, nil)
+	return group, nil
+}
+
+// AddRules adds rules to a mock security group. Either every rule is added
+// or, on error, none is.
+//
+// Error injection:
+//   - Configure errors using WithError("AddSecurityGroupRules", error)
+//   - Invalid rules return cloudsdk.ValidationErrors
+//   - Automatically returns ErrResourceNotFound for non-existent groups
+//   - Returns ErrInvalidConfig if a rule's SourceGroupID doesn't exist
+//   - Returns ErrResourceConflict if the group already has a rule
+func (s *MockSecurityGroupsService) AddRules(ctx context.Context, groupID string, rules []services.FirewallRule) error {
+	s.provider.applyDelay("AddSecurityGroupRules")
+
+	args := []interface{}{groupID, rules}
+	if err := s.provider.checkError("AddSecurityGroupRules"); err != nil {
+		s.provider.recordOperation("AddSecurityGroupRules", args, nil, err)
+		return err
+	}
+
+	if err := cloudsdk.ValidateFirewallRules("mock", "compute", rules); err != nil {
+		s.provider.recordOperation("AddSecurityGroupRules", args, nil, err)
+		return err
+	}
+
+	group, exists := s.provider.securityGroupState[groupID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "SecurityGroup", groupID)
+		s.provider.recordOperation("AddSecurityGroupRules", args, nil, err)
+		return err
+	}
+
+	added := append([]services.FirewallRule(nil), group.Rules...)
+	for i, rule := range rules {
+		if rule.SourceGroupID != "" {
+			if _, exists := s.provider.securityGroupState[rule.SourceGroupID]; !exists {
+				err := cloudsdk.NewInvalidConfigError("mock", "compute", fmt.Sprintf("Rules[%d].SourceGroupID", i),
+					fmt.Sprintf("security group '%s' not found", rule.SourceGroupID))
+				s.provider.recordOperation("AddSecurityGroupRules", args, nil, err)
+				return err
+			}
+		}
+
+		rule = normalizeRule(rule)
+		if findRule(added, rule) >= 0 {
+			err := cloudsdk.NewCloudError(
+				cloudsdk.ErrResourceConflict,
+				fmt.Sprintf("Security group %s already has the %s rule for %s", groupID, rule.Direction, describeRule(rule)),
+				"mock", "compute", "AddSecurityGroupRules",
+			).WithSuggestions(
+				"Use SecurityGroups().Get to check the group's current rules",
+				"Leave rules the group already has out of the request",
+			)
+			s.provider.recordOperation("AddSecurityGroupRules", args, nil, err)
+			return err
+		}
+		added = append(added, rule)
+	}
+
+	group.Rules = added
+	s.provider.recordOperation("AddSecurityGroupRules", args, nil, nil)
+	return nil
+}
+
+// RevokeRules removes rules from a mock security group. Either every rule
+// is removed or, on error, none is.
+//
+// Error injection:
+//   - Configure errors using WithError("RevokeSecurityGroupRules", error)
+//   - Invalid rules return cloudsdk.ValidationErrors
+//   - Automatically returns ErrResourceNotFound for non-existent groups and rules
+func (s *MockSecurityGroupsService) RevokeRules(ctx context.Context, groupID string, rules []services.FirewallRule) error {
+	s.provider.applyDelay("RevokeSecurityGroupRules")
+
+	args := []interface{}{groupID, rules}
+	if err := s.provider.checkError("RevokeSecurityGroupRules"); err != nil {
+		s.provider.recordOperation("RevokeSecurityGroupRules", args, nil, err)
+		return err
+	}
+
+	if err := cloudsdk.ValidateFirewallRules("mock", "compute", rules); err != nil {
+		s.provider.recordOperation("RevokeSecurityGroupRules", args, nil, err)
+		return err
+	}
+
+	group, exists := s.provider.securityGroupState[groupID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "SecurityGroup", groupID)
+		s.provider.recordOperation("RevokeSecurityGroupRules", args, nil, err)
+		return err
+	}
+
+	remaining := append([]services.FirewallRule(nil), group.Rules...)
+	for _, rule := range rules {
+		rule = normalizeRule(rule)
+		i := findRule(remaining, rule)
+		if i < 0 {
+			err := cloudsdk.NewResourceNotFoundError("mock", "compute", "SecurityGroupRule",
+				fmt.Sprintf("%s %s %s", groupID, rule.Direction, describeRule(rule)))
+			s.provider.recordOperation("RevokeSecurityGroupRules", args, nil, err)
+			return err
+		}
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+
+	group.Rules = remaining
+	s.provider.recordOperation("RevokeSecurityGroupRules", args, nil, nil)
+	return nil
+}
+
+// normalizeRule fills in the ToPort of single-port rules, so that rules
+// compare equal however their port is given
+func normalizeRule(rule services.FirewallRule) services.FirewallRule {
+	if (rule.Protocol == "tcp" || rule.Protocol == "udp") && rule.ToPort == 0 {
+		rule.ToPort = rule.FromPort
+	}
+	return rule
+}
+
+// findRule returns the index of the rule in rules that matches rule in
+// every field but Description, or -1
+func findRule(rules []services.FirewallRule, rule services.FirewallRule) int {
+	for i, existing := range rules {
+		existing.Description = rule.Description
+		if existing == rule {
+			return i
+		}
+	}
+	return -1
+}
+
+// describeRule describes the traffic a normalized rule allows, for errors
+func describeRule(rule services.FirewallRule) string {
+	peer := rule.CIDR
+	if peer == "" {
+		peer = rule.SourceGroupID
+	}
+	switch {
+	case rule.Protocol == "all" || rule.Protocol == "icmp":
+		return fmt.Sprintf("%s with %s", rule.Protocol, peer)
+	case rule.FromPort == rule.ToPort:
+		return fmt.Sprintf("%s/%d with %s", rule.Protocol, rule.FromPort, peer)
+	default:
+		return fmt.Sprintf("%s/%d-%d with %s", rule.Protocol, rule.FromPort, rule.ToPort, peer)
+	}
+}
+
+// Delete removes a mock security group.
+//
+// Error injection:
+//   - Configure errors using WithError("DeleteSecurityGroup", error)
+//   - Automatically returns ErrResourceNotFound for non-existent groups
+//   - Returns ErrDependencyViolation while a mock VM is in the group, or
+//     another group's rules refer to it
+func (s *MockSecurityGroupsService) Delete(ctx context.Context, id string) error {
+	s.provider.applyDelay("DeleteSecurityGroup")
+
+	if err := s.provider.checkError("DeleteSecurityGroup"); err != nil {
+		s.provider.recordOperation("DeleteSecurityGroup", []interface{}{id}, nil, err)
+		return err
+	}
+
+	if _, exists := s.provider.securityGroupState[id]; !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "SecurityGroup", id)
+		s.provider.recordOperation("DeleteSecurityGroup", []interface{}{id}, nil, err)
+		return err
+	}
+
+	if reason := s.provider.securityGroupDependent(id); reason != "" {
+		err := cloudsdk.NewDependencyViolationError("mock", "compute", "DeleteSecurityGroup", reason)
+		s.provider.recordOperation("DeleteSecurityGroup", []interface{}{id}, nil, err)
+		return err
+	}
+
+	delete(s.provider.securityGroupState, id)
+
+	s.provider.recordOperation("DeleteSecurityGroup", []interface{}{id}, nil, nil)
+	return nil
+}
+
+// securityGroupDependent describes a VM in the security group or another
+// group whose rules refer to it, or returns "" if there is none
+func (m *MockProvider) securityGroupDependent(id string) string {
+	for _, vm := range m.vmState {
+		if containsString(vm.SecurityGroups, id) {
+			return fmt.Sprintf("security group %s is used by VM %s", id, vm.ID)
+		}
+	}
+	for _, group := range m.securityGroupState {
+		if group.ID == id {
+			continue
+		}
+		for _, rule := range group.Rules {
+			if rule.SourceGroupID == id {
+				return fmt.Sprintf("security group %s is referenced by a rule of %s", id, group.ID)
+			}
+		}
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"strings"
 
@@ -20,6 +21,10 @@ type MockStorage struct {
 //
 // Error injection:
 //   - Configure errors using WithError("CreateBucket", error)
+//   - Invalid configurations return cloudsdk.ValidationErrors, as real providers do
+//   - Returns ErrQuotaExceeded once the WithQuota("CreateBucket", n) limit is reached
+//   - Repeating a call with the same IdempotencyKey succeeds without creating
+//     anything, or returns ErrResourceConflict if the configuration differs
 //   - Automatically returns ErrResourceConflict if bucket already exists
 //   - Common test scenarios: authentication, authorization, invalid names
 //
@@ -42,6 +47,21 @@ func (m *MockStorage) CreateBucket(ctx context.Context, config *services.BucketC
 		return err
 	}
 
+	if err := cloudsdk.ValidateConfig("mock", "storage", config); err != nil {
+		m.provider.recordOperation("CreateBucket", []interface{}{config}, nil, err)
+		return err
+	}
+
+	if _, found, err := m.provider.checkIdempotency("CreateBucket", "storage", config.IdempotencyKey, config); found {
+		m.provider.recordOperation("CreateBucket", []interface{}{config}, nil, err)
+		return err
+	}
+
+	if err := m.provider.checkQuota("CreateBucket", "storage", len(m.provider.bucketState)); err != nil {
+		m.provider.recordOperation("CreateBucket", []interface{}{config}, nil, err)
+		return err
+	}
+
 	// Check if bucket already exists
 	if _, exists := m.provider.bucketState[config.Name]; exists {
 		err := cloudsdk.NewCloudError(
@@ -56,13 +76,19 @@ func (m *MockStorage) CreateBucket(ctx context.Context, config *services.BucketC
 		return err
 	}
 
+	region := config.Region
+	if region == "" {
+		region = m.provider.regionFor(ctx)
+	}
+
 	// Create bucket in state
 	m.provider.bucketState[config.Name] = &BucketState{
 		Name:    config.Name,
-		Region:  config.Region,
+		Region:  region,
 		Objects: make(map[string][]byte),
 		Tags:    config.Tags,
 	}
+	m.provider.recordIdempotency("CreateBucket", config.IdempotencyKey, config, nil)
 
 	m.provider.recordOperation("CreateBucket", []interface{}{config}, nil, nil)
 	return nil
@@ -104,7 +130,7 @@ func (m *MockStorage) ListBuckets(ctx context.Context) ([]string, error) {
 // Error injection:
 //   - Configure errors using WithError("DeleteBucket", error)
 //   - Automatically returns ErrResourceNotFound for non-existent buckets
-//   - Returns ErrResourceConflict if bucket contains objects
+//   - Returns ErrDependencyViolation if bucket contains objects
 //
 // Example:
 //
@@ -130,14 +156,8 @@ func (m *MockStorage) DeleteBucket(ctx context.Context, name string) error {
 
 	// Check if bucket is empty
 	if len(bucket.Objects) > 0 {
-		err := cloudsdk.NewCloudError(
-			cloudsdk.ErrResourceConflict,
-			"Bucket is not empty",
-			"mock", "storage", "DeleteBucket",
-		).WithSuggestions(
-			"Delete all objects in the bucket first",
-			"Use force delete if supported",
-		)
+		err := cloudsdk.NewDependencyViolationError("mock", "storage", "DeleteBucket",
+			fmt.Sprintf("bucket '%s' is not empty", name))
 		m.provider.recordOperation("DeleteBucket", []interface{}{name}, nil, err)
 		return err
 	}
@@ -0,0 +1,410 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// MockVolumesService implements the services.VolumesService interface for
+// testing. Volumes, attachments and snapshots are kept in the provider's
+// state, and state changes take effect immediately: volumes are created
+// "available", attach and detach complete at once, and snapshots are
+// created "completed".
+type MockVolumesService struct {
+	provider *MockProvider
+}
+
+// Volumes returns the mock volumes service
+func (m *MockCompute) Volumes() services.VolumesService {
+	return &MockVolumesService{provider: m.provider}
+}
+
+// Create creates a mock volume.
+//
+// Error injection:
+//   - Configure errors using WithError("CreateVolume", error)
+//   - Invalid configurations return cloudsdk.ValidationErrors
+//   - Returns ErrResourceNotFound if SnapshotID names an unknown snapshot
+//   - Returns ErrQuotaExceeded once the WithQuota("CreateVolume", n) limit is reached
+//   - Repeating a call with the same IdempotencyKey returns the first call's
+//     volume, or ErrResourceConflict if the configuration differs
+func (s *MockVolumesService) Create(ctx context.Context, config *services.VolumeConfig) (*services.Volume, error) {
+	s.provider.applyDelay("CreateVolume")
+
+	if err := s.provider.checkError("CreateVolume"); err != nil {
+		s.provider.recordOperation("CreateVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("CreateVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if prior, found, err := s.provider.checkIdempotency("CreateVolume", "compute", config.IdempotencyKey, config); found {
+		volume, _ := prior.(*services.Volume)
+		s.provider.recordOperation("CreateVolume", []interface{}{config}, volume, err)
+		return volume, err
+	}
+
+	if err := s.provider.checkQuota("CreateVolume", "compute", len(s.provider.volumeState)); err != nil {
+		s.provider.recordOperation("CreateVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	size := config.SizeGiB
+	if config.SnapshotID != "" {
+		snapshot, exists := s.provider.snapshotState[config.SnapshotID]
+		if !exists {
+			err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Snapshot", config.SnapshotID)
+			s.provider.recordOperation("CreateVolume", []interface{}{config}, nil, err)
+			return nil, err
+		}
+		if size == 0 {
+			size = snapshot.SizeGiB
+		}
+	}
+	if size < 1 {
+		err := cloudsdk.NewInvalidConfigError("mock", "compute", "SizeGiB", "size is required unless creating from a snapshot")
+		s.provider.recordOperation("CreateVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	volumeType := config.VolumeType
+	if volumeType == "" {
+		volumeType = "gp2"
+	}
+	volume := &services.Volume{
+		ID:               fmt.Sprintf("vol-%017x", time.Now().UnixNano()),
+		Name:             config.Name,
+		State:            "available",
+		SizeGiB:          size,
+		VolumeType:       volumeType,
+		AvailabilityZone: config.AvailabilityZone,
+		Encrypted:        config.Encrypted != nil && *config.Encrypted,
+		SnapshotID:       config.SnapshotID,
+		Tags:             make(map[string]string, len(config.Tags)),
+		CreateTime:       time.Now().Format(time.RFC3339),
+	}
+	if config.Iops != nil {
+		volume.Iops = *config.Iops
+	}
+	if config.Throughput != nil {
+		volume.Throughput = *config.Throughput
+	}
+	for key, value := range config.Tags {
+		volume.Tags[key] = value
+	}
+
+	s.provider.volumeState[volume.ID] = volume
+	s.provider.recordIdempotency("CreateVolume", config.IdempotencyKey, config, volume)
+	s.provider.recordOperation("CreateVolume", []interface{}{config}, volume, nil)
+	return volume, nil
+}
+
+// List returns the mock volumes that match filter, sorted by ID.
+//
+// Error injection:
+//   - Configure errors using WithError("ListVolumes", error)
+func (s *MockVolumesService) List(ctx context.Context, filter *services.VolumeFilter) ([]*services.Volume, error) {
+	s.provider.applyDelay("ListVolumes")
+
+	if err := s.provider.checkError("ListVolumes"); err != nil {
+		s.provider.recordOperation("ListVolumes", []interface{}{filter}, nil, err)
+		return nil, err
+	}
+
+	volumes := []*services.Volume{}
+	for _, volume := range s.provider.volumeState {
+		if matchesVolumeFilter(volume, filter) {
+			volumes = append(volumes, volume)
+		}
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].ID < volumes[j].ID })
+
+	s.provider.recordOperation("ListVolumes", []interface{}{filter}, volumes, nil)
+	return volumes, nil
+}
+
+// matchesVolumeFilter reports whether volume matches every filter that is set
+func matchesVolumeFilter(volume *services.Volume, filter *services.VolumeFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.VMID != "" && (len(volume.Attachments) == 0 || volume.Attachments[0].VMID != filter.VMID) {
+		return false
+	}
+	if filter.AvailabilityZone != "" && volume.AvailabilityZone != filter.AvailabilityZone {
+		return false
+	}
+	if len(filter.States) > 0 && !containsString(filter.States, volume.State) {
+		return false
+	}
+	for key, value := range filter.Tags {
+		if tag, ok := volume.Tags[key]; !ok || tag != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Get retrieves a mock volume by ID.
+//
+// Error injection:
+//   - Configure errors using WithError("GetVolume", error)
+//   - Automatically returns ErrResourceNotFound for non-existent volumes
+func (s *MockVolumesService) Get(ctx context.Context, id string) (*services.Volume, error) {
+	s.provider.applyDelay("GetVolume")
+
+	if err := s.provider.checkError("GetVolume"); err != nil {
+		s.provider.recordOperation("GetVolume", []interface{}{id}, nil, err)
+		return nil, err
+	}
+
+	volume, exists := s.provider.volumeState[id]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Volume", id)
+		s.provider.recordOperation("GetVolume", []interface{}{id}, nil, err)
+		return nil, err
+	}
+
+	s.provider.recordOperation("GetVolume", []interface{}{id}, volume, nil)
+	return volume, nil
+}
+
+// Attach attaches a mock volume to a mock VM, updating both the volume's
+// Attachments and the VM's Volumes.
+//
+// Error injection:
+//   - Configure errors using WithError("AttachVolume", error)
+//   - Automatically returns ErrResourceNotFound for non-existent volumes or VMs
+//   - Returns ErrInvalidState if the volume is not available
+//   - Returns ErrResourceConflict if the VM already uses device
+func (s *MockVolumesService) Attach(ctx context.Context, volumeID, vmID, device string) error {
+	args := []interface{}{volumeID, vmID, device}
+	s.provider.applyDelay("AttachVolume")
+
+	if err := s.provider.checkError("AttachVolume"); err != nil {
+		s.provider.recordOperation("AttachVolume", args, nil, err)
+		return err
+	}
+
+	if device == "" {
+		err := cloudsdk.NewInvalidConfigError("mock", "compute", "Device", "device name is required")
+		s.provider.recordOperation("AttachVolume", args, nil, err)
+		return err
+	}
+
+	volume, exists := s.provider.volumeState[volumeID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Volume", volumeID)
+		s.provider.recordOperation("AttachVolume", args, nil, err)
+		return err
+	}
+
+	vm, exists := s.provider.vmState[vmID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "VM", vmID)
+		s.provider.recordOperation("AttachVolume", args, nil, err)
+		return err
+	}
+
+	if volume.State != "available" {
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "AttachVolume", volumeID, volume.State)
+		s.provider.recordOperation("AttachVolume", args, nil, err)
+		return err
+	}
+
+	for _, attachment := range vm.Volumes {
+		if attachment.Device == device {
+			err := cloudsdk.NewCloudError(
+				cloudsdk.ErrResourceConflict,
+				fmt.Sprintf("Device %s is already in use on VM '%s'", device, vmID),
+				"mock", "compute", "AttachVolume",
+			).WithSuggestions(
+				"Choose a different device name",
+				"Detach the volume using the device first",
+			)
+			s.provider.recordOperation("AttachVolume", args, nil, err)
+			return err
+		}
+	}
+
+	attachment := services.VolumeAttachment{VolumeID: volumeID, VMID: vmID, Device: device, State: "attached"}
+	volume.State = "in-use"
+	volume.Attachments = []services.VolumeAttachment{attachment}
+	vm.Volumes = append(vm.Volumes, attachment)
+
+	s.provider.recordOperation("AttachVolume", args, nil, nil)
+	return nil
+}
+
+// Detach detaches a mock volume from its VM.
+//
+// Error injection:
+//   - Configure errors using WithError("DetachVolume", error)
+//   - Automatically returns ErrResourceNotFound for non-existent volumes
+//   - Returns ErrInvalidState if the volume is not attached
+func (s *MockVolumesService) Detach(ctx context.Context, volumeID string) error {
+	s.provider.applyDelay("DetachVolume")
+
+	if err := s.provider.checkError("DetachVolume"); err != nil {
+		s.provider.recordOperation("DetachVolume", []interface{}{volumeID}, nil, err)
+		return err
+	}
+
+	volume, exists := s.provider.volumeState[volumeID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Volume", volumeID)
+		s.provider.recordOperation("DetachVolume", []interface{}{volumeID}, nil, err)
+		return err
+	}
+
+	if len(volume.Attachments) == 0 {
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "DetachVolume", volumeID, volume.State)
+		s.provider.recordOperation("DetachVolume", []interface{}{volumeID}, nil, err)
+		return err
+	}
+
+	s.provider.detachVolume(volume)
+
+	s.provider.recordOperation("DetachVolume", []interface{}{volumeID}, nil, nil)
+	return nil
+}
+
+// detachVolume removes a volume's attachment from both the volume and its VM
+func (m *MockProvider) detachVolume(volume *services.Volume) {
+	for _, attachment := range volume.Attachments {
+		vm, exists := m.vmState[attachment.VMID]
+		if !exists {
+			continue
+		}
+		remaining := vm.Volumes[:0]
+		for _, vmAttachment := range vm.Volumes {
+			if vmAttachment.VolumeID != volume.ID {
+				remaining = append(remaining, vmAttachment)
+			}
+		}
+		vm.Volumes = remaining
+	}
+	volume.Attachments = nil
+	volume.State = "available"
+}
+
+// Resize grows a mock volume.
+//
+// Error injection:
+//   - Configure errors using WithError("ResizeVolume", error)
+//   - Automatically returns ErrResourceNotFound for non-existent volumes
+//   - Returns ErrInvalidConfig if sizeGiB is smaller than the current size
+func (s *MockVolumesService) Resize(ctx context.Context, volumeID string, sizeGiB int32) error {
+	args := []interface{}{volumeID, sizeGiB}
+	s.provider.applyDelay("ResizeVolume")
+
+	if err := s.provider.checkError("ResizeVolume"); err != nil {
+		s.provider.recordOperation("ResizeVolume", args, nil, err)
+		return err
+	}
+
+	volume, exists := s.provider.volumeState[volumeID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Volume", volumeID)
+		s.provider.recordOperation("ResizeVolume", args, nil, err)
+		return err
+	}
+
+	if sizeGiB < volume.SizeGiB {
+		err := cloudsdk.NewInvalidConfigError("mock", "compute", "SizeGiB",
+			fmt.Sprintf("volumes can't shrink: %d GiB is smaller than the current %d GiB", sizeGiB, volume.SizeGiB))
+		s.provider.recordOperation("ResizeVolume", args, nil, err)
+		return err
+	}
+
+	volume.SizeGiB = sizeGiB
+
+	s.provider.recordOperation("ResizeVolume", args, nil, nil)
+	return nil
+}
+
+// Snapshot creates a completed mock snapshot of a volume.
+//
+// Error injection:
+//   - Configure errors using WithError("SnapshotVolume", error)
+//   - Invalid configurations return cloudsdk.ValidationErrors
+//   - Automatically returns ErrResourceNotFound for non-existent volumes
+func (s *MockVolumesService) Snapshot(ctx context.Context, config *services.VolumeSnapshotConfig) (*services.VolumeSnapshot, error) {
+	s.provider.applyDelay("SnapshotVolume")
+
+	if err := s.provider.checkError("SnapshotVolume"); err != nil {
+		s.provider.recordOperation("SnapshotVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	if err := cloudsdk.ValidateConfig("mock", "compute", config); err != nil {
+		s.provider.recordOperation("SnapshotVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	volume, exists := s.provider.volumeState[config.VolumeID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Volume", config.VolumeID)
+		s.provider.recordOperation("SnapshotVolume", []interface{}{config}, nil, err)
+		return nil, err
+	}
+
+	snapshot := &services.VolumeSnapshot{
+		ID:          fmt.Sprintf("snap-%017x", time.Now().UnixNano()),
+		VolumeID:    volume.ID,
+		State:       "completed",
+		Progress:    "100%",
+		SizeGiB:     volume.SizeGiB,
+		Description: config.Description,
+		Tags:        make(map[string]string, len(config.Tags)),
+		StartTime:   time.Now().Format(time.RFC3339),
+	}
+	for key, value := range config.Tags {
+		snapshot.Tags[key] = value
+	}
+
+	s.provider.snapshotState[snapshot.ID] = snapshot
+	s.provider.recordOperation("SnapshotVolume", []interface{}{config}, snapshot, nil)
+	return snapshot, nil
+}
+
+// Delete deletes a mock volume. Its snapshots are kept.
+//
+// Error injection:
+//   - Configure errors using WithError("DeleteVolume", error)
+//   - Automatically returns ErrResourceNotFound for non-existent volumes
+//   - Returns ErrInvalidState if the volume is still attached
+func (s *MockVolumesService) Delete(ctx context.Context, volumeID string) error {
+	s.provider.applyDelay("DeleteVolume")
+
+	if err := s.provider.checkError("DeleteVolume"); err != nil {
+		s.provider.recordOperation("DeleteVolume", []interface{}{volumeID}, nil, err)
+		return err
+	}
+
+	volume, exists := s.provider.volumeState[volumeID]
+	if !exists {
+		err := cloudsdk.NewResourceNotFoundError("mock", "compute", "Volume", volumeID)
+		s.provider.recordOperation("DeleteVolume", []interface{}{volumeID}, nil, err)
+		return err
+	}
+
+	if len(volume.Attachments) > 0 {
+		err := cloudsdk.NewInvalidStateError("mock", "compute", "DeleteVolume", volumeID, volume.State)
+		s.provider.recordOperation("DeleteVolume", []interface{}{volumeID}, nil, err)
+		return err
+	}
+
+	delete(s.provider.volumeState, volumeID)
+
+	s.provider.recordOperation("DeleteVolume", []interface{}{volumeID}, nil, nil)
+	return nil
+}
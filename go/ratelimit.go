@@ -0,0 +1,276 @@
+package cloudsdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: it allows Rate calls per second on average,
+// with bursts of up to Burst calls.
+type RateLimit struct {
+	// Rate is the sustained number of calls per second. Limits with a Rate
+	// of zero or less are ignored.
+	Rate float64
+
+	// Burst is the number of calls that may be made at once after a quiet
+	// period. Values below 1 are treated as 1.
+	Burst int
+}
+
+// RateLimitConfig limits how fast a Client calls its provider, so that it
+// stays under the provider's API quotas instead of recovering from throttling
+// errors through retries.
+//
+// A call waits for a token from its operation's bucket, if Operations has
+// one, and from its service's bucket, from Services or else Default.
+// Waiting respects the caller's context.
+//
+// Example:
+//
+//	client := cloudsdk.New(provider, &cloudsdk.Config{
+//	    RateLimits: &cloudsdk.RateLimitConfig{
+//	        Services:   map[cloudsdk.ServiceType]cloudsdk.RateLimit{cloudsdk.ServiceCompute: {Rate: 20, Burst: 40}},
+//	        Operations: map[string]cloudsdk.RateLimit{cloudsdk.OpGetVM: {Rate: 5, Burst: 10}},
+//	    },
+//	})
+type RateLimitConfig struct {
+	// Default limits services that have no entry in Services.
+	// If nil, those services are not limited.
+	Default *RateLimit
+
+	// Services sets per-service limits.
+	Services map[ServiceType]RateLimit
+
+	// Operations sets per-operation limits keyed by operation name. They
+	// apply in addition to the service's limit.
+	Operations map[string]RateLimit
+}
+
+// RateLimitStats reports how much a rate limiter has delayed calls.
+type RateLimitStats struct {
+	// Service is the service a service-wide limiter applies to, or empty
+	// for a per-operation limiter.
+	Service ServiceType
+
+	// Operation is the operation a per-operation limiter applies to, or
+	// empty for a service-wide limiter.
+	Operation string
+
+	// Calls is the number of calls that asked the limiter for a token.
+	Calls int64
+
+	// Waits is the number of calls that had to wait for a token.
+	Waits int64
+
+	// TotalWait is the total time calls spent waiting.
+	TotalWait time.Duration
+
+	// MaxWait is the longest time a single call waited.
+	MaxWait time.Duration
+
+	// Rejected is the number of calls whose context ended, or would have
+	// ended, before a token was available.
+	Rejected int64
+}
+
+// tokenBucket is a token bucket rate limiter. Tokens are reserved ahead of
+// time, so waiting callers are served in order.
+type tokenBucket struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	stats  RateLimitStats
+}
+
+func newTokenBucket(service ServiceType, operation string, limit RateLimit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+		stats:  RateLimitStats{Service: service, Operation: operation},
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	if b.tokens > float64(b.limit.Burst) {
+		b.tokens = float64(b.limit.Burst)
+	}
+	b.last = now
+	b.tokens--
+	b.stats.Calls++
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that won't be used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	b.stats.Rejected++
+}
+
+// record adds a completed wait to the stats.
+func (b *tokenBucket) record(wait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stats.Waits++
+	b.stats.TotalWait += wait
+	if wait > b.stats.MaxWait {
+		b.stats.MaxWait = wait
+	}
+}
+
+// wait blocks until a token is available or ctx ends.
+func (b *tokenBucket) wait(ctx context.Context, call *Call) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		b.cancel()
+		return newRateLimitWaitError(call, delay, context.DeadlineExceeded)
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return newRateLimitWaitError(call, delay-time.Since(start), ctx.Err())
+	case <-timer.C:
+		b.record(time.Since(start))
+		return nil
+	}
+}
+
+func (b *tokenBucket) snapshot() RateLimitStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// newRateLimitWaitError reports a call that gave up waiting for the Client's rate limiter.
+func newRateLimitWaitError(call *Call, retryAfter time.Duration, cause error) *CloudError {
+	err := NewCloudError(ErrRateLimit,
+		fmt.Sprintf("Client rate limit: context ended before operation '%s' could start", call.Operation),
+		call.Provider, string(call.Service), call.Operation).
+		WithCause(cause).
+		WithSuggestions(
+			"Allow a longer context deadline for rate-limited operations",
+			"Raise the limit in Config.RateLimits if the provider's quota allows it",
+		)
+	err.RetryAfter = retryAfter
+	return err
+}
+
+// rateLimiter holds the Client's token buckets.
+type rateLimiter struct {
+	services   map[ServiceType]*tokenBucket
+	operations map[string]*tokenBucket
+	fallback   *RateLimit
+
+	mu sync.Mutex
+}
+
+func newRateLimiter(config *RateLimitConfig) *rateLimiter {
+	if config == nil {
+		return nil
+	}
+	limiter := &rateLimiter{
+		services:   make(map[ServiceType]*tokenBucket, len(config.Services)),
+		operations: make(map[string]*tokenBucket, len(config.Operations)),
+	}
+	if config.Default != nil && config.Default.Rate > 0 {
+		limiter.fallback = config.Default
+	}
+	for service, limit := range config.Services {
+		if limit.Rate > 0 {
+			limiter.services[service] = newTokenBucket(service, "", limit)
+		}
+	}
+	for operation, limit := range config.Operations {
+		if limit.Rate > 0 {
+			limiter.operations[operation] = newTokenBucket("", operation, limit)
+		}
+	}
+	return limiter
+}
+
+// serviceBucket returns the bucket for service, creating it from Default on
+// first use, or nil if the service isn't limited.
+func (l *rateLimiter) serviceBucket(service ServiceType) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket, ok := l.services[service]
+	if !ok && l.fallback != nil {
+		bucket = newTokenBucket(service, "", *l.fallback)
+		l.services[service] = bucket
+	}
+	return bucket
+}
+
+// wait blocks until the call may be sent to the provider. If the call gets
+// an operation token but gives up waiting for the service's, the operation
+// token is returned so calls that never ran don't use up the operation's
+// limit.
+func (l *rateLimiter) wait(ctx context.Context, call *Call) error {
+	operationBucket, ok := l.operations[call.Operation]
+	if ok {
+		if err := operationBucket.wait(ctx, call); err != nil {
+			return err
+		}
+	}
+	if bucket := l.serviceBucket(call.Service); bucket != nil {
+		if err := bucket.wait(ctx, call); err != nil {
+			if operationBucket != nil {
+				operationBucket.cancel()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// RateLimitStats returns the wait-time stats of each of the Client's rate
+// limiters, service-wide limiters first. It returns nil when the Client has
+// no RateLimitConfig.
+func (c *Client) RateLimitStats() []RateLimitStats {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	var stats []RateLimitStats
+	c.rateLimiter.mu.Lock()
+	for _, bucket := range c.rateLimiter.services {
+		stats = append(stats, bucket.snapshot())
+	}
+	c.rateLimiter.mu.Unlock()
+	for _, bucket := range c.rateLimiter.operations {
+		stats = append(stats, bucket.snapshot())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Service != stats[j].Service {
+			// Per-operation limiters have no service and sort last
+			return stats[j].Service == "" || (stats[i].Service != "" && stats[i].Service < stats[j].Service)
+		}
+		return stats[i].Operation < stats[j].Operation
+	})
+	return stats
+}
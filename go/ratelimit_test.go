@@ -0,0 +1,91 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestClientRateLimits(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		RateLimits: &cloudsdk.RateLimitConfig{
+			Default:    &cloudsdk.RateLimit{Rate: 1000, Burst: 100},
+			Operations: map[string]cloudsdk.RateLimit{cloudsdk.OpListVMs: {Rate: 50, Burst: 1}},
+		},
+	})
+
+	// One call per 20ms after the first
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.Compute().ListVMs(ctx)
+		helper.AssertNoError(err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("three calls at 50/s took %v, expected at least 30ms", elapsed)
+	}
+
+	// Callers whose deadline can't be met fail fast without calling the provider
+	shortCtx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	_, err := client.Compute().ListVMs(shortCtx)
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the error to wrap context.DeadlineExceeded, got %v", err)
+	}
+	helper.AssertEqual(3, provider.CallCount("ListVMs"))
+
+	stats := client.RateLimitStats()
+	helper.AssertEqual(2, len(stats))
+	helper.AssertEqual(cloudsdk.ServiceCompute, stats[0].Service)
+	helper.AssertEqual(int64(3), stats[0].Calls)
+	helper.AssertEqual(int64(0), stats[0].Waits)
+
+	helper.AssertEqual(cloudsdk.OpListVMs, stats[1].Operation)
+	helper.AssertEqual(int64(4), stats[1].Calls)
+	helper.AssertEqual(int64(2), stats[1].Waits)
+	helper.AssertEqual(int64(1), stats[1].Rejected)
+	if stats[1].TotalWait < 20*time.Millisecond || stats[1].MaxWait > stats[1].TotalWait {
+		t.Errorf("unexpected wait stats: %+v", stats[1])
+	}
+}
+
+func TestClientRateLimitsReturnOperationTokens(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{
+		RateLimits: &cloudsdk.RateLimitConfig{
+			Services:   map[cloudsdk.ServiceType]cloudsdk.RateLimit{cloudsdk.ServiceCompute: {Rate: 20, Burst: 1}},
+			Operations: map[string]cloudsdk.RateLimit{cloudsdk.OpListVMs: {Rate: 0.1, Burst: 1}},
+		},
+	})
+
+	// Use up the service's token
+	_, err := client.Compute().ListVMsWithOptions(ctx, nil)
+	helper.AssertNoError(err)
+
+	// ListVMs gets its operation token but can't wait 50ms for the
+	// service's, so it gives the operation token back
+	shortCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Compute().ListVMs(shortCtx)
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+
+	// The next ListVMs only waits for the service, not 10s for another
+	// operation token
+	longCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = client.Compute().ListVMs(longCtx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, provider.CallCount("ListVMs"))
+}
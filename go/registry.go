@@ -0,0 +1,175 @@
+package cloudsdk
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Environment variables read by OpenFromEnv.
+const (
+	// EnvProvider selects the provider, either as a bare name ("aws", "mock")
+	// or as a full provider URL ("aws://us-east-1?profile=prod").
+	EnvProvider = "CLOUDSDK_PROVIDER"
+
+	// EnvRegion sets the region when EnvProvider doesn't include one.
+	EnvRegion = "CLOUDSDK_REGION"
+)
+
+// ProviderFactory builds a Provider from the parts of a provider URL.
+// For "aws://us-east-1?profile=prod&timeout=30s" the factory receives
+// region "us-east-1" and params {"profile": ["prod"], "timeout": ["30s"]}.
+//
+// Factories should reject unknown parameters with an ErrInvalidConfig error
+// so that typos in configuration are caught at startup.
+type ProviderFactory func(region string, params url.Values) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ProviderFactory)
+)
+
+// Register makes a provider available by name to Open and OpenFromEnv.
+// Provider packages call it from their init function, so importing a provider
+// package (even as a blank import) is enough to make it available:
+//
+//	import _ "github.com/VAIBHAVSING/Cloudsdk/go/providers/aws"
+//
+// Names must be lowercase, as Open lowercases the URL scheme before looking
+// the provider up. Register panics if name is empty or not lowercase, factory
+// is nil, or the name is already registered.
+func Register(name string, factory ProviderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" {
+		panic("cloudsdk: Register provider name is empty")
+	}
+	if name != strings.ToLower(name) {
+		panic("cloudsdk: Register provider name is not lowercase: " + name)
+	}
+	if factory == nil {
+		panic("cloudsdk: Register factory is nil for provider " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("cloudsdk: Register called twice for provider " + name)
+	}
+	registry[name] = factory
+}
+
+// Providers returns a sorted list of the names of the registered providers.
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenProvider creates a Provider from a provider URL of the form
+// "name://region?param=value". The region may be omitted ("mock://")
+// when the provider has a sensible default.
+//
+// Example:
+//
+//	provider, err := cloudsdk.OpenProvider("aws://us-east-1?profile=prod&timeout=30s")
+func OpenProvider(rawURL string) (Provider, error) {
+	name, region, params, err := parseProviderURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, NewCloudError(
+			ErrInvalidConfig,
+			fmt.Sprintf("Unknown provider '%s'", name),
+			name, "", "Open",
+		).WithSuggestions(
+			fmt.Sprintf("Registered providers: %s", strings.Join(Providers(), ", ")),
+			"Import the provider package so it registers itself, e.g. _ \"github.com/VAIBHAVSING/Cloudsdk/go/providers/aws\"",
+			"Check the spelling of the provider name in the URL",
+		)
+	}
+
+	return factory(region, params)
+}
+
+// Open creates a Client from a provider URL. It is equivalent to calling
+// OpenProvider followed by NewFromProvider.
+//
+// Example:
+//
+//	client, err := cloudsdk.Open("aws://us-east-1?profile=prod&timeout=30s")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	vms, err := client.Compute().ListVMs(ctx)
+func Open(rawURL string) (*Client, error) {
+	provider, err := OpenProvider(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromProvider(provider), nil
+}
+
+// OpenFromEnv creates a Client from the CLOUDSDK_PROVIDER environment variable.
+// The variable may hold a full provider URL or a bare provider name; in the
+// latter case CLOUDSDK_REGION, if set, supplies the region. This lets the same
+// binary run against the mock provider in CI and a real cloud in production:
+//
+//	CLOUDSDK_PROVIDER=mock ./app
+//	CLOUDSDK_PROVIDER=aws CLOUDSDK_REGION=eu-west-1 ./app
+//	CLOUDSDK_PROVIDER="aws://us-east-1?profile=prod" ./app
+func OpenFromEnv() (*Client, error) {
+	value := strings.TrimSpace(os.Getenv(EnvProvider))
+	if value == "" {
+		return nil, NewCloudError(
+			ErrInvalidConfig,
+			fmt.Sprintf("Environment variable %s is not set", EnvProvider),
+			"", "", "OpenFromEnv",
+		).WithSuggestions(
+			fmt.Sprintf("Set %s to a provider name such as 'aws' or 'mock'", EnvProvider),
+			fmt.Sprintf("Set %s to a provider URL such as 'aws://us-east-1?profile=prod'", EnvProvider),
+		)
+	}
+
+	if !strings.Contains(value, "://") {
+		value = value + "://" + url.PathEscape(strings.TrimSpace(os.Getenv(EnvRegion)))
+	}
+
+	return Open(value)
+}
+
+// parseProviderURL splits a provider URL into its name, region and parameters.
+func parseProviderURL(rawURL string) (string, string, url.Values, error) {
+	invalid := func(reason string) error {
+		return NewInvalidConfigError("", "", "url", reason).WithSuggestions(
+			"Use the form 'provider://region?param=value', e.g. 'aws://us-east-1?profile=prod'",
+			"Use 'mock://' for the in-memory mock provider",
+		)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", nil, invalid(err.Error())
+	}
+	if u.Scheme == "" {
+		return "", "", nil, invalid(fmt.Sprintf("missing provider name in '%s'", rawURL))
+	}
+	if u.Path != "" && u.Path != "/" {
+		return "", "", nil, invalid(fmt.Sprintf("unexpected path '%s' in provider URL", u.Path))
+	}
+
+	return strings.ToLower(u.Scheme), u.Host, u.Query(), nil
+}
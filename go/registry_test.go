@@ -0,0 +1,105 @@
+package cloudsdk_test
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestOpenMockProvider(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	client, err := cloudsdk.Open("mock://eu-west-1?services=compute,storage")
+	helper.AssertNoError(err)
+
+	helper.AssertEqual(true, client.Supports(cloudsdk.ServiceCompute))
+	helper.AssertEqual(true, client.Supports(cloudsdk.ServiceStorage))
+	helper.AssertEqual(false, client.Supports(cloudsdk.ServiceDatabase))
+
+	provider, err := cloudsdk.OpenProvider("mock://")
+	helper.AssertNoError(err)
+	helper.AssertEqual("us-east-1", provider.Region())
+	helper.AssertEqual("mock", provider.Name())
+}
+
+func TestOpenErrors(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	invalidURLs := []string{
+		"",
+		"us-east-1",
+		"nosuchcloud://us-east-1",
+		"mock://us-east-1/extra/path",
+		"mock://us-east-1?services=teleporter",
+		"mock://us-east-1?colour=blue",
+	}
+
+	for _, rawURL := range invalidURLs {
+		_, err := cloudsdk.Open(rawURL)
+		helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	}
+}
+
+func TestOpenFromEnv(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	t.Setenv(cloudsdk.EnvProvider, "")
+	_, err := cloudsdk.OpenFromEnv()
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	t.Setenv(cloudsdk.EnvProvider, "mock")
+	t.Setenv(cloudsdk.EnvRegion, "ap-south-1")
+	client, err := cloudsdk.OpenFromEnv()
+	helper.AssertNoError(err)
+	_, err = client.TryCompute()
+	helper.AssertNoError(err)
+
+	t.Setenv(cloudsdk.EnvProvider, "mock://?services=storage")
+	client, err = cloudsdk.OpenFromEnv()
+	helper.AssertNoError(err)
+	helper.AssertEqual(false, client.Supports(cloudsdk.ServiceCompute))
+}
+
+// registerRuns counts TestRegister runs. The registry is global and can't be
+// reset, so each run, e.g. with -count, registers a provider of its own.
+var registerRuns atomic.Int32
+
+func TestRegister(t *testing.T) {
+	name := fmt.Sprintf("registrytest%d", registerRuns.Add(1))
+	factory := func(region string, params url.Values) (cloudsdk.Provider, error) {
+		return mock.New(region + "-custom"), nil
+	}
+	cloudsdk.Register(name, factory)
+
+	helper := cloudsdktesting.NewTestHelper(t)
+	provider, err := cloudsdk.OpenProvider(name + "://us-west-2")
+	helper.AssertNoError(err)
+	helper.AssertEqual("us-west-2-custom", provider.Region())
+
+	found := false
+	for _, registered := range cloudsdk.Providers() {
+		if registered == name {
+			found = true
+		}
+	}
+	helper.AssertEqual(true, found)
+
+	cloudsdktesting.MustPanic(t, func() {
+		cloudsdk.Register(name, factory)
+	})
+	cloudsdktesting.MustPanic(t, func() {
+		cloudsdk.Register("", factory)
+	})
+	cloudsdktesting.MustPanic(t, func() {
+		cloudsdk.Register("nilfactory", nil)
+	})
+	// Open lowercases schemes, so a mixed-case name could never be opened
+	cloudsdktesting.MustPanic(t, func() {
+		cloudsdk.Register("MixedCase", factory)
+	})
+}
@@ -0,0 +1,90 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestRebootVM(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-server"))
+	helper.AssertNoError(err)
+	helper.AssertNoError(client.Compute().RebootVM(ctx, vm.ID))
+
+	// Only running VMs can be rebooted
+	helper.AssertNoError(client.Compute().StopVM(ctx, vm.ID))
+	helper.AssertErrorCode(client.Compute().RebootVM(ctx, vm.ID), cloudsdk.ErrInvalidState)
+	helper.AssertErrorCode(client.Compute().RebootVM(ctx, "i-missing"), cloudsdk.ErrResourceNotFound)
+}
+
+func TestResizeVM(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, nil)
+
+	config := cloudsdktesting.GenerateVMConfig("web-server")
+	config.InstanceType = "t3.micro"
+	vm, err := client.Compute().CreateVM(ctx, config)
+	helper.AssertNoError(err)
+
+	// A running VM is resized and running again afterwards
+	helper.AssertNoError(client.Compute().ResizeVM(ctx, vm.ID, "t3.large"))
+	vm, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual("t3.large", vm.InstanceType)
+	helper.AssertEqual("running", vm.State)
+
+	// A stopped VM stays stopped
+	helper.AssertNoError(client.Compute().StopVM(ctx, vm.ID))
+	helper.AssertNoError(client.Compute().ResizeVM(ctx, vm.ID, "t3.medium"))
+	vm, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual("stopped", vm.State)
+	helper.AssertEqual("t3.medium", vm.InstanceType)
+	helper.AssertNoError(client.Compute().StartVM(ctx, vm.ID))
+
+	helper.AssertErrorCode(client.Compute().ResizeVM(ctx, vm.ID, ""), cloudsdk.ErrInvalidConfig)
+
+	// A failed step is rolled back
+	capacityErr := cloudsdk.NewCloudError(cloudsdk.ErrResourceConflict, "Insufficient capacity", "mock", "compute", cloudsdk.OpResizeVM)
+	provider.WithError("ResizeVM/"+services.ResizeStepStart, capacityErr)
+	err = client.Compute().ResizeVM(ctx, vm.ID, "p4d.24xlarge")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	var resizeErr *services.ResizeError
+	if !errors.As(err, &resizeErr) {
+		t.Fatalf("expected *services.ResizeError, got %T", err)
+	}
+	helper.AssertEqual(services.ResizeStepStart, resizeErr.Step)
+	helper.AssertEqual(true, resizeErr.RolledBack)
+	vm, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual("t3.medium", vm.InstanceType)
+	helper.AssertEqual("running", vm.State)
+
+	// and a failed rollback leaves the VM as the failed step did
+	provider.WithError("ResizeVM/rollback", errors.New("rollback failed"))
+	err = client.Compute().ResizeVM(ctx, vm.ID, "p4d.24xlarge")
+	if !errors.As(err, &resizeErr) {
+		t.Fatalf("expected *services.ResizeError, got %T", err)
+	}
+	helper.AssertEqual(false, resizeErr.RolledBack)
+	vm, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual("stopped", vm.State)
+
+	// Resizes are planned, not made, in dry-run mode
+	dryRun := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+	err = dryRun.Compute().ResizeVM(ctx, vm.ID, "t3.large")
+	helper.AssertErrorCode(err, cloudsdk.ErrDryRun)
+	helper.AssertEqual(cloudsdk.OpResizeVM, dryRun.Plan()[0].Operation)
+}
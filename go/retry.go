@@ -0,0 +1,377 @@
+package cloudsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Jitter selects how Retry randomizes the delay between attempts.
+type Jitter int
+
+const (
+	// JitterNone waits exactly the exponential backoff delay.
+	JitterNone Jitter = iota
+
+	// JitterFull waits a random delay between zero and the backoff delay.
+	JitterFull
+
+	// JitterDecorrelated waits a random delay between InitialDelay and three
+	// times the previous delay, capped at MaxDelay.
+	JitterDecorrelated
+)
+
+// RetryPolicy controls how failed provider calls are retried.
+//
+// Providers retry with their own default policy. Set Config.RetryPolicy or
+// Config.RetryPolicies to override it for a Client, or ContextWithRetryPolicy
+// for a single call:
+//
+//	client := cloudsdk.New(provider, &cloudsdk.Config{
+//	    RetryPolicies: map[string]cloudsdk.RetryPolicy{
+//	        cloudsdk.OpCreateVM: cloudsdk.NoRetry, // not idempotent
+//	    },
+//	})
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 are treated as 1.
+	MaxAttempts int
+
+	// InitialDelay is the backoff delay before the first retry.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the backoff delay. Server-supplied retry-after hints may
+	// exceed it.
+	MaxDelay time.Duration
+
+	// BackoffFactor multiplies the delay after each retry. Values below 1 are
+	// treated as 1.
+	BackoffFactor float64
+
+	// Jitter randomizes the delay so that clients throttled together don't
+	// retry together.
+	Jitter Jitter
+}
+
+// DefaultRetryPolicy is the policy providers use unless configured otherwise.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   3,
+	InitialDelay:  100 * time.Millisecond,
+	MaxDelay:      5 * time.Second,
+	BackoffFactor: 2.0,
+	Jitter:        JitterFull,
+}
+
+// NoRetry makes a single attempt. Use it for operations that are not safe to
+// repeat, such as OpCreateVM.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// backoff returns the delay before retry number retry (1 for the first
+// retry), given the delay used before the previous one.
+func (p RetryPolicy) backoff(retry int, previous time.Duration) time.Duration {
+	factor := p.BackoffFactor
+	if factor < 1 {
+		factor = 1
+	}
+	delay := float64(p.InitialDelay) * math.Pow(factor, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	switch p.Jitter {
+	case JitterFull:
+		delay = rand.Float64() * delay
+	case JitterDecorrelated:
+		if previous < p.InitialDelay {
+			previous = p.InitialDelay
+		}
+		upper := float64(previous) * 3
+		delay = float64(p.InitialDelay) + rand.Float64()*(upper-float64(p.InitialDelay))
+		if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+			delay = float64(p.MaxDelay)
+		}
+	}
+	return time.Duration(delay)
+}
+
+// DefaultRetryBudgetCapacity is the capacity of the retry budget each Client
+// creates when Config.RetryBudget is nil.
+const DefaultRetryBudgetCapacity = 500
+
+const (
+	// retryCost is withdrawn from a RetryBudget for every retry.
+	retryCost = 5
+
+	// successRefund is deposited for every call that succeeds first time.
+	successRefund = 1
+)
+
+// RetryBudget limits how many retries a Client makes across all of its
+// operations, so that a provider outage doesn't multiply the load on it.
+//
+// Every retry costs 5 tokens and every call that succeeds on its first
+// attempt returns 1; a call that succeeds after retrying gets its tokens
+// back. When the budget is empty, failed calls return their error instead
+// of retrying.
+type RetryBudget struct {
+	mu       sync.Mutex
+	tokens   int
+	capacity int
+}
+
+// NewRetryBudget returns a full budget holding capacity tokens.
+func NewRetryBudget(capacity int) *RetryBudget {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &RetryBudget{tokens: capacity, capacity: capacity}
+}
+
+// Available returns the number of tokens left in the budget.
+func (b *RetryBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+// withdraw takes n tokens, reporting false if the budget can't cover them.
+func (b *RetryBudget) withdraw(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+// deposit returns n tokens, up to the budget's capacity.
+func (b *RetryBudget) deposit(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += n
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+type (
+	retryPolicyKey struct{}
+	retryBudgetKey struct{}
+)
+
+// ContextWithRetryPolicy returns a copy of ctx that makes providers retry
+// with policy instead of their default. It takes precedence over
+// Config.RetryPolicy and Config.RetryPolicies.
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// RetryPolicyFromContext returns the retry policy carried in ctx, if any.
+func RetryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy)
+	return policy, ok
+}
+
+// withRetry returns a context carrying the Client's retry policy for
+// operation and its retry budget.
+func (c *Client) withRetry(ctx context.Context, operation string) context.Context {
+	if _, ok := RetryPolicyFromContext(ctx); !ok {
+		if policy, ok := c.config.RetryPolicies[operation]; ok {
+			ctx = ContextWithRetryPolicy(ctx, policy)
+		} else if c.config.RetryPolicy != nil {
+			ctx = ContextWithRetryPolicy(ctx, *c.config.RetryPolicy)
+		}
+	}
+	if c.retryBudget != nil {
+		ctx = context.WithValue(ctx, retryBudgetKey{}, c.retryBudget)
+	}
+	return ctx
+}
+
+// RetryAttempt describes one attempt made by Retry.
+type RetryAttempt struct {
+	// Number is the attempt number, starting at 1.
+	Number int
+
+	// Duration is how long the attempt took.
+	Duration time.Duration
+
+	// Err is the error the attempt returned, or nil on success.
+	Err error
+
+	// Delay is how long Retry waits before the next attempt, or zero if it
+	// is returning.
+	Delay time.Duration
+}
+
+// RetryOptions customize Retry for a provider.
+type RetryOptions struct {
+	// Retryable reports whether err may be retried. Defaults to IsRetryable.
+	Retryable func(err error) bool
+
+	// RetryAfter returns a server-supplied delay hint for err, or zero.
+	// Defaults to RetryAfterHint.
+	RetryAfter func(err error) time.Duration
+
+	// OnAttempt is called after every attempt, for logging.
+	OnAttempt func(ctx context.Context, attempt RetryAttempt)
+}
+
+// Retry calls fn until it succeeds, returns an error that isn't retryable,
+// or runs out of attempts, retry budget or time. It returns the last error.
+// If ctx ends while waiting to retry, the last error is wrapped with ctx's
+// error, so both can be matched with errors.Is and errors.As.
+//
+// policy is the provider's default; a policy carried in ctx (see
+// ContextWithRetryPolicy) replaces it. The wait before each retry is the
+// policy's backoff delay or the error's retry-after hint, whichever is
+// longer. Retries are reported through RecordRetry and charged to the
+// Client's RetryBudget.
+//
+// Example:
+//
+//	err := cloudsdk.Retry(ctx, cloudsdk.DefaultRetryPolicy, cloudsdk.RetryOptions{},
+//	    func(ctx context.Context, attempt int) error {
+//	        return callAPI(ctx)
+//	    })
+func Retry(ctx context.Context, policy RetryPolicy, opts RetryOptions, fn func(ctx context.Context, attempt int) error) error {
+	if override, ok := RetryPolicyFromContext(ctx); ok {
+		policy = override
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = IsRetryable
+	}
+	if opts.RetryAfter == nil {
+		opts.RetryAfter = RetryAfterHint
+	}
+	budget, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var delay time.Duration
+	withdrawn := 0
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := fn(ctx, attempt)
+		report := RetryAttempt{Number: attempt, Duration: time.Since(start), Err: err}
+
+		if err == nil {
+			if budget != nil {
+				if withdrawn > 0 {
+					budget.deposit(withdrawn)
+				} else {
+					budget.deposit(successRefund)
+				}
+			}
+			notifyAttempt(ctx, opts, report)
+			return nil
+		}
+
+		if attempt >= maxAttempts || !opts.Retryable(err) || ctx.Err() != nil {
+			notifyAttempt(ctx, opts, report)
+			return err
+		}
+
+		delay = policy.backoff(attempt, delay)
+		if hint := opts.RetryAfter(err); hint > delay {
+			delay = hint
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// The context would expire before the next attempt starts
+			notifyAttempt(ctx, opts, report)
+			return err
+		}
+		if budget != nil {
+			if !budget.withdraw(retryCost) {
+				notifyAttempt(ctx, opts, report)
+				return err
+			}
+			withdrawn += retryCost
+		}
+
+		report.Delay = delay
+		notifyAttempt(ctx, opts, report)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			// Keep the error being retried, so callers see why the call
+			// failed as well as that it was abandoned
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+		RecordRetry(ctx)
+	}
+}
+
+func notifyAttempt(ctx context.Context, opts RetryOptions, attempt RetryAttempt) {
+	if opts.OnAttempt != nil {
+		opts.OnAttempt(ctx, attempt)
+	}
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// a rate limit or network timeout CloudError, an HTTP 429 or 5xx response,
+// a network timeout, or an error that says so through a RetryableError()
+// method. Cancelled and expired contexts are never retryable. Providers pass
+// their own classifier to Retry to recognize their error codes.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var explicit interface{ RetryableError() bool }
+	if errors.As(err, &explicit) {
+		return explicit.RetryableError()
+	}
+
+	var cloudErr *CloudError
+	if errors.As(err, &cloudErr) {
+		switch cloudErr.Code {
+		case ErrRateLimit, ErrNetworkTimeout:
+			return true
+		}
+	}
+
+	var withStatus interface{ HTTPStatusCode() int }
+	if errors.As(err, &withStatus) {
+		switch withStatus.HTTPStatusCode() {
+		case 429, 500, 502, 503, 504:
+			return true
+		}
+	}
+
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// RetryAfterHint returns the delay a server asked for before retrying err:
+// CloudError.RetryAfter, or the result of a RetryAfter() time.Duration
+// method. It returns zero when there is no hint.
+func RetryAfterHint(err error) time.Duration {
+	var cloudErr *CloudError
+	if errors.As(err, &cloudErr) && cloudErr.RetryAfter > 0 {
+		return cloudErr.RetryAfter
+	}
+	var hinted interface{ RetryAfter() time.Duration }
+	if errors.As(err, &hinted) {
+		return hinted.RetryAfter()
+	}
+	return 0
+}
@@ -0,0 +1,198 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// fastRetry retries quickly enough for tests.
+var fastRetry = cloudsdk.RetryPolicy{
+	MaxAttempts:   5,
+	InitialDelay:  time.Millisecond,
+	MaxDelay:      5 * time.Millisecond,
+	BackoffFactor: 2,
+	Jitter:        cloudsdk.JitterFull,
+}
+
+// throttledCompute fails GetVM with a rate limit error until failures run
+// out, retrying through cloudsdk.Retry the way providers do.
+type throttledCompute struct {
+	services.Compute
+	failures int
+	calls    int
+}
+
+func (c *throttledCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
+	err := cloudsdk.Retry(ctx, fastRetry, cloudsdk.RetryOptions{}, func(ctx context.Context, attempt int) error {
+		c.calls++
+		if c.calls <= c.failures {
+			return cloudsdk.NewRateLimitError("mock", "compute", "GetVM", 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &services.VM{ID: id}, nil
+}
+
+type throttledProvider struct {
+	*mock.MockProvider
+	compute *throttledCompute
+}
+
+func (p *throttledProvider) Compute() services.Compute {
+	return p.compute
+}
+
+func newThrottledProvider(failures int) *throttledProvider {
+	provider := mock.New("us-east-1")
+	return &throttledProvider{
+		MockProvider: provider,
+		compute:      &throttledCompute{Compute: provider.Compute(), failures: failures},
+	}
+}
+
+func TestRetry(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	calls := 0
+	err := cloudsdk.Retry(ctx, fastRetry, cloudsdk.RetryOptions{}, func(ctx context.Context, attempt int) error {
+		calls++
+		helper.AssertEqual(calls, attempt)
+		if attempt < 3 {
+			return cloudsdk.NewRateLimitError("mock", "compute", "GetVM", 0)
+		}
+		return nil
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual(3, calls)
+
+	// Errors that aren't transient are returned straight away
+	calls = 0
+	err = cloudsdk.Retry(ctx, fastRetry, cloudsdk.RetryOptions{}, func(ctx context.Context, attempt int) error {
+		calls++
+		return cloudsdk.NewInvalidConfigError("mock", "compute", "ImageID", "image ID is required")
+	})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	helper.AssertEqual(1, calls)
+
+	// The last error is returned once attempts run out
+	var attempts []cloudsdk.RetryAttempt
+	err = cloudsdk.Retry(ctx, fastRetry, cloudsdk.RetryOptions{
+		OnAttempt: func(ctx context.Context, attempt cloudsdk.RetryAttempt) {
+			attempts = append(attempts, attempt)
+		},
+	}, func(ctx context.Context, attempt int) error {
+		return context.DeadlineExceeded
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	helper.AssertEqual(1, len(attempts))
+	helper.AssertEqual(time.Duration(0), attempts[0].Delay)
+
+	// A context that ends while waiting to retry keeps the last error
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	err = cloudsdk.Retry(cancelCtx, fastRetry, cloudsdk.RetryOptions{
+		OnAttempt: func(ctx context.Context, attempt cloudsdk.RetryAttempt) {
+			cancel()
+		},
+	}, func(ctx context.Context, attempt int) error {
+		return cloudsdk.NewRateLimitError("mock", "compute", "GetVM", time.Hour)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+}
+
+func TestRetryAfterHint(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	err := cloudsdk.NewRateLimitError("mock", "compute", "GetVM", 30*time.Millisecond)
+	helper.AssertEqual(30*time.Millisecond, err.RetryAfter)
+	helper.AssertEqual(30*time.Millisecond, cloudsdk.RetryAfterHint(err))
+
+	var delays []time.Duration
+	start := time.Now()
+	retryErr := cloudsdk.Retry(context.Background(), fastRetry, cloudsdk.RetryOptions{
+		OnAttempt: func(ctx context.Context, attempt cloudsdk.RetryAttempt) {
+			delays = append(delays, attempt.Delay)
+		},
+	}, func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			return err
+		}
+		return nil
+	})
+	helper.AssertNoError(retryErr)
+	helper.AssertEqual(30*time.Millisecond, delays[0])
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("retried after %v, before the 30ms hint", elapsed)
+	}
+}
+
+func TestClientRetryPolicies(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	// The provider's policy applies by default
+	provider := newThrottledProvider(2)
+	client := cloudsdk.New(provider, nil)
+	_, err := client.Compute().GetVM(ctx, "i-1")
+	helper.AssertNoError(err)
+	helper.AssertEqual(3, provider.compute.calls)
+
+	// Per-operation policies replace it
+	provider = newThrottledProvider(2)
+	client = cloudsdk.New(provider, &cloudsdk.Config{
+		RetryPolicies: map[string]cloudsdk.RetryPolicy{cloudsdk.OpGetVM: cloudsdk.NoRetry},
+	})
+	_, err = client.Compute().GetVM(ctx, "i-1")
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+	helper.AssertEqual(1, provider.compute.calls)
+
+	// A policy in the context wins over the Client's
+	provider.compute.calls = 0
+	_, err = client.Compute().GetVM(cloudsdk.ContextWithRetryPolicy(ctx, fastRetry), "i-1")
+	helper.AssertNoError(err)
+	helper.AssertEqual(3, provider.compute.calls)
+}
+
+func TestClientRetryBudget(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	// Enough budget for two retries
+	budget := cloudsdk.NewRetryBudget(10)
+	provider := newThrottledProvider(100)
+	client := cloudsdk.New(provider, &cloudsdk.Config{RetryBudget: budget})
+
+	_, err := client.Compute().GetVM(ctx, "i-1")
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+	helper.AssertEqual(3, provider.compute.calls)
+	helper.AssertEqual(0, budget.Available())
+
+	// With the budget spent, failures are returned without retrying
+	_, err = client.Compute().GetVM(ctx, "i-1")
+	helper.AssertErrorCode(err, cloudsdk.ErrRateLimit)
+	helper.AssertEqual(4, provider.compute.calls)
+
+	// A success after retrying returns the tokens it used
+	budget = cloudsdk.NewRetryBudget(10)
+	provider = newThrottledProvider(1)
+	client = cloudsdk.New(provider, &cloudsdk.Config{RetryBudget: budget})
+	_, err = client.Compute().GetVM(ctx, "i-1")
+	helper.AssertNoError(err)
+	helper.AssertEqual(10, budget.Available())
+}
@@ -0,0 +1,183 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestSecurityGroupsCreateAndList(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), &cloudsdk.Config{
+		DefaultTags: map[string]string{"Team": "platform"},
+	})
+	groups := client.Compute().SecurityGroups()
+
+	web, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "web", Description: "Public web servers"})
+	helper.AssertNoError(err)
+	helper.AssertEqual("platform", web.Tags["Team"])
+	// A new group allows all outbound traffic
+	helper.AssertEqual(1, len(web.Rules))
+	helper.AssertEqual(services.FirewallRule{Direction: "egress", Protocol: "all", CIDR: "0.0.0.0/0"}, web.Rules[0])
+
+	_, err = groups.Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	// Names only need to be unique within a network
+	_, err = groups.Create(ctx, &services.SecurityGroupConfig{Name: "web", NetworkID: "vpc-0123456789abcdef0"})
+	helper.AssertNoError(err)
+
+	list, err := groups.List(ctx, &services.SecurityGroupFilter{Name: "web"})
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(list))
+	list, err = groups.List(ctx, &services.SecurityGroupFilter{NetworkID: "vpc-0123456789abcdef0"})
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(list))
+	list, err = groups.List(ctx, &services.SecurityGroupFilter{Tags: map[string]string{"Team": "data"}})
+	helper.AssertNoError(err)
+	helper.AssertEqual(0, len(list))
+}
+
+func TestSecurityGroupsRules(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	groups := client.Compute().SecurityGroups()
+
+	web, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	helper.AssertNoError(err)
+	db, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "db"})
+	helper.AssertNoError(err)
+
+	https := services.FirewallRule{Direction: "ingress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"}
+	postgres := services.FirewallRule{Direction: "ingress", Protocol: "tcp", FromPort: 5432, SourceGroupID: web.ID}
+	helper.AssertNoError(groups.AddRules(ctx, web.ID, []services.FirewallRule{https}))
+	helper.AssertNoError(groups.AddRules(ctx, db.ID, []services.FirewallRule{postgres}))
+
+	got, err := groups.Get(ctx, db.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(got.Rules))
+
+	// Adding a rule twice is a conflict, and adds none of the rules
+	err = groups.AddRules(ctx, web.ID, []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 80, CIDR: "0.0.0.0/0"},
+		https,
+	})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceConflict)
+	got, err = groups.Get(ctx, web.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(got.Rules))
+
+	// Rules naming a missing group are rejected
+	err = groups.AddRules(ctx, db.ID, []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 6379, SourceGroupID: "sg-00000000000000000"},
+	})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	err = groups.AddRules(ctx, "sg-00000000000000000", []services.FirewallRule{https})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	// Revoking matches every field but Description
+	https.Description = "HTTPS"
+	helper.AssertNoError(groups.RevokeRules(ctx, web.ID, []services.FirewallRule{https}))
+	got, err = groups.Get(ctx, web.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(got.Rules))
+	err = groups.RevokeRules(ctx, web.ID, []services.FirewallRule{https})
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+}
+
+func TestSecurityGroupsValidateRules(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	groups := client.Compute().SecurityGroups()
+
+	group, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	helper.AssertNoError(err)
+
+	err = groups.AddRules(ctx, group.ID, []services.FirewallRule{
+		{Direction: "inbound", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"},
+		{Direction: "ingress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0"},
+		{Direction: "ingress", Protocol: "tcp", FromPort: 8999, ToPort: 8000, CIDR: "10.0.0.0/8"},
+		{Direction: "ingress", Protocol: "icmp", FromPort: 8, CIDR: "10.0.0.0/8"},
+		{Direction: "ingress", Protocol: "udp", FromPort: 53, CIDR: "10.0.0.0/8", SourceGroupID: group.ID},
+	})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	var errs cloudsdk.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected cloudsdk.ValidationErrors, got %v", err)
+	}
+	rules := make(map[string]string, len(errs))
+	for _, fieldErr := range errs {
+		rules[fieldErr.Field] = fieldErr.Context.Metadata["rule"]
+	}
+	want := map[string]string{
+		"Rules[0].Direction": "oneof",
+		"Rules[1].CIDR":      "cidr",
+		"Rules[2].ToPort":    "port_range",
+		"Rules[3].FromPort":  "port_range",
+		"Rules[4]":           "peer",
+	}
+	if !reflect.DeepEqual(want, rules) {
+		t.Errorf("expected invalid fields %v, got %v", want, rules)
+	}
+
+	err = groups.AddRules(ctx, group.ID, nil)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+}
+
+func TestSecurityGroupsDelete(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	groups := client.Compute().SecurityGroups()
+
+	web, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	helper.AssertNoError(err)
+	db, err := groups.Create(ctx, &services.SecurityGroupConfig{Name: "db"})
+	helper.AssertNoError(err)
+	helper.AssertNoError(groups.AddRules(ctx, db.ID, []services.FirewallRule{
+		{Direction: "ingress", Protocol: "tcp", FromPort: 5432, SourceGroupID: web.ID},
+	}))
+
+	config := cloudsdktesting.GenerateVMConfig("web-server")
+	config.SecurityGroups = []string{web.ID}
+	vm, err := client.Compute().CreateVM(ctx, config)
+	helper.AssertNoError(err)
+
+	// The group is still used by the VM and by the db group's rule
+	helper.AssertErrorCode(groups.Delete(ctx, web.ID), cloudsdk.ErrDependencyViolation)
+	helper.AssertNoError(client.Compute().DeleteVM(ctx, vm.ID))
+	helper.AssertErrorCode(groups.Delete(ctx, web.ID), cloudsdk.ErrDependencyViolation)
+
+	helper.AssertNoError(groups.Delete(ctx, db.ID))
+	helper.AssertNoError(groups.Delete(ctx, web.ID))
+	helper.AssertErrorCode(groups.Delete(ctx, web.ID), cloudsdk.ErrResourceNotFound)
+	_, err = groups.Get(ctx, web.ID)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+}
+
+func TestSecurityGroupsDryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+
+	_, err := client.Compute().SecurityGroups().Create(ctx, &services.SecurityGroupConfig{Name: "web"})
+	if !errors.Is(err, cloudsdk.ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+	helper.AssertEqual(false, provider.WasCalled(cloudsdk.OpCreateSecurityGroup))
+	helper.AssertEqual(cloudsdk.OpCreateSecurityGroup, client.Plan()[0].Operation)
+
+	_, err = client.Compute().SecurityGroups().List(ctx, nil)
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, provider.WasCalled(cloudsdk.OpListSecurityGroups))
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// CallOptions changes how a single service call is made, without building a
+// new provider. They are carried in the call's context; see
+// ContextWithCallOptions.
+//
+// A cloudsdk.Client applies Timeout and Tags to calls made through it.
+// Providers apply Region, reading it with CallOptionsFromContext.
+type CallOptions struct {
+	// Timeout bounds the call, taking precedence over the Client's
+	// Config.DefaultTimeout and Config.Timeouts.
+	//
+	// Default: 0 (the Client's configured timeout applies)
+	Timeout time.Duration
+
+	// Region sends the call to another region of the same provider, e.g.
+	// to create one bucket in "eu-west-1" with a provider opened in
+	// "us-east-1". Resources created this way are placed in Region unless
+	// their config names a region itself.
+	//
+	// Default: empty (the provider's region)
+	Region string
+
+	// Tags are added to resources created by the call. Tags in the
+	// config take precedence over these, which in turn take precedence
+	// over the Client's Config.DefaultTags.
+	//
+	// Default: nil (no extra tags)
+	Tags map[string]string
+}
+
+// CallOption sets a field of CallOptions.
+type CallOption func(*CallOptions)
+
+// WithTimeout bounds a call to timeout.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithRegion sends a call to region instead of the provider's region.
+func WithRegion(region string) CallOption {
+	return func(o *CallOptions) {
+		o.Region = region
+	}
+}
+
+// WithTags adds tags to resources created by a call. Repeated WithTags
+// options are merged, later ones winning for the same key.
+func WithTags(tags map[string]string) CallOption {
+	return func(o *CallOptions) {
+		if len(tags) == 0 {
+			return
+		}
+		merged := make(map[string]string, len(o.Tags)+len(tags))
+		for key, value := range o.Tags {
+			merged[key] = value
+		}
+		for key, value := range tags {
+			merged[key] = value
+		}
+		o.Tags = merged
+	}
+}
+
+type callOptionsKey struct{}
+
+// ContextWithCallOptions returns a copy of ctx carrying opts, applied on top
+// of any options ctx already carries. Every service call made with the
+// returned context honors them:
+//
+//	ctx := services.ContextWithCallOptions(ctx,
+//	    services.WithTimeout(30*time.Minute),
+//	    services.WithTags(map[string]string{"request-id": requestID}),
+//	)
+//	db, err := client.Database().CreateDB(ctx, dbConfig)
+func ContextWithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	options := CallOptionsFromContext(ctx)
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, options)
+}
+
+// CallOptionsFromContext returns the CallOptions carried in ctx, or the zero
+// CallOptions if there are none.
+func CallOptionsFromContext(ctx context.Context) CallOptions {
+	options, _ := ctx.Value(callOptionsKey{}).(CallOptions)
+	return options
+}
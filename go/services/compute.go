@@ -1,6 +1,9 @@
 package services
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // VMConfig represents the configuration for creating a virtual machine.
 // All fields are validated before creating the VM to ensure proper configuration.
@@ -59,6 +62,10 @@ type VMConfig struct {
 	//   - AWS: ami-0abcdef1234567890 (Amazon Linux 2), ami-0987654321fedcba0 (Ubuntu 20.04)
 	//   - GCP: "projects/ubuntu-os-cloud/global/images/family/ubuntu-2004-lts"
 	//   - Azure: "Canonical:0001-com-ubuntu-server-focal:20_04-lts-gen2:latest"
+	//
+	// Image IDs differ between regions and are replaced by new releases; use
+	// Compute().Images().Latest to look up the current image by name instead
+	// of hardcoding its ID.
 	ImageID string `json:"image_id" yaml:"image_id" validate:"required"`
 
 	// InstanceType specifies the hardware configuration for the VM.
@@ -88,7 +95,7 @@ type VMConfig struct {
 	// Leave empty if using other authentication methods (passwords, certificates)
 	//
 	// Key Management:
-	//   - AWS: Key pairs are region-specific, create via EC2 console or CLI
+	//   - AWS: Key pairs are region-specific, create via Compute().KeyPairs()
 	//   - GCP: Uses project-wide SSH keys or instance-specific keys
 	//   - Azure: Can use SSH keys or passwords for authentication
 	//
@@ -163,7 +170,9 @@ type VMConfig struct {
 	//   - Monitoring: "enabled", "disabled"
 	//
 	// Provider-Specific Notes:
-	//   - AWS: Tags are case-sensitive, support resource-based policies
+	//   - AWS: Tags are case-sensitive, support resource-based policies.
+	//     Applied to the instance and its volumes at launch, together with a
+	//     "Name" tag set to Name unless Tags has one
 	//   - GCP: Called "labels", lowercase keys/values only
 	//   - Azure: Called "tags", case-insensitive
 	//
@@ -285,6 +294,19 @@ type VMConfig struct {
 	//
 	// Default: false (not EBS optimized)
 	EbsOptimized *bool `json:"ebs_optimized,omitempty" yaml:"ebs_optimized,omitempty"`
+
+	// IdempotencyKey makes CreateVM safe to retry. Repeating a create with
+	// the same key and configuration returns the VM the first call created
+	// instead of launching another one; reusing a key with a different
+	// configuration fails with ErrResourceConflict.
+	//
+	// Use a key derived from the work being done, e.g. "deploy-42-web-1", so
+	// that application-level retries send the same key. Maps to the EC2
+	// ClientToken on AWS. Keys are kept by the provider for a limited time
+	// (at least 24 hours on AWS).
+	//
+	// Default: empty (the provider's own retries are still idempotent)
+	IdempotencyKey string `json:"idempotency_key,omitempty" yaml:"idempotency_key,omitempty" validate:"max=64"`
 }
 
 // VM represents a virtual machine instance with its current state and network information.
@@ -319,6 +341,128 @@ type VM struct {
 	// LaunchTime indicates when the VM was created.
 	// Format: RFC3339 timestamp (e.g., "2023-01-15T10:30:00Z")
 	LaunchTime string
+
+	// InstanceType is the VM's size, e.g. "t3.micro".
+	InstanceType string
+
+	// SubnetID is the subnet the VM was launched in, or empty if the
+	// provider doesn't report it.
+	SubnetID string
+
+	// Tags are the VM's tags, including its "Name" tag on AWS.
+	Tags map[string]string
+
+	// KeyName is the SSH key pair the VM was launched with, if any.
+	KeyName string
+
+	// SecurityGroups are the IDs of the VM's security groups.
+	SecurityGroups []string
+
+	// PlacementGroup is the placement group the VM runs in, if any.
+	PlacementGroup string
+
+	// IamInstanceProfile is the IAM instance profile attached to the VM, if
+	// any. AWS reports it as an ARN even if it was given by name.
+	IamInstanceProfile string
+
+	// Monitoring reports whether detailed monitoring is enabled.
+	Monitoring bool
+
+	// EbsOptimized reports whether the VM is EBS optimized.
+	EbsOptimized bool
+
+	// Volumes lists the block volumes attached to the VM, including its
+	// root volume on AWS.
+	Volumes []VolumeAttachment
+}
+
+// Steps of a ResizeVM call, as reported by ResizeError.Step.
+const (
+	ResizeStepStop   = "stop"
+	ResizeStepModify = "modify"
+	ResizeStepStart  = "start"
+)
+
+// ResizeError is returned by ResizeVM when a step of the resize fails.
+// errors.Is and errors.As see through it to the step's error.
+type ResizeError struct {
+	// VMID is the ID of the VM being resized.
+	VMID string
+
+	// From and To are the VM's original and requested instance types.
+	From string
+	To   string
+
+	// Step is the step that failed: ResizeStepStop, ResizeStepModify or
+	// ResizeStepStart.
+	Step string
+
+	// RolledBack reports whether the VM was restored to its original
+	// instance type and state. If false, RollbackErr says why not.
+	RolledBack  bool
+	RollbackErr error
+
+	// Err is the error of the failed step.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ResizeError) Error() string {
+	msg := fmt.Sprintf("resizing VM %s from %s to %s failed at the %s step: %v", e.VMID, e.From, e.To, e.Step, e.Err)
+	if e.RolledBack {
+		return msg + " (rolled back)"
+	}
+	if e.RollbackErr != nil {
+		return msg + fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return msg
+}
+
+// Unwrap returns Err.
+func (e *ResizeError) Unwrap() error {
+	return e.Err
+}
+
+// ListVMsOptions filters and paginates ListVMsWithOptions. Filters are
+// combined: a VM is listed only if it matches all of them.
+type ListVMsOptions struct {
+	// Tags lists only VMs that have every tag with the given value.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// States lists only VMs in one of these states, e.g. "running".
+	//
+	// Default: every state except "terminated"
+	States []string `json:"states,omitempty" yaml:"states,omitempty"`
+
+	// NamePrefix lists only VMs whose name starts with this prefix.
+	NamePrefix string `json:"name_prefix,omitempty" yaml:"name_prefix,omitempty"`
+
+	// InstanceTypes lists only VMs of one of these types, e.g. "t3.micro".
+	InstanceTypes []string `json:"instance_types,omitempty" yaml:"instance_types,omitempty"`
+
+	// SubnetIDs lists only VMs in one of these subnets.
+	SubnetIDs []string `json:"subnet_ids,omitempty" yaml:"subnet_ids,omitempty"`
+
+	// PageSize is the maximum number of VMs per page. Providers may
+	// return fewer, even before the last page, and AWS needs at least 5.
+	//
+	// Default: 0 (the provider's default, 1000 on AWS)
+	PageSize int32 `json:"page_size,omitempty" yaml:"page_size,omitempty" validate:"max=1000"`
+
+	// Cursor is the NextCursor of the previous page, or empty for the
+	// first page. Pass the same filters with every page.
+	Cursor string `json:"cursor,omitempty" yaml:"cursor,omitempty"`
+}
+
+// VMPage is one page of ListVMsWithOptions results.
+type VMPage struct {
+	// VMs are the VMs on this page. A page may be empty even if more pages
+	// follow.
+	VMs []*VM
+
+	// NextCursor is the Cursor for the next page, or empty if this is the
+	// last page.
+	NextCursor string
 }
 
 // InstanceTypeFilter represents filters for querying available instance types.
@@ -562,6 +706,11 @@ type SpotInstanceConfig struct {
 	// LaunchSpecification defines the instance configuration.
 	// This is similar to regular instance configuration but for spot requests.
 	LaunchSpecification *SpotLaunchSpec
+
+	// IdempotencyKey makes the request safe to retry: repeating it with the
+	// same key returns the original spot request instead of creating another.
+	// Maps to the EC2 ClientToken on AWS. See VMConfig.IdempotencyKey.
+	IdempotencyKey string `validate:"max=64"`
 }
 
 // SpotLaunchSpec represents the launch specification for spot instances.
@@ -747,22 +896,19 @@ type Compute interface {
 	//   fmt.Printf("VM created: %s (ID: %s, State: %s)\n", vm.Name, vm.ID, vm.State)
 	//
 	//   // Wait for VM to be running
-	//   for vm.State == "pending" {
-	//       time.Sleep(10 * time.Second)
-	//       vm, err = compute.GetVM(ctx, vm.ID)
-	//       if err != nil {
-	//           log.Fatalf("Failed to check VM status: %v", err)
-	//       }
+	//   if err := services.WaitUntilVMRunning(ctx, compute, vm.ID); err != nil {
+	//       log.Fatalf("VM did not start: %v", err)
 	//   }
-	//
-	//   if vm.State == "running" {
+	//   vm, err = compute.GetVM(ctx, vm.ID)
+	//   if err == nil {
 	//       fmt.Printf("VM is ready! Public IP: %s\n", vm.PublicIP)
 	//   }
 	CreateVM(ctx context.Context, config *VMConfig) (*VM, error)
 
-	// ListVMs returns all virtual machines in the current region.
+	// ListVMs returns all virtual machines in the current region, in every state.
 	// Returns an empty slice if no VMs exist. Results are not paginated - all VMs are returned.
-	// For accounts with many VMs, consider using provider-specific filtering if available.
+	// For accounts with many VMs, use ListVMsWithOptions or a VMIterator to filter VMs
+	// and fetch them a page at a time.
 	//
 	// Common errors:
 	//   - ErrAuthentication: Invalid credentials or expired tokens
@@ -790,6 +936,30 @@ type Compute interface {
 	//   fmt.Printf("%d VMs are currently running\n", len(runningVMs))
 	ListVMs(ctx context.Context) ([]*VM, error)
 
+	// ListVMsWithOptions returns one page of the VMs in the current region
+	// that match the filters in opts. A nil opts lists the first page of all
+	// VMs that aren't terminated. Pass the page's NextCursor as opts.Cursor
+	// to get the next page; NewVMIterator does this for you.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: The cursor is invalid or PageSize is out of range
+	//   - ErrAuthorization: Insufficient permissions to list instances
+	//   - ErrRateLimit: Too many requests, retry with exponential backoff
+	//
+	// Example:
+	//   page, err := compute.ListVMsWithOptions(ctx, &ListVMsOptions{
+	//       Tags:     map[string]string{"Environment": "production"},
+	//       States:   []string{"running"},
+	//       PageSize: 100,
+	//   })
+	//   if err != nil {
+	//       log.Fatalf("Failed to list VMs: %v", err)
+	//   }
+	//   for _, vm := range page.VMs {
+	//       fmt.Printf("  %s (%s)\n", vm.Name, vm.ID)
+	//   }
+	ListVMsWithOptions(ctx context.Context, opts *ListVMsOptions) (*VMPage, error)
+
 	// GetVM retrieves detailed information about a specific virtual machine.
 	// Returns the current state, network information, and metadata for the VM.
 	// Use this method to check VM status after creation or state changes.
@@ -843,19 +1013,12 @@ type Compute interface {
 	//   fmt.Println("VM start initiated. Waiting for running state...")
 	//
 	//   // Poll until running
-	//   for {
-	//       vm, err := compute.GetVM(ctx, "i-1234567890abcdef0")
-	//       if err != nil {
-	//           log.Fatalf("Failed to check VM status: %v", err)
-	//       }
-	//
-	//       fmt.Printf("Current state: %s\n", vm.State)
-	//       if vm.State == "running" {
-	//           fmt.Printf("VM is now running! Public IP: %s\n", vm.PublicIP)
-	//           break
-	//       }
-	//
-	//       time.Sleep(10 * time.Second)
+	//   err = services.WaitUntilVMRunning(ctx, compute, "i-1234567890abcdef0",
+	//       services.WithProgress(func(p services.WaitProgress) {
+	//           fmt.Printf("Current state: %s\n", p.State)
+	//       }))
+	//   if err != nil {
+	//       log.Fatalf("VM did not start: %v", err)
 	//   }
 	StartVM(ctx context.Context, id string) error
 
@@ -880,21 +1043,55 @@ type Compute interface {
 	//   fmt.Println("VM stop initiated. Waiting for stopped state...")
 	//
 	//   // Poll until stopped
-	//   for {
-	//       vm, err := compute.GetVM(ctx, "i-1234567890abcdef0")
-	//       if err != nil {
-	//           log.Fatalf("Failed to check VM status: %v", err)
-	//       }
+	//   if err := services.WaitUntilVMStopped(ctx, compute, "i-1234567890abcdef0"); err != nil {
+	//       log.Fatalf("VM did not stop: %v", err)
+	//   }
+	//   fmt.Println("VM is now stopped. You can start it again later with StartVM.")
+	StopVM(ctx context.Context, id string) error
+
+	// RebootVM restarts a running virtual machine in place. The VM keeps its
+	// ID, IP addresses and data, and stays in the "running" state; the
+	// reboot itself happens asynchronously after RebootVM returns.
 	//
-	//       fmt.Printf("Current state: %s\n", vm.State)
-	//       if vm.State == "stopped" {
-	//           fmt.Println("VM is now stopped. You can start it again later with StartVM.")
-	//           break
-	//       }
+	// Common errors:
+	//   - ErrAuthorization: Insufficient permissions or VM not owned by your account
+	//   - ErrResourceNotFound: VM with the specified ID doesn't exist
+	//   - ErrInvalidState: VM is not running
+	//   - ErrRateLimit: Too many requests, retry with exponential backoff
 	//
-	//       time.Sleep(10 * time.Second)
+	// Example:
+	//   if err := compute.RebootVM(ctx, "i-1234567890abcdef0"); err != nil {
+	//       log.Fatalf("Failed to reboot VM: %v", err)
 	//   }
-	StopVM(ctx context.Context, id string) error
+	RebootVM(ctx context.Context, id string) error
+
+	// ResizeVM changes the instance type of a virtual machine. A running VM
+	// is stopped, modified and started again, waiting for each state
+	// change, so the call takes minutes and the VM is unavailable meanwhile.
+	// A stopped VM is only modified and stays stopped. Resizing to the VM's
+	// current type does nothing.
+	//
+	// If a step fails, ResizeVM puts the VM back the way it was, as far as
+	// it can: the instance type is restored and a VM that was running is
+	// started again. The error is then a *ResizeError that records the
+	// failed step and whether the rollback succeeded, and that wraps the
+	// step's error, so errors.As still finds the provider's error.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: The instance type is empty or not valid
+	//   - ErrResourceNotFound: VM with the specified ID doesn't exist
+	//   - ErrInvalidState: VM is neither running nor stopped
+	//   - ErrResourceConflict: No capacity for the new instance type
+	//
+	// Example:
+	//   ctx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	//   defer cancel()
+	//   err := compute.ResizeVM(ctx, "i-1234567890abcdef0", "t3.large")
+	//   var resizeErr *services.ResizeError
+	//   if errors.As(err, &resizeErr) && !resizeErr.RolledBack {
+	//       log.Printf("VM left in an unknown state: %v", resizeErr.RollbackErr)
+	//   }
+	ResizeVM(ctx context.Context, id string, instanceType string) error
 
 	// DeleteVM permanently deletes a virtual machine and all its associated data.
 	// This operation cannot be undone. The VM and its ephemeral storage will be lost forever.
@@ -928,23 +1125,10 @@ type Compute interface {
 	//   fmt.Println("VM deletion initiated. The VM will be terminated shortly.")
 	//
 	//   // Optional: Wait for termination
-	//   for {
-	//       vm, err := compute.GetVM(ctx, "i-1234567890abcdef0")
-	//       if err != nil {
-	//           if errors.Is(err, ErrResourceNotFound) {
-	//               fmt.Println("VM has been successfully deleted")
-	//               break
-	//           }
-	//           log.Fatalf("Failed to check VM status: %v", err)
-	//       }
-	//
-	//       if vm.State == "terminated" {
-	//           fmt.Println("VM has been terminated")
-	//           break
-	//       }
-	//
-	//       time.Sleep(10 * time.Second)
+	//   if err := services.WaitUntilVMTerminated(ctx, compute, "i-1234567890abcdef0"); err != nil {
+	//       log.Fatalf("Failed to check VM status: %v", err)
 	//   }
+	//   fmt.Println("VM has been terminated")
 	DeleteVM(ctx context.Context, id string) error
 
 	// InstanceTypes returns the service for querying available instance types.
@@ -1021,4 +1205,60 @@ type Compute interface {
 	//
 	//   fmt.Printf("Spot request created: %s\n", request.SpotInstanceRequestId)
 	SpotInstances() SpotInstancesService
+
+	// Volumes returns the service for managing block volumes (EBS on AWS).
+	//
+	// Example:
+	//   volumes := compute.Volumes()
+	//   volume, err := volumes.Create(ctx, &VolumeConfig{
+	//       AvailabilityZone: "us-east-1a",
+	//       SizeGiB:          500,
+	//   })
+	//   if err != nil {
+	//       log.Fatalf("Failed to create volume: %v", err)
+	//   }
+	//   if err := services.WaitUntilVolumeAvailable(ctx, compute, volume.ID); err != nil {
+	//       log.Fatalf("Volume did not become available: %v", err)
+	//   }
+	//   err = volumes.Attach(ctx, volume.ID, vm.ID, "/dev/sdf")
+	Volumes() VolumesService
+
+	// Images returns the service for finding and creating machine images
+	// (AMIs on AWS).
+	//
+	// Example:
+	//   image, err := compute.Images().Latest(ctx, &ImageFilter{
+	//       Owners:       []string{"amazon"},
+	//       NamePattern:  "al2023-ami-2023.*-x86_64",
+	//       Architecture: "x86_64",
+	//   })
+	//   if err != nil {
+	//       log.Fatalf("Failed to find image: %v", err)
+	//   }
+	//   config.ImageID = image.ID
+	Images() ImagesService
+
+	// KeyPairs returns the service for managing the SSH key pairs that
+	// VMConfig.KeyName refers to.
+	//
+	// Example:
+	//   keyPair, err := compute.KeyPairs().Create(ctx, &KeyPairConfig{Name: "deploy"})
+	//   if err != nil {
+	//       log.Fatalf("Failed to create key pair: %v", err)
+	//   }
+	//   config.KeyName = keyPair.Name
+	KeyPairs() KeyPairsService
+
+	// SecurityGroups returns the service for managing security groups and
+	// their firewall rules, which VMConfig.SecurityGroups refers to.
+	//
+	// Example:
+	//   group, err := compute.SecurityGroups().Create(ctx, &SecurityGroupConfig{Name: "ssh"})
+	//   if err != nil {
+	//       log.Fatalf("Failed to create security group: %v", err)
+	//   }
+	//   err = compute.SecurityGroups().AddRules(ctx, group.ID, []FirewallRule{
+	//       {Direction: "ingress", Protocol: "tcp", FromPort: 22, CIDR: "203.0.113.0/24"},
+	//   })
+	SecurityGroups() SecurityGroupsService
 }
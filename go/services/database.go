@@ -627,6 +627,14 @@ type DBConfig struct {
 	//
 	// Examples: ["postgresql"], ["error", "slow-query"], ["alert", "audit"]
 	EnabledCloudwatchLogsExports []string `json:"enabled_cloudwatch_logs_exports,omitempty" yaml:"enabled_cloudwatch_logs_exports,omitempty"`
+
+	// IdempotencyKey makes CreateDB safe to retry. Repeating a create with
+	// the same key returns the database the first call created instead of
+	// failing because the identifier is taken. See VMConfig.IdempotencyKey.
+	//
+	// RDS has no client tokens, so the AWS provider stores the key in a tag
+	// named by cloudsdk.IdempotencyKeyTag and looks it up on conflicts.
+	IdempotencyKey string `json:"idempotency_key,omitempty" yaml:"idempotency_key,omitempty" validate:"max=64"`
 }
 
 // DBInstance represents a managed database instance with its current state and connection information.
@@ -763,14 +771,14 @@ type Database interface {
 	//
 	//   // Wait for database to become available
 	//   fmt.Println("Waiting for database to become available...")
-	//   for db.Status != "available" {
-	//       time.Sleep(30 * time.Second)
-	//       db, err = database.GetDB(ctx, db.ID)
-	//       if err != nil {
-	//           log.Fatalf("Failed to check database status: %v", err)
-	//       }
-	//       fmt.Printf("Current status: %s\n", db.Status)
+	//   err = services.WaitUntilDBAvailable(ctx, database, db.ID,
+	//       services.WithProgress(func(p services.WaitProgress) {
+	//           fmt.Printf("Current status: %s\n", p.State)
+	//       }))
+	//   if err != nil {
+	//       log.Fatalf("Database did not become available: %v", err)
 	//   }
+	//   db, err = database.GetDB(ctx, db.ID) // now has its endpoint
 	//
 	//   // Database is ready - construct connection string
 	//   connStr := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=require",
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// ImageFilter selects the images returned by ImagesService.List and Latest.
+// Filters are combined: an image is listed only if it matches all of them.
+//
+// Example:
+//
+//	// The latest Ubuntu 22.04 image for arm64, published by Canonical
+//	filter := &ImageFilter{
+//	    Owners:       []string{"099720109477"},
+//	    NamePattern:  "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-arm64-server-*",
+//	    Architecture: "arm64",
+//	}
+type ImageFilter struct {
+	// ImageIDs lists only these images.
+	ImageIDs []string
+
+	// Owners lists only images owned by one of these accounts. Besides
+	// account IDs, AWS accepts the aliases "self", "amazon" and
+	// "aws-marketplace".
+	//
+	// Default: "self" unless ImageIDs is set, since listing every image
+	// shared with the account returns tens of thousands of public images
+	Owners []string
+
+	// NamePattern lists only images whose name matches this glob, where "*"
+	// matches any run of characters and "?" any single character.
+	//
+	// Example: "al2023-ami-2023.*-x86_64"
+	NamePattern string
+
+	// Architecture lists only images for this architecture, e.g. "x86_64"
+	// or "arm64".
+	Architecture string
+
+	// States lists only images in one of these states, e.g. "available".
+	States []string
+
+	// CreatedAfter and CreatedBefore list only images created in this
+	// window. Zero values leave the window open.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Tags lists only images that have every tag with the given value.
+	Tags map[string]string
+}
+
+// Image represents a machine image that VMs can be launched from.
+type Image struct {
+	// ID is the unique identifier assigned by the provider, e.g.
+	// "ami-0abcdef1234567890" on AWS. Pass it as VMConfig.ImageID.
+	ID string
+
+	// Name and Description are set by the image's owner.
+	Name        string
+	Description string
+
+	// OwnerID is the account that owns the image, and OwnerAlias its alias
+	// (e.g. "amazon") if it has one.
+	OwnerID    string
+	OwnerAlias string
+
+	// Architecture is the processor architecture, e.g. "x86_64" or "arm64".
+	Architecture string
+
+	// State is the image's state. Common states: "pending", "available",
+	// "failed", "deregistered".
+	State string
+
+	// CreationDate indicates when the image was created.
+	// Format: RFC3339 timestamp
+	CreationDate string
+
+	// Public reports whether every account can launch the image.
+	Public bool
+
+	// Platform is "windows" for Windows images, and empty otherwise.
+	Platform string
+
+	// RootDeviceType is where the root volume lives, e.g. "ebs".
+	RootDeviceType string
+
+	// Tags are the image's tags.
+	Tags map[string]string
+}
+
+// CreateImageConfig represents the configuration for creating an image from
+// a VM.
+type CreateImageConfig struct {
+	// VMID is the VM to create the image from.
+	VMID string `json:"vm_id" yaml:"vm_id" validate:"required"`
+
+	// Name is the image's name, unique within the account and region.
+	// Validation: 3-128 characters
+	Name string `json:"name" yaml:"name" validate:"required,min=3,max=128"`
+
+	// Description is a free-form description of the image.
+	Description string `json:"description,omitempty" yaml:"description,omitempty" validate:"max=255"`
+
+	// NoReboot creates the image without shutting the VM down first. File
+	// systems are then not guaranteed to be consistent.
+	//
+	// Default: false (the VM is rebooted)
+	NoReboot bool `json:"no_reboot,omitempty" yaml:"no_reboot,omitempty"`
+
+	// Tags are key-value pairs applied to the image, and on AWS to its
+	// snapshots, when it is created.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"max=50,dive,keys,max=255,endkeys,max=255"`
+}
+
+// ImagesService provides operations for finding and creating machine images.
+// Looking images up by name with Latest avoids hardcoding image IDs, which
+// differ between regions and go stale as new images are released.
+type ImagesService interface {
+	// List returns the images that match filter, newest first. Returns an
+	// empty slice if none match.
+	//
+	// Example:
+	//   images, err := compute.Images().List(ctx, &ImageFilter{
+	//       Owners:       []string{"self"},
+	//       CreatedAfter: time.Now().AddDate(0, -1, 0),
+	//   })
+	List(ctx context.Context, filter *ImageFilter) ([]*Image, error)
+
+	// Get retrieves an image by ID.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Image doesn't exist or isn't shared with the account
+	Get(ctx context.Context, id string) (*Image, error)
+
+	// Latest returns the most recently created available image that matches
+	// filter, ignoring filter.States.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: No available image matches
+	//
+	// Example:
+	//   image, err := compute.Images().Latest(ctx, &ImageFilter{
+	//       Owners:      []string{"amazon"},
+	//       NamePattern: "al2023-ami-2023.*-x86_64",
+	//   })
+	Latest(ctx context.Context, filter *ImageFilter) (*Image, error)
+
+	// Create starts creating an image from a VM and returns it in the
+	// "pending" state; see WaitUntilImageAvailable. Unless NoReboot is set,
+	// the VM is shut down and restarted while its disks are copied.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: Invalid name or description
+	//   - ErrResourceNotFound: VM doesn't exist
+	//   - ErrResourceConflict: An image with this name already exists
+	Create(ctx context.Context, config *CreateImageConfig) (*Image, error)
+
+	// Deregister deregisters an image owned by the account, so that no new
+	// VMs can be launched from it. VMs already running are unaffected. On
+	// AWS the image's snapshots are kept and still billed.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Image doesn't exist
+	Deregister(ctx context.Context, id string) error
+}
@@ -0,0 +1,113 @@
+package services
+
+import "context"
+
+// VMIterator lists VMs a page at a time with ListVMsWithOptions. A page is
+// only fetched when Next needs it, so stopping early avoids fetching the
+// remaining pages.
+//
+// Example:
+//
+//	it := services.NewVMIterator(ctx, client.Compute(), services.ListVMsOptions{
+//	    States: []string{"running"},
+//	})
+//	for it.Next() {
+//	    vm := it.VM()
+//	    if vm.Name == "web-server" {
+//	        break // no more pages are fetched
+//	    }
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+type VMIterator struct {
+	ctx     context.Context
+	compute Compute
+	opts    ListVMsOptions
+
+	page  *VMPage
+	index int
+	vm    *VM
+	pages int
+	done  bool
+	err   error
+}
+
+// NewVMIterator returns an iterator over the VMs of compute that match the
+// filters in opts, starting at opts.Cursor. No page is fetched until the
+// first call to Next.
+func NewVMIterator(ctx context.Context, compute Compute, opts ListVMsOptions) *VMIterator {
+	return &VMIterator{ctx: ctx, compute: compute, opts: opts}
+}
+
+// Next advances to the next VM, fetching the next page when the current one
+// is used up. It returns false when there are no more VMs or a page could
+// not be fetched; check Err to tell these apart.
+func (it *VMIterator) Next() bool {
+	for {
+		if it.page != nil && it.index < len(it.page.VMs) {
+			it.vm = it.page.VMs[it.index]
+			it.index++
+			return true
+		}
+		if !it.NextPage() {
+			it.vm = nil
+			return false
+		}
+	}
+}
+
+// NextPage fetches the next page, discarding any VMs of the current page
+// that Next hasn't returned yet. It returns false when there are no more
+// pages or the page could not be fetched; check Err to tell these apart. A
+// nil page, as returned by middleware that short-circuits the call, ends the
+// iteration.
+// Use NextPage and Page instead of Next and VM to handle VMs a page at a
+// time.
+func (it *VMIterator) NextPage() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	opts := it.opts
+	page, err := it.compute.ListVMsWithOptions(it.ctx, &opts)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if page == nil {
+		it.done = true
+		return false
+	}
+	it.page, it.index = page, 0
+	it.pages++
+	it.opts.Cursor = page.NextCursor
+	it.done = page.NextCursor == ""
+	return true
+}
+
+// VM returns the VM that Next advanced to.
+func (it *VMIterator) VM() *VM {
+	return it.vm
+}
+
+// Page returns the page that was fetched last, or nil before the first.
+func (it *VMIterator) Page() *VMPage {
+	return it.page
+}
+
+// Cursor returns the cursor of the page after the one fetched last, which
+// can be stored to resume listing later with ListVMsOptions.Cursor. It is
+// empty once the last page has been fetched.
+func (it *VMIterator) Cursor() string {
+	return it.opts.Cursor
+}
+
+// Pages returns how many pages have been fetched.
+func (it *VMIterator) Pages() int {
+	return it.pages
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *VMIterator) Err() error {
+	return it.err
+}
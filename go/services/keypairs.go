@@ -0,0 +1,118 @@
+package services
+
+import "context"
+
+// KeyPairConfig represents the configuration for generating a key pair.
+//
+// Example:
+//
+//	keyPair, err := compute.KeyPairs().Create(ctx, &KeyPairConfig{
+//	    Name: "deploy-2024-10",
+//	})
+//	if err != nil {
+//	    log.Fatalf("Failed to create key pair: %v", err)
+//	}
+//	// The private key is only returned here; store it securely now
+//	err = os.WriteFile("deploy-2024-10.pem", []byte(keyPair.PrivateKey), 0600)
+type KeyPairConfig struct {
+	// Name is the key pair's name, unique within the account and region.
+	// VMConfig.KeyName and SpotLaunchSpec.KeyName refer to it.
+	// Validation: 1-255 characters
+	Name string `json:"name" yaml:"name" validate:"required,min=1,max=255"`
+
+	// KeyType is the kind of key to generate: "ed25519" or "rsa" (2048-bit).
+	// Windows VMs on AWS need "rsa" to decrypt their administrator password.
+	//
+	// Default: "ed25519"
+	KeyType string `json:"key_type,omitempty" yaml:"key_type,omitempty" validate:"omitempty,oneof=ed25519 rsa"`
+
+	// Tags are key-value pairs applied to the key pair when it is created.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"max=50,dive,keys,max=255,endkeys,max=255"`
+}
+
+// ImportKeyPairConfig represents the configuration for importing an existing
+// public key, whose private key never leaves the caller.
+//
+// Example:
+//
+//	publicKey, _ := os.ReadFile(filepath.Join(home, ".ssh", "id_ed25519.pub"))
+//	keyPair, err := compute.KeyPairs().Import(ctx, &ImportKeyPairConfig{
+//	    Name:      "alice-laptop",
+//	    PublicKey: string(publicKey),
+//	})
+type ImportKeyPairConfig struct {
+	// Name is the key pair's name, unique within the account and region.
+	// Validation: 1-255 characters
+	Name string `json:"name" yaml:"name" validate:"required,min=1,max=255"`
+
+	// PublicKey is the public key in OpenSSH authorized_keys format, e.g.
+	// "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA... alice@laptop". ed25519 and
+	// RSA keys are supported.
+	PublicKey string `json:"public_key" yaml:"public_key" validate:"required,max=16384"`
+
+	// Tags are key-value pairs applied to the key pair when it is imported.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"max=50,dive,keys,max=255,endkeys,max=255"`
+}
+
+// KeyPair represents an SSH key pair registered with the provider.
+type KeyPair struct {
+	// ID is the unique identifier assigned by the provider, e.g.
+	// "key-0123456789abcdef0" on AWS.
+	ID string
+
+	// Name is the key pair's name, used as VMConfig.KeyName.
+	Name string
+
+	// KeyType is "ed25519" or "rsa".
+	KeyType string
+
+	// Fingerprint identifies the key. Its format is provider-specific; on
+	// AWS it differs between generated and imported keys.
+	Fingerprint string
+
+	// PublicKey is the public key in OpenSSH authorized_keys format. On AWS
+	// it is empty on the KeyPair returned by Create, which EC2 doesn't
+	// return it from, and for key pairs created before EC2 kept public keys.
+	PublicKey string
+
+	// PrivateKey is the unencrypted private key, PEM-encoded (OpenSSH format
+	// for ed25519 keys). It is only set on the KeyPair returned by
+	// KeyPairsService.Create: providers don't keep it, so it can't be
+	// retrieved again.
+	PrivateKey string
+
+	// CreateTime indicates when the key pair was created or imported.
+	// Format: RFC3339 timestamp
+	CreateTime string
+
+	// Tags are the key pair's tags.
+	Tags map[string]string
+}
+
+// KeyPairsService provides operations for managing the SSH key pairs that
+// VMs are launched with.
+type KeyPairsService interface {
+	// Create generates a key pair and returns it with its private key. This
+	// is the only time the private key is available.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: Invalid name or key type
+	//   - ErrResourceConflict: A key pair with this name already exists
+	Create(ctx context.Context, config *KeyPairConfig) (*KeyPair, error)
+
+	// Import registers an existing public key as a key pair.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: Invalid name, or the public key can't be parsed
+	//   - ErrResourceConflict: A key pair with this name already exists
+	Import(ctx context.Context, config *ImportKeyPairConfig) (*KeyPair, error)
+
+	// List returns every key pair in the region, sorted by name, without
+	// private keys. Returns an empty slice if there are none.
+	List(ctx context.Context) ([]*KeyPair, error)
+
+	// Delete deletes a key pair by name. VMs already launched with it keep
+	// the public key in their authorized keys. Deleting a key pair that
+	// doesn't exist is not an error.
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,119 @@
+package services
+
+import "log/slog"
+
+// redacted replaces secret values when configurations are logged.
+const redacted = "[REDACTED]"
+
+// Configuration types that carry secrets implement slog.LogValuer, so passing
+// them to a *slog.Logger (for example from middleware) never writes the secret:
+//
+//	logger.Debug("creating VM", "config", vmConfig) // UserData is "[REDACTED]"
+//
+// Each method logs a copy converted to a method-less type, so the copy is
+// formatted normally instead of calling LogValue again.
+
+type (
+	vmConfigLog                 VMConfig
+	spotInstanceConfigLog       SpotInstanceConfig
+	spotLaunchSpecLog           SpotLaunchSpec
+	dbConfigLog                 DBConfig
+	bucketConfigLog             BucketConfig
+	replicationConfigurationLog ReplicationConfiguration
+)
+
+// LogValue implements slog.LogValuer, redacting UserData.
+func (c *VMConfig) LogValue() slog.Value {
+	if c == nil {
+		return slog.AnyValue(nil)
+	}
+	masked := vmConfigLog(*c)
+	if masked.UserData != "" {
+		masked.UserData = redacted
+	}
+	return slog.AnyValue(masked)
+}
+
+// LogValue implements slog.LogValuer, redacting LaunchSpecification.UserData.
+func (c *SpotInstanceConfig) LogValue() slog.Value {
+	if c == nil {
+		return slog.AnyValue(nil)
+	}
+	masked := spotInstanceConfigLog(*c)
+	masked.LaunchSpecification = c.LaunchSpecification.redacted()
+	return slog.AnyValue(masked)
+}
+
+// LogValue implements slog.LogValuer, redacting UserData.
+func (s *SpotLaunchSpec) LogValue() slog.Value {
+	if s == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.AnyValue(spotLaunchSpecLog(*s.redacted()))
+}
+
+// redacted returns a copy of s with UserData masked.
+func (s *SpotLaunchSpec) redacted() *SpotLaunchSpec {
+	if s == nil {
+		return nil
+	}
+	masked := *s
+	if masked.UserData != "" {
+		masked.UserData = redacted
+	}
+	return &masked
+}
+
+// LogValue implements slog.LogValuer, redacting MasterPassword.
+func (c *DBConfig) LogValue() slog.Value {
+	if c == nil {
+		return slog.AnyValue(nil)
+	}
+	masked := dbConfigLog(*c)
+	if masked.MasterPassword != "" {
+		masked.MasterPassword = redacted
+	}
+	return slog.AnyValue(masked)
+}
+
+// LogValue implements slog.LogValuer, redacting the replication role and
+// replica KMS keys in ReplicationConfig.
+func (c *BucketConfig) LogValue() slog.Value {
+	if c == nil {
+		return slog.AnyValue(nil)
+	}
+	masked := bucketConfigLog(*c)
+	masked.ReplicationConfig = c.ReplicationConfig.redacted()
+	return slog.AnyValue(masked)
+}
+
+// LogValue implements slog.LogValuer, redacting the role and replica KMS keys.
+func (c *ReplicationConfiguration) LogValue() slog.Value {
+	if c == nil {
+		return slog.AnyValue(nil)
+	}
+	return slog.AnyValue(replicationConfigurationLog(*c.redacted()))
+}
+
+// redacted returns a deep copy of c with the role and replica KMS keys masked.
+func (c *ReplicationConfiguration) redacted() *ReplicationConfiguration {
+	if c == nil {
+		return nil
+	}
+	masked := *c
+	if masked.Role != "" {
+		masked.Role = redacted
+	}
+	masked.Rules = make([]ReplicationRule, len(c.Rules))
+	for i, rule := range c.Rules {
+		if enc := rule.Destination.EncryptionConfiguration; enc != nil {
+			maskedEnc := *enc
+			if maskedEnc.ReplicaKmsKeyID != "" {
+				maskedEnc.ReplicaKmsKeyID = redacted
+			}
+			rule.Destination.EncryptionConfiguration = &maskedEnc
+		}
+		masked.Rules[i] = rule
+	}
+	return &masked
+}
@@ -0,0 +1,169 @@
+package services
+
+import "context"
+
+// SecurityGroupConfig represents the configuration for creating a security
+// group: a named set of firewall rules that VMs and databases are placed in
+// through VMConfig.SecurityGroups and DBConfig.VpcSecurityGroups.
+//
+// Example:
+//
+//	group, err := compute.SecurityGroups().Create(ctx, &SecurityGroupConfig{
+//	    Name:        "web",
+//	    Description: "Public web servers",
+//	    NetworkID:   "vpc-0123456789abcdef0",
+//	})
+type SecurityGroupConfig struct {
+	// Name is the group's name, unique within its network.
+	// Validation: 1-255 characters
+	Name string `json:"name" yaml:"name" validate:"required,min=1,max=255"`
+
+	// Description is a free-form description of the group.
+	//
+	// Default: the group's name
+	Description string `json:"description,omitempty" yaml:"description,omitempty" validate:"max=255"`
+
+	// NetworkID is the network to create the group in: a VPC ID on AWS.
+	// Groups can only be used by resources in the same network.
+	//
+	// Default: the region's default network
+	NetworkID string `json:"network_id,omitempty" yaml:"network_id,omitempty"`
+
+	// Tags are key-value pairs applied to the group when it is created.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"max=50,dive,keys,max=255,endkeys,max=255"`
+}
+
+// FirewallRule allows one kind of traffic into or out of a security group.
+// Security groups only allow traffic, so there are no deny rules: traffic
+// that no rule allows is dropped. Responses to allowed traffic are always
+// allowed back.
+//
+// Each rule names exactly one peer: a CIDR block, or another security group
+// whose members are allowed.
+//
+// Example:
+//
+//	rules := []FirewallRule{
+//	    // HTTPS from anywhere
+//	    {Direction: "ingress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"},
+//	    // PostgreSQL from the web servers only
+//	    {Direction: "ingress", Protocol: "tcp", FromPort: 5432, SourceGroupID: web.ID},
+//	}
+type FirewallRule struct {
+	// Direction is "ingress" for traffic into the group's members, or
+	// "egress" for traffic out of them.
+	Direction string `json:"direction" yaml:"direction" validate:"required,oneof=ingress egress"`
+
+	// Protocol is "tcp", "udp", "icmp" or "all". Rules listed from a
+	// provider may also carry other IP protocol numbers, e.g. "50".
+	Protocol string `json:"protocol" yaml:"protocol" validate:"required,oneof=tcp udp icmp all"`
+
+	// FromPort and ToPort are the inclusive port range for "tcp" and "udp"
+	// rules. A ToPort of 0 means FromPort alone. "icmp" and "all" rules
+	// match every port and ICMP type, and must leave both at 0.
+	//
+	// Example: FromPort 8000, ToPort 8999
+	FromPort int32 `json:"from_port,omitempty" yaml:"from_port,omitempty" validate:"min=0,max=65535"`
+	ToPort   int32 `json:"to_port,omitempty" yaml:"to_port,omitempty" validate:"min=0,max=65535"`
+
+	// CIDR is the IPv4 or IPv6 block traffic is allowed from (ingress) or to
+	// (egress), e.g. "203.0.113.0/24" or "::/0".
+	CIDR string `json:"cidr,omitempty" yaml:"cidr,omitempty" validate:"omitempty,cidr"`
+
+	// SourceGroupID is the security group whose members traffic is allowed
+	// from (ingress) or to (egress), instead of a CIDR block.
+	SourceGroupID string `json:"source_group_id,omitempty" yaml:"source_group_id,omitempty"`
+
+	// Description is a free-form description of the rule.
+	Description string `json:"description,omitempty" yaml:"description,omitempty" validate:"max=255"`
+}
+
+// SecurityGroupFilter selects the security groups returned by
+// SecurityGroupsService.List. Filters are combined: a group is listed only if
+// it matches all of them.
+type SecurityGroupFilter struct {
+	// Name lists only the group with this name.
+	Name string
+
+	// NetworkID lists only groups in this network.
+	NetworkID string
+
+	// Tags lists only groups that have every tag with the given value.
+	Tags map[string]string
+}
+
+// SecurityGroup represents a security group and its rules.
+type SecurityGroup struct {
+	// ID is the unique identifier assigned by the provider, e.g.
+	// "sg-0123456789abcdef0" on AWS. VMConfig.SecurityGroups takes it.
+	ID string
+
+	// Name and Description are set when the group is created.
+	Name        string
+	Description string
+
+	// NetworkID is the network the group belongs to: a VPC ID on AWS.
+	NetworkID string
+
+	// Rules are the group's ingress and egress rules.
+	Rules []FirewallRule
+
+	// Tags are the group's tags.
+	Tags map[string]string
+}
+
+// SecurityGroupsService provides operations for managing security groups and
+// their firewall rules.
+type SecurityGroupsService interface {
+	// Create creates a security group. A new group allows all outbound
+	// traffic and no inbound traffic; revoke its egress rule to restrict
+	// outbound traffic.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: Invalid name or description, or the network doesn't exist
+	//   - ErrResourceConflict: A group with this name exists in the network
+	//
+	// Example:
+	//   group, err := compute.SecurityGroups().Create(ctx, &SecurityGroupConfig{Name: "web"})
+	//   if err != nil {
+	//       log.Fatalf("Failed to create security group: %v", err)
+	//   }
+	//   err = compute.SecurityGroups().AddRules(ctx, group.ID, []FirewallRule{
+	//       {Direction: "ingress", Protocol: "tcp", FromPort: 443, CIDR: "0.0.0.0/0"},
+	//   })
+	Create(ctx context.Context, config *SecurityGroupConfig) (*SecurityGroup, error)
+
+	// List returns the security groups that match filter, sorted by ID. A nil
+	// filter lists every group. Returns an empty slice if none match.
+	List(ctx context.Context, filter *SecurityGroupFilter) ([]*SecurityGroup, error)
+
+	// Get retrieves a security group and its rules by ID.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Group doesn't exist
+	Get(ctx context.Context, id string) (*SecurityGroup, error)
+
+	// AddRules adds rules to a security group. They apply at once to every
+	// resource in the group.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: A rule is invalid, or names a group that doesn't exist
+	//   - ErrResourceNotFound: Group doesn't exist
+	//   - ErrResourceConflict: The group already has one of the rules
+	AddRules(ctx context.Context, groupID string, rules []FirewallRule) error
+
+	// RevokeRules removes rules from a security group. Rules match when
+	// every field but Description is equal.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: A rule is invalid
+	//   - ErrResourceNotFound: Group doesn't exist, or doesn't have one of the rules
+	RevokeRules(ctx context.Context, groupID string, rules []FirewallRule) error
+
+	// Delete deletes a security group.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Group doesn't exist
+	//   - ErrDependencyViolation: VMs are still in the group, or another group's rules refer to it
+	Delete(ctx context.Context, id string) error
+}
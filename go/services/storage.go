@@ -154,7 +154,7 @@ type BucketConfig struct {
 	//   - Use bucket policies to deny public access by default
 	//
 	// Default: "private"
-	ACL string `json:"acl,omitempty" yaml:"acl,omitempty" validate:"oneof=private public-read public-read-write authenticated-read bucket-owner-read bucket-owner-full-control"`
+	ACL string `json:"acl,omitempty" yaml:"acl,omitempty" validate:"omitempty,oneof=private public-read public-read-write authenticated-read bucket-owner-read bucket-owner-full-control"`
 
 	// StorageClass defines the storage tier for objects in the bucket.
 	// Different classes offer different performance, availability, and cost characteristics
@@ -242,6 +242,17 @@ type BucketConfig struct {
 	// Automatically replicates objects to another bucket in a different region
 	// Provides disaster recovery and compliance benefits
 	ReplicationConfig *ReplicationConfiguration `json:"replication_config,omitempty" yaml:"replication_config,omitempty"`
+
+	// IdempotencyKey makes CreateBucket safe to retry. Repeating a create
+	// with the same key succeeds if the first call created the bucket,
+	// instead of failing because the name is taken. See
+	// VMConfig.IdempotencyKey.
+	//
+	// S3 has no client tokens, so the AWS provider stores the key in a tag
+	// named by cloudsdk.IdempotencyKeyTag and looks it up on conflicts. If
+	// the tag can't be stored, CreateBucket returns an error even though the
+	// bucket was created.
+	IdempotencyKey string `json:"idempotency_key,omitempty" yaml:"idempotency_key,omitempty" validate:"max=64"`
 }
 
 // BucketEncryption configures server-side encryption for bucket objects.
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError describes one field of a configuration that failed validation.
+type FieldError struct {
+	// Field is the path to the field from the validated struct, such as
+	// "Name", "Tags[env]" or "ReplicationConfig.Rules[0].ID".
+	Field string
+
+	// Rule is the validate tag rule that failed, such as "required" or "max".
+	Rule string
+
+	// Param is the rule's parameter, such as "63" for max=63, or empty.
+	Param string
+
+	// Message explains what is wrong with the value.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors holds every FieldError found by Validate.
+type ValidationErrors []*FieldError
+
+// Error implements the error interface, listing each invalid field.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks a configuration such as *VMConfig, *BucketConfig or
+// *DBConfig against its `validate` struct tags. It walks nested structs,
+// slices and maps, and returns ValidationErrors with every invalid field,
+// or nil if the configuration is valid.
+//
+// Supported rules:
+//   - required, omitempty
+//   - min=N, max=N: length of strings, slices and maps, or value of numbers
+//   - oneof=a b c: the value must be one of the space-separated options
+//   - dive: apply the following rules to each slice or map element, with
+//     keys ... endkeys applying rules to map keys
+//   - hostname, lowercase, alphanum_underscore, starts_with_letter
+//   - cidr: an IPv4 or IPv6 CIDR block such as "10.0.0.0/16" or "::/0"
+//   - bucket_name: the bucket naming rules documented on BucketConfig
+//   - strong_password: the rules documented on DBConfig.MasterPassword
+//   - backup_window_format: "HH:MM-HH:MM" spanning at least 30 minutes
+//   - maintenance_window_format: "ddd:HH:MM-ddd:HH:MM" spanning at least 30 minutes
+//
+// Each field reports at most one violation: the first rule it fails.
+//
+// Example:
+//
+//	if err := services.Validate(config); err != nil {
+//	    var errs services.ValidationErrors
+//	    errors.As(err, &errs)
+//	    for _, fieldErr := range errs {
+//	        fmt.Printf("%s: %s\n", fieldErr.Field, fieldErr.Message)
+//	    }
+//	}
+func Validate(config interface{}) error {
+	v := reflect.ValueOf(config)
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return ValidationErrors{{Field: "config", Rule: "required", Message: "configuration cannot be nil"}}
+	}
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return ValidationErrors{{Field: "config", Message: fmt.Sprintf("expected a configuration struct, got %s", v.Type())}}
+	}
+
+	var errs ValidationErrors
+	validateStruct(v, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// rule is one parsed entry of a validate tag.
+type rule struct {
+	name  string
+	param string
+}
+
+// parseTag splits a validate tag into the rules for the field itself, the
+// rules for map keys, and the tag to apply to each element after "dive".
+func parseTag(tag string) (fieldRules, keyRules []rule, elemTag string) {
+	if tag == "" {
+		return nil, nil, ""
+	}
+	parts := strings.Split(tag, ",")
+	for i := 0; i < len(parts); i++ {
+		if parts[i] != "dive" {
+			fieldRules = append(fieldRules, parseRule(parts[i]))
+			continue
+		}
+		rest := parts[i+1:]
+		if len(rest) > 0 && rest[0] == "keys" {
+			j := 1
+			for ; j < len(rest) && rest[j] != "endkeys"; j++ {
+				keyRules = append(keyRules, parseRule(rest[j]))
+			}
+			if j < len(rest) {
+				j++
+			}
+			rest = rest[j:]
+		}
+		return fieldRules, keyRules, strings.Join(rest, ",")
+	}
+	return fieldRules, nil, ""
+}
+
+func parseRule(s string) rule {
+	name, param, _ := strings.Cut(strings.TrimSpace(s), "=")
+	return rule{name: name, param: param}
+}
+
+func validateStruct(v reflect.Value, path string, errs *ValidationErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		validateValue(v.Field(i), fieldPath, field.Tag.Get("validate"), errs)
+	}
+}
+
+// validateValue applies tag to v, then validates the structs, slice
+// elements and map entries it contains.
+func validateValue(v reflect.Value, path, tag string, errs *ValidationErrors) {
+	fieldRules, keyRules, elemTag := parseTag(tag)
+	if !applyRules(v, path, fieldRules, "", errs) {
+		return
+	}
+
+	v = indirect(v)
+	if !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		if elemTag == "" && !containsStructs(v.Type().Elem()) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), elemTag, errs)
+		}
+	case reflect.Map:
+		if elemTag == "" && len(keyRules) == 0 && !containsStructs(v.Type().Elem()) {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			elemPath := fmt.Sprintf("%s[%v]", path, key.Interface())
+			applyRules(key, elemPath, keyRules, "key ", errs)
+			validateValue(v.MapIndex(key), elemPath, elemTag, errs)
+		}
+	}
+}
+
+// applyRules checks v against rules, adding the first failure to errs.
+// It reports whether nested values should be validated too, which they
+// aren't for empty optional or missing required values.
+func applyRules(v reflect.Value, path string, rules []rule, prefix string, errs *ValidationErrors) bool {
+	for _, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if isEmpty(v) {
+				return false
+			}
+			continue
+		case "required":
+			if isEmpty(v) {
+				*errs = append(*errs, &FieldError{Field: path, Rule: r.name, Message: prefix + "is required"})
+				return false
+			}
+			continue
+		}
+
+		value := indirect(v)
+		if !value.IsValid() {
+			// Optional pointers that aren't set have nothing to check
+			return false
+		}
+		check, ok := validators[r.name]
+		if !ok {
+			*errs = append(*errs, &FieldError{Field: path, Rule: r.name, Param: r.param,
+				Message: fmt.Sprintf("unknown validation rule %q", r.name)})
+			return true
+		}
+		if message := check(value, r.param); message != "" {
+			*errs = append(*errs, &FieldError{Field: path, Rule: r.name, Param: r.param, Message: prefix + message})
+			return true
+		}
+	}
+	return true
+}
+
+// isEmpty reports whether v is unset. Struct values are never empty; their
+// own fields' rules decide whether they are valid.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	default:
+		return v.IsZero()
+	}
+}
+
+// indirect follows pointers, returning the zero Value for nil pointers.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func containsStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// validator checks a value against a rule's parameter and returns a
+// message describing the failure, or "" if the value is valid.
+type validator func(v reflect.Value, param string) string
+
+var validators = map[string]validator{
+	"min":                       validateMin,
+	"max":                       validateMax,
+	"oneof":                     validateOneOf,
+	"hostname":                  stringRule(validateHostname),
+	"lowercase":                 stringRule(validateLowercase),
+	"bucket_name":               stringRule(validateBucketName),
+	"alphanum_underscore":       stringRule(validateAlphanumUnderscore),
+	"starts_with_letter":        stringRule(validateStartsWithLetter),
+	"cidr":                      stringRule(validateCIDR),
+	"strong_password":           stringRule(validateStrongPassword),
+	"backup_window_format":      stringRule(validateBackupWindow),
+	"maintenance_window_format": stringRule(validateMaintenanceWindow),
+}
+
+// stringRule adapts a string check to a validator. Values of other kinds pass.
+func stringRule(check func(s string) string) validator {
+	return func(v reflect.Value, param string) string {
+		if v.Kind() != reflect.String {
+			return ""
+		}
+		return check(v.String())
+	}
+}
+
+// size returns the length of strings, slices and maps, or the value of
+// numbers, with the unit to use in messages.
+func size(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	default:
+		return 0, "", false
+	}
+}
+
+func validateMin(v reflect.Value, param string) string {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid min parameter %q", param)
+	}
+	if n, unit, ok := size(v); ok && n < limit {
+		return fmt.Sprintf("must be at least %s%s", param, unit)
+	}
+	return ""
+}
+
+func validateMax(v reflect.Value, param string) string {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid max parameter %q", param)
+	}
+	if n, unit, ok := size(v); ok && n > limit {
+		return fmt.Sprintf("must be at most %s%s", param, unit)
+	}
+	return ""
+}
+
+func validateOneOf(v reflect.Value, param string) string {
+	options := strings.Fields(param)
+	value := fmt.Sprint(v.Interface())
+	for _, option := range options {
+		if value == option {
+			return ""
+		}
+	}
+	return fmt.Sprintf("must be one of: %s", strings.Join(options, ", "))
+}
+
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+
+func validateHostname(s string) string {
+	for _, label := range strings.Split(s, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return "must contain only letters, numbers, hyphens and dots, and each part must start and end with a letter or number"
+		}
+	}
+	return ""
+}
+
+func validateLowercase(s string) string {
+	if s != strings.ToLower(s) {
+		return "must not contain uppercase letters"
+	}
+	return ""
+}
+
+func validateBucketName(s string) string {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' {
+			return "must contain only lowercase letters, numbers and hyphens"
+		}
+	}
+	if s == "" || s[0] == '-' || s[len(s)-1] == '-' {
+		return "must start and end with a letter or number"
+	}
+	if strings.HasPrefix(s, "xn--") {
+		return `must not start with "xn--"`
+	}
+	if strings.HasSuffix(s, "-s3alias") {
+		return `must not end with "-s3alias"`
+	}
+	if strings.Contains(s, "--") {
+		return "must not contain consecutive hyphens"
+	}
+	return ""
+}
+
+var alphanumUnderscore = regexp.MustCompile(`^[a-zA-Z0-9_]*$`)
+
+func validateAlphanumUnderscore(s string) string {
+	if !alphanumUnderscore.MatchString(s) {
+		return "must contain only letters, numbers and underscores"
+	}
+	return ""
+}
+
+func validateStartsWithLetter(s string) string {
+	if r, _ := utf8.DecodeRuneInString(s); !unicode.IsLetter(r) {
+		return "must start with a letter"
+	}
+	return ""
+}
+
+func validateCIDR(s string) string {
+	if _, err := netip.ParsePrefix(s); err != nil {
+		return `must be a CIDR block such as "10.0.0.0/16" or "::/0"`
+	}
+	return ""
+}
+
+func validateStrongPassword(s string) string {
+	var upper, lower, digit bool
+	for _, r := range s {
+		switch {
+		case r < 32 || r > 126:
+			return "must contain only printable ASCII characters"
+		case r == '\'' || r == '"' || r == '\\' || r == '/':
+			return "must not contain quotes, backslashes or forward slashes"
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return "must contain uppercase letters, lowercase letters and numbers"
+	}
+	return ""
+}
+
+// minWindow is the shortest backup or maintenance window providers accept.
+const minWindow = 30
+
+var backupWindow = regexp.MustCompile(`^(\d{2}):(\d{2})-(\d{2}):(\d{2})$`)
+
+func validateBackupWindow(s string) string {
+	const format = `must be formatted as "HH:MM-HH:MM" (UTC) and span at least 30 minutes`
+	m := backupWindow.FindStringSubmatch(s)
+	if m == nil {
+		return format
+	}
+	start, ok1 := clockMinutes(m[1], m[2])
+	end, ok2 := clockMinutes(m[3], m[4])
+	if !ok1 || !ok2 {
+		return format
+	}
+	const day = 24 * 60
+	if (end-start+day)%day < minWindow {
+		return format
+	}
+	return ""
+}
+
+var maintenanceWindow = regexp.MustCompile(`^([a-z]{3}):(\d{2}):(\d{2})-([a-z]{3}):(\d{2}):(\d{2})$`)
+
+var weekdays = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+func validateMaintenanceWindow(s string) string {
+	const format = `must be formatted as "ddd:HH:MM-ddd:HH:MM" (UTC, days sun-sat) and span at least 30 minutes`
+	m := maintenanceWindow.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return format
+	}
+	startDay, ok1 := weekdays[m[1]]
+	endDay, ok2 := weekdays[m[4]]
+	startTime, ok3 := clockMinutes(m[2], m[3])
+	endTime, ok4 := clockMinutes(m[5], m[6])
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return format
+	}
+	const day, week = 24 * 60, 7 * 24 * 60
+	start := startDay*day + startTime
+	end := endDay*day + endTime
+	if (end-start+week)%week < minWindow {
+		return format
+	}
+	return ""
+}
+
+// clockMinutes converts "HH" and "MM" to minutes after midnight.
+func clockMinutes(hours, minutes string) (int, bool) {
+	h, err1 := strconv.Atoi(hours)
+	m, err2 := strconv.Atoi(minutes)
+	if err1 != nil || err2 != nil || h > 23 || m > 59 {
+		return 0, false
+	}
+	return h*60 + m, true
+}
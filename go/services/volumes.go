@@ -0,0 +1,281 @@
+package services
+
+import "context"
+
+// VolumeConfig represents the configuration for creating a block volume.
+// A volume lives in one availability zone and can only be attached to VMs
+// in that zone.
+//
+// Example:
+//
+//	config := &VolumeConfig{
+//	    Name:             "data-node-1",
+//	    AvailabilityZone: "us-east-1a",
+//	    SizeGiB:          500,
+//	    VolumeType:       "gp3",
+//	    Encrypted:        aws.Bool(true),
+//	}
+type VolumeConfig struct {
+	// Name is a human-readable name for the volume, stored as its "Name"
+	// tag on AWS.
+	Name string `json:"name,omitempty" yaml:"name,omitempty" validate:"max=255"`
+
+	// AvailabilityZone is the zone to create the volume in, e.g.
+	// "us-east-1a". It must be the zone of the VMs it will be attached to.
+	AvailabilityZone string `json:"availability_zone" yaml:"availability_zone" validate:"required"`
+
+	// SizeGiB is the size of the volume in GiB. It may be left at 0 when
+	// SnapshotID is set, to use the snapshot's size.
+	//
+	// AWS limits: 1-16384 GiB for gp2, gp3, io1 and st1/sc1 (125 GiB
+	// minimum for st1/sc1), up to 65536 GiB for io2
+	SizeGiB int32 `json:"size_gib,omitempty" yaml:"size_gib,omitempty" validate:"max=65536"`
+
+	// VolumeType is the kind of storage, e.g. "gp3", "gp2", "io1", "io2",
+	// "st1" or "sc1" on AWS.
+	//
+	// Default: the provider's default ("gp2" on AWS)
+	VolumeType string `json:"volume_type,omitempty" yaml:"volume_type,omitempty"`
+
+	// Iops is the provisioned I/O operations per second, for volume types
+	// that support it (gp3, io1, io2 on AWS).
+	Iops *int32 `json:"iops,omitempty" yaml:"iops,omitempty"`
+
+	// Throughput is the provisioned throughput in MiB/s, for volume types
+	// that support it (gp3 on AWS).
+	Throughput *int32 `json:"throughput,omitempty" yaml:"throughput,omitempty"`
+
+	// Encrypted enables encryption at rest.
+	//
+	// Default: the account's default encryption setting
+	Encrypted *bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
+
+	// KmsKeyID is the key used to encrypt the volume. Requires Encrypted.
+	//
+	// Default: empty (the provider's default key)
+	KmsKeyID string `json:"kms_key_id,omitempty" yaml:"kms_key_id,omitempty"`
+
+	// SnapshotID creates the volume from a snapshot, e.g. one made by
+	// VolumesService.Snapshot.
+	SnapshotID string `json:"snapshot_id,omitempty" yaml:"snapshot_id,omitempty"`
+
+	// Tags are key-value pairs applied to the volume when it is created.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"max=50,dive,keys,max=255,endkeys,max=255"`
+
+	// IdempotencyKey makes Create safe to retry. See VMConfig.IdempotencyKey.
+	// Maps to the EC2 ClientToken on AWS.
+	IdempotencyKey string `json:"idempotency_key,omitempty" yaml:"idempotency_key,omitempty" validate:"max=64"`
+}
+
+// Volume represents a block volume and its current state.
+type Volume struct {
+	// ID is the unique identifier assigned by the provider, e.g.
+	// "vol-1234567890abcdef0" on AWS.
+	ID string
+
+	// Name is the volume's name, from its "Name" tag on AWS.
+	Name string
+
+	// State is the volume's current state. Common states: "creating",
+	// "available", "in-use", "deleting", "deleted", "error".
+	State string
+
+	// SizeGiB is the size of the volume in GiB.
+	SizeGiB int32
+
+	// VolumeType is the kind of storage, e.g. "gp3".
+	VolumeType string
+
+	// Iops and Throughput are the provisioned performance, or 0 if the
+	// volume type doesn't have any.
+	Iops       int32
+	Throughput int32
+
+	// AvailabilityZone is the zone the volume lives in.
+	AvailabilityZone string
+
+	// Encrypted reports whether the volume is encrypted at rest.
+	Encrypted bool
+
+	// SnapshotID is the snapshot the volume was created from, if any.
+	SnapshotID string
+
+	// Attachments lists the VMs the volume is attached to. Most volumes
+	// have at most one.
+	Attachments []VolumeAttachment
+
+	// Tags are the volume's tags.
+	Tags map[string]string
+
+	// CreateTime indicates when the volume was created.
+	// Format: RFC3339 timestamp
+	CreateTime string
+}
+
+// VolumeAttachment describes a volume attached to a VM. It appears both in
+// Volume.Attachments and in VM.Volumes.
+type VolumeAttachment struct {
+	// VolumeID and VMID identify the volume and the VM.
+	VolumeID string
+	VMID     string
+
+	// Device is the device name the volume is exposed as, e.g. "/dev/sdf".
+	Device string
+
+	// State is the attachment's state: "attaching", "attached",
+	// "detaching" or "detached".
+	State string
+
+	// DeleteOnTermination reports whether the volume is deleted when the
+	// VM is terminated. It is true for root volumes launched with the VM.
+	DeleteOnTermination bool
+}
+
+// VolumeFilter narrows the volumes returned by VolumesService.List. Filters
+// are combined: a volume is listed only if it matches all of them.
+type VolumeFilter struct {
+	// VMID lists only volumes attached to this VM.
+	VMID string
+
+	// AvailabilityZone lists only volumes in this zone.
+	AvailabilityZone string
+
+	// States lists only volumes in one of these states, e.g. "available".
+	States []string
+
+	// Tags lists only volumes that have every tag with the given value.
+	Tags map[string]string
+}
+
+// VolumeSnapshotConfig represents the configuration for snapshotting a volume.
+type VolumeSnapshotConfig struct {
+	// VolumeID is the volume to snapshot.
+	VolumeID string `json:"volume_id" yaml:"volume_id" validate:"required"`
+
+	// Description is a free-form description of the snapshot.
+	Description string `json:"description,omitempty" yaml:"description,omitempty" validate:"max=255"`
+
+	// Tags are key-value pairs applied to the snapshot when it is created.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty" validate:"max=50,dive,keys,max=255,endkeys,max=255"`
+}
+
+// VolumeSnapshot represents a point-in-time copy of a volume.
+type VolumeSnapshot struct {
+	// ID is the unique identifier assigned by the provider, e.g.
+	// "snap-1234567890abcdef0" on AWS.
+	ID string
+
+	// VolumeID is the volume the snapshot was made from.
+	VolumeID string
+
+	// State is the snapshot's state: "pending", "completed" or "error".
+	// A snapshot can be used to create volumes once it is completed.
+	State string
+
+	// Progress is how far along the snapshot is, e.g. "42%".
+	Progress string
+
+	// SizeGiB is the size of the volume the snapshot was made from.
+	SizeGiB int32
+
+	// Description is the snapshot's description.
+	Description string
+
+	// Tags are the snapshot's tags.
+	Tags map[string]string
+
+	// StartTime indicates when the snapshot was started.
+	// Format: RFC3339 timestamp
+	StartTime string
+}
+
+// VolumesService provides operations for managing block volumes: creating
+// them, attaching them to VMs and snapshotting them.
+type VolumesService interface {
+	// Create creates a new volume. The volume starts in the "creating" state
+	// and can be attached once it is "available"; see
+	// WaitUntilVolumeAvailable.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: Invalid size, type, zone or performance settings
+	//   - ErrQuotaExceeded: Volume or storage limits reached
+	//   - ErrResourceConflict: Idempotency key reused with a different configuration
+	//
+	// Example:
+	//   volume, err := compute.Volumes().Create(ctx, &VolumeConfig{
+	//       Name:             "data-node-1",
+	//       AvailabilityZone: "us-east-1a",
+	//       SizeGiB:          500,
+	//       VolumeType:       "gp3",
+	//   })
+	//   if err != nil {
+	//       log.Fatalf("Failed to create volume: %v", err)
+	//   }
+	Create(ctx context.Context, config *VolumeConfig) (*Volume, error)
+
+	// List returns the volumes in the current region that match filter, or
+	// all of them if filter is nil. Returns an empty slice if none match.
+	//
+	// Example:
+	//   volumes, err := compute.Volumes().List(ctx, &VolumeFilter{VMID: vm.ID})
+	//   for _, volume := range volumes {
+	//       fmt.Printf("%s: %d GiB %s\n", volume.ID, volume.SizeGiB, volume.VolumeType)
+	//   }
+	List(ctx context.Context, filter *VolumeFilter) ([]*Volume, error)
+
+	// Get retrieves a volume by ID.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Volume doesn't exist
+	Get(ctx context.Context, id string) (*Volume, error)
+
+	// Attach attaches an available volume to a VM in the same availability
+	// zone, exposing it as device (e.g. "/dev/sdf"). The attachment completes
+	// asynchronously; see WaitUntilVolumeAttached.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Volume or VM doesn't exist
+	//   - ErrInvalidState: Volume is not available, or is in another zone
+	//   - ErrResourceConflict: Device name already in use on the VM
+	//
+	// Example:
+	//   err := compute.Volumes().Attach(ctx, volume.ID, vm.ID, "/dev/sdf")
+	Attach(ctx context.Context, volumeID, vmID, device string) error
+
+	// Detach detaches a volume from the VM it is attached to. Unmount the
+	// volume's file systems first to avoid losing data. The volume becomes
+	// "available" again asynchronously.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Volume doesn't exist
+	//   - ErrInvalidState: Volume is not attached
+	Detach(ctx context.Context, volumeID string) error
+
+	// Resize grows a volume to sizeGiB. Volumes can't shrink. The new size
+	// is usable once the provider finishes optimizing the volume, and the
+	// file system on it must be extended separately.
+	//
+	// Common errors:
+	//   - ErrInvalidConfig: sizeGiB is smaller than the current size
+	//   - ErrInvalidState: Volume is being modified already
+	Resize(ctx context.Context, volumeID string, sizeGiB int32) error
+
+	// Snapshot starts a point-in-time snapshot of a volume and returns it
+	// in the "pending" state. The volume can be used while the snapshot
+	// completes.
+	//
+	// Example:
+	//   snapshot, err := compute.Volumes().Snapshot(ctx, &VolumeSnapshotConfig{
+	//       VolumeID:    volume.ID,
+	//       Description: "nightly backup",
+	//   })
+	Snapshot(ctx context.Context, config *VolumeSnapshotConfig) (*VolumeSnapshot, error)
+
+	// Delete permanently deletes a volume and its data. The volume must be
+	// detached first.
+	//
+	// Common errors:
+	//   - ErrResourceNotFound: Volume doesn't exist
+	//   - ErrInvalidState: Volume is still attached
+	Delete(ctx context.Context, volumeID string) error
+}
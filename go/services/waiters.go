@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Default polling intervals of the waiters. The delay between polls starts at
+// the minimum and doubles, with jitter, up to the maximum.
+const (
+	DefaultWaitMinDelay = 2 * time.Second
+	DefaultWaitMaxDelay = 30 * time.Second
+)
+
+// ErrTerminalState is matched with errors.Is by the error of a waiter whose
+// resource entered a state from which it can't reach the target state, such
+// as a database in "failed" or a spot request in "cancelled".
+var ErrTerminalState = errors.New("resource reached a terminal state")
+
+// WaitProgress reports one poll of a waiter.
+type WaitProgress struct {
+	// ResourceType and ResourceID identify the resource being waited on,
+	// e.g. "VM" and "i-1234567890abcdef0".
+	ResourceType string
+	ResourceID   string
+
+	// Target is the state being waited for, e.g. "running".
+	Target string
+
+	// State is the resource's state at this poll, or empty if the poll
+	// failed or the resource wasn't found.
+	State string
+
+	// Attempt is the number of the poll, starting at 1.
+	Attempt int
+
+	// Elapsed is the time since the waiter started.
+	Elapsed time.Duration
+
+	// Err is the error of a poll that failed transiently, or that didn't
+	// find the resource yet. The waiter keeps polling after it.
+	Err error
+}
+
+// WaitOptions configures a waiter.
+type WaitOptions struct {
+	// MinDelay and MaxDelay bound the delay between polls.
+	//
+	// Default: DefaultWaitMinDelay and DefaultWaitMaxDelay
+	MinDelay time.Duration
+	MaxDelay time.Duration
+
+	// OnProgress, if set, is called after every poll.
+	OnProgress func(WaitProgress)
+}
+
+// WaitOption sets a field of WaitOptions.
+type WaitOption func(*WaitOptions)
+
+// WithPollInterval sets the minimum and maximum delay between polls.
+func WithPollInterval(minDelay, maxDelay time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.MinDelay = minDelay
+		o.MaxDelay = maxDelay
+	}
+}
+
+// WithProgress calls fn after every poll of a waiter.
+func WithProgress(fn func(WaitProgress)) WaitOption {
+	return func(o *WaitOptions) {
+		o.OnProgress = fn
+	}
+}
+
+// WaitError is returned by a waiter that gave up: because its resource
+// reached a terminal state, because a poll failed, or because its context
+// ended. errors.Is matches ErrTerminalState, the context's error, or the
+// error of the failed poll.
+type WaitError struct {
+	ResourceType string
+	ResourceID   string
+	Target       string
+
+	// State is the last state the resource was seen in, or empty if it was
+	// never seen.
+	State string
+
+	// Err is ErrTerminalState, the context's error or the poll's error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *WaitError) Error() string {
+	msg := fmt.Sprintf("waiting for %s %s to be %s", e.ResourceType, e.ResourceID, e.Target)
+	if e.State != "" {
+		msg += fmt.Sprintf(" (last state %q)", e.State)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns Err.
+func (e *WaitError) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound reports whether err reports a resource that doesn't exist, such
+// as a cloudsdk.CloudError with code ErrResourceNotFound. Errors report this
+// by implementing NotFound() bool.
+func IsNotFound(err error) bool {
+	var notFound interface{ NotFound() bool }
+	return errors.As(err, &notFound) && notFound.NotFound()
+}
+
+// isTemporary reports whether err is a transient failure, such as throttling
+// or a timeout, that a later poll may not hit. Errors report this by
+// implementing Temporary() bool.
+func isTemporary(err error) bool {
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+// waiter polls a resource until it reaches its target state.
+type waiter struct {
+	resourceType string
+	resourceID   string
+	target       string
+
+	// poll returns the resource's current state.
+	poll func(ctx context.Context) (string, error)
+
+	// done reports whether state is the target state.
+	done func(state string) bool
+
+	// terminal lists states from which the target can't be reached.
+	terminal []string
+
+	// notFoundIsDone makes a missing resource count as reaching the target,
+	// as for deletions. Otherwise the waiter keeps polling, because new
+	// resources may not be visible right away.
+	notFoundIsDone bool
+}
+
+func (w *waiter) wait(ctx context.Context, opts []WaitOption) error {
+	options := WaitOptions{MinDelay: DefaultWaitMinDelay, MaxDelay: DefaultWaitMaxDelay}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.MinDelay <= 0 {
+		options.MinDelay = DefaultWaitMinDelay
+	}
+	if options.MaxDelay < options.MinDelay {
+		options.MaxDelay = options.MinDelay
+	}
+
+	start := time.Now()
+	delay := options.MinDelay
+	lastState := ""
+	for attempt := 1; ; attempt++ {
+		state, err := w.poll(ctx)
+		if err == nil {
+			lastState = state
+		}
+		if options.OnProgress != nil {
+			options.OnProgress(WaitProgress{
+				ResourceType: w.resourceType,
+				ResourceID:   w.resourceID,
+				Target:       w.target,
+				State:        state,
+				Attempt:      attempt,
+				Elapsed:      time.Since(start),
+				Err:          err,
+			})
+		}
+
+		switch {
+		case err == nil && w.done(state):
+			return nil
+		case err == nil && contains(w.terminal, state):
+			return w.fail(lastState, ErrTerminalState)
+		case IsNotFound(err) && w.notFoundIsDone:
+			return nil
+		case err != nil && ctx.Err() != nil:
+			return w.fail(lastState, ctx.Err())
+		case err != nil && !IsNotFound(err) && !isTemporary(err):
+			return w.fail(lastState, err)
+		}
+
+		// Equal jitter: wait between half and all of the delay
+		sleep := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		timer := time.NewTimer(sleep)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return w.fail(lastState, ctx.Err())
+		case <-timer.C:
+		}
+		if delay *= 2; delay > options.MaxDelay {
+			delay = options.MaxDelay
+		}
+	}
+}
+
+func (w *waiter) fail(state string, err error) error {
+	return &WaitError{
+		ResourceType: w.resourceType,
+		ResourceID:   w.resourceID,
+		Target:       w.target,
+		State:        state,
+		Err:          err,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// vmWaiter waits for a VM to reach target, failing if it enters one of the
+// terminal states.
+func vmWaiter(compute Compute, id, target string, terminal ...string) *waiter {
+	return &waiter{
+		resourceType: "VM",
+		resourceID:   id,
+		target:       target,
+		poll: func(ctx context.Context) (string, error) {
+			vm, err := compute.GetVM(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return vm.State, nil
+		},
+		done:     func(state string) bool { return state == target },
+		terminal: terminal,
+	}
+}
+
+// WaitUntilVMRunning polls compute until the VM is running. It fails with
+// ErrTerminalState if the VM is shutting down or terminated, and otherwise
+// polls until ctx ends. It works with any Compute implementation.
+//
+// Example:
+//
+//	vm, err := client.Compute().CreateVM(ctx, config)
+//	if err != nil {
+//	    return err
+//	}
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+//	defer cancel()
+//	err = services.WaitUntilVMRunning(ctx, client.Compute(), vm.ID,
+//	    services.WithProgress(func(p services.WaitProgress) {
+//	        log.Printf("%s is %s after %v", p.ResourceID, p.State, p.Elapsed)
+//	    }))
+func WaitUntilVMRunning(ctx context.Context, compute Compute, id string, opts ...WaitOption) error {
+	return vmWaiter(compute, id, "running", "shutting-down", "terminated").wait(ctx, opts)
+}
+
+// WaitUntilVMStopped polls compute until the VM is stopped. It fails with
+// ErrTerminalState if the VM is shutting down or terminated.
+func WaitUntilVMStopped(ctx context.Context, compute Compute, id string, opts ...WaitOption) error {
+	return vmWaiter(compute, id, "stopped", "shutting-down", "terminated").wait(ctx, opts)
+}
+
+// WaitUntilVMTerminated polls compute until the VM is terminated or no
+// longer exists.
+func WaitUntilVMTerminated(ctx context.Context, compute Compute, id string, opts ...WaitOption) error {
+	w := vmWaiter(compute, id, "terminated")
+	w.notFoundIsDone = true
+	return w.wait(ctx, opts)
+}
+
+// WaitUntilSpotFulfilled polls compute until the spot instance request has
+// launched an instance, and returns the request. It fails with
+// ErrTerminalState if the request is cancelled, closed or failed, or if AWS
+// reports a status from which it won't be fulfilled, such as
+// "bad-parameters" or "incompatible-parameters".
+func WaitUntilSpotFulfilled(ctx context.Context, compute Compute, requestID string, opts ...WaitOption) (*SpotInstanceRequest, error) {
+	var request *SpotInstanceRequest
+	err := (&waiter{
+		resourceType: "spot instance request",
+		resourceID:   requestID,
+		target:       "fulfilled",
+		poll: func(ctx context.Context) (string, error) {
+			requests, err := compute.SpotInstances().Describe(ctx, []string{requestID})
+			if err != nil {
+				return "", err
+			}
+			for _, r := range requests {
+				if r.SpotInstanceRequestId == requestID {
+					request = r
+					if contains(spotTerminalStatuses, r.Status) {
+						return r.Status, nil
+					}
+					return r.State, nil
+				}
+			}
+			return "", errSpotRequestNotFound
+		},
+		done: func(state string) bool {
+			return state == "active" && request.InstanceId != ""
+		},
+		terminal: append([]string{"cancelled", "closed", "failed"}, spotTerminalStatuses...),
+	}).wait(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// spotTerminalStatuses are spot request status codes after which the request
+// won't be fulfilled.
+var spotTerminalStatuses = []string{
+	"bad-parameters",
+	"incompatible-parameters",
+	"canceled-before-fulfillment",
+	"schedule-expired",
+	"system-error",
+}
+
+// notFoundError is a missing resource found by a waiter itself.
+type notFoundError string
+
+func (e notFoundError) Error() string  { return string(e) }
+func (e notFoundError) NotFound() bool { return true }
+
+var errSpotRequestNotFound = notFoundError("spot instance request not found")
+
+// dbWaiter waits for a database to reach target.
+func dbWaiter(database Database, id, target string, terminal ...string) *waiter {
+	return &waiter{
+		resourceType: "database",
+		resourceID:   id,
+		target:       target,
+		poll: func(ctx context.Context) (string, error) {
+			db, err := database.GetDB(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return db.Status, nil
+		},
+		done:     func(state string) bool { return state == target },
+		terminal: terminal,
+	}
+}
+
+// WaitUntilDBAvailable polls database until the instance is available. It
+// fails with ErrTerminalState if the instance enters a state it won't recover
+// from by itself, such as "failed", "deleting" or "incompatible-parameters".
+func WaitUntilDBAvailable(ctx context.Context, database Database, id string, opts ...WaitOption) error {
+	return dbWaiter(database, id, "available",
+		"failed", "deleting", "deleted", "storage-full", "restore-error",
+		"incompatible-parameters", "incompatible-network", "incompatible-option-group",
+		"incompatible-restore", "inaccessible-encryption-credentials",
+	).wait(ctx, opts)
+}
+
+// WaitUntilDBDeleted polls database until the instance no longer exists.
+func WaitUntilDBDeleted(ctx context.Context, database Database, id string, opts ...WaitOption) error {
+	w := dbWaiter(database, id, "deleted")
+	w.notFoundIsDone = true
+	return w.wait(ctx, opts)
+}
+
+// volumeWaiter waits for a volume to reach target.
+func volumeWaiter(compute Compute, id, target string, terminal ...string) *waiter {
+	return &waiter{
+		resourceType: "volume",
+		resourceID:   id,
+		target:       target,
+		poll: func(ctx context.Context) (string, error) {
+			volume, err := compute.Volumes().Get(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return volume.State, nil
+		},
+		done:     func(state string) bool { return state == target },
+		terminal: terminal,
+	}
+}
+
+// WaitUntilVolumeAvailable polls compute until the volume is available: it
+// has been created, or detached from its VM. It fails with ErrTerminalState
+// if the volume is in error or being deleted.
+func WaitUntilVolumeAvailable(ctx context.Context, compute Compute, id string, opts ...WaitOption) error {
+	return volumeWaiter(compute, id, "available", "error", "deleting", "deleted").wait(ctx, opts)
+}
+
+// WaitUntilVolumeAttached polls compute until the volume's attachment to a
+// VM has completed.
+func WaitUntilVolumeAttached(ctx context.Context, compute Compute, id string, opts ...WaitOption) error {
+	w := volumeWaiter(compute, id, "attached", "error", "deleting", "deleted")
+	w.poll = func(ctx context.Context) (string, error) {
+		volume, err := compute.Volumes().Get(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if len(volume.Attachments) == 0 {
+			return volume.State, nil
+		}
+		return volume.Attachments[0].State, nil
+	}
+	return w.wait(ctx, opts)
+}
+
+// WaitUntilImageAvailable polls compute until the image is available, for
+// example after ImagesService.Create. It fails with ErrTerminalState if the
+// image failed or was deregistered.
+func WaitUntilImageAvailable(ctx context.Context, compute Compute, id string, opts ...WaitOption) error {
+	return (&waiter{
+		resourceType: "image",
+		resourceID:   id,
+		target:       "available",
+		poll: func(ctx context.Context) (string, error) {
+			image, err := compute.Images().Get(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return image.State, nil
+		},
+		done:     func(state string) bool { return state == "available" },
+		terminal: []string{"failed", "error", "invalid", "deregistered"},
+	}).wait(ctx, opts)
+}
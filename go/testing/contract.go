@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -29,25 +30,67 @@ func NewProviderContractSuite(t *testing.T, provider cloudsdk.Provider) *Provide
 	}
 }
 
-// RunAllTests runs all contract tests for the provider
+// RunAllTests runs all contract tests for the provider.
+// Cases that need an operation or configuration field the provider's
+// Capabilities report as unsupported are skipped.
 func (s *ProviderContractSuite) RunAllTests() {
-	s.t.Run("ProviderInterface", func(t *testing.T) { s.TestProviderInterface() })
-	s.t.Run("ServiceAvailability", func(t *testing.T) { s.TestServiceAvailability() })
+	s.t.Run("ProviderInterface", func(t *testing.T) { s.withT(t).TestProviderInterface() })
+	s.t.Run("ServiceAvailability", func(t *testing.T) { s.withT(t).TestServiceAvailability() })
+	s.t.Run("Capabilities", func(t *testing.T) { s.withT(t).TestCapabilities() })
 
 	// Test each supported service
 	supportedServices := s.provider.SupportedServices()
 	for _, serviceType := range supportedServices {
 		switch serviceType {
 		case cloudsdk.ServiceCompute:
-			s.t.Run("ComputeService", func(t *testing.T) { s.TestComputeService() })
+			s.t.Run("ComputeService", func(t *testing.T) { s.withT(t).TestComputeService() })
+			s.t.Run("PlacementGroups", func(t *testing.T) { s.withT(t).TestPlacementGroups() })
+			s.t.Run("SpotInstances", func(t *testing.T) { s.withT(t).TestSpotInstances() })
 		case cloudsdk.ServiceStorage:
-			s.t.Run("StorageService", func(t *testing.T) { s.TestStorageService() })
+			s.t.Run("StorageService", func(t *testing.T) { s.withT(t).TestStorageService() })
+			s.t.Run("BucketReplication", func(t *testing.T) { s.withT(t).TestBucketReplication() })
 		case cloudsdk.ServiceDatabase:
-			s.t.Run("DatabaseService", func(t *testing.T) { s.TestDatabaseService() })
+			s.t.Run("DatabaseService", func(t *testing.T) { s.withT(t).TestDatabaseService() })
+			s.t.Run("MultiAZDatabase", func(t *testing.T) { s.withT(t).TestMultiAZDatabase() })
 		}
 	}
 }
 
+// withT returns a copy of the suite that reports to t, so that failures and
+// skips apply to the subtest being run.
+func (s *ProviderContractSuite) withT(t *testing.T) *ProviderContractSuite {
+	suite := *s
+	suite.t = t
+	return &suite
+}
+
+// skipUnlessSupported skips the test unless the provider supports every
+// listed operation.
+func (s *ProviderContractSuite) skipUnlessSupported(operations ...string) {
+	caps := s.provider.Capabilities()
+	for _, operation := range operations {
+		if !caps.SupportsOperation(operation) {
+			s.t.Skipf("Operation %s not supported by provider", operation)
+		}
+	}
+}
+
+// skipUnlessConfigSupported skips the test if config sets fields that the
+// provider can't apply for operation.
+func (s *ProviderContractSuite) skipUnlessConfigSupported(operation string, config interface{}) {
+	s.skipUnlessSupported(operation)
+	if fields := s.provider.Capabilities().UnsupportedFieldsSet(operation, config); len(fields) > 0 {
+		s.t.Skipf("Fields %v of %s not supported by provider", fields, operation)
+	}
+}
+
+// supportsTags reports whether the provider can tag resources created by
+// operation. Tags only label contract test resources, so they are left out
+// for providers that can't apply them.
+func (s *ProviderContractSuite) supportsTags(operation string) bool {
+	return s.provider.Capabilities().SupportsField(operation, "Tags")
+}
+
 // TestProviderInterface tests the basic provider interface
 func (s *ProviderContractSuite) TestProviderInterface() {
 	// Test Name() method
@@ -126,6 +169,58 @@ func (s *ProviderContractSuite) TestServiceAvailability() {
 			}
 		}
 	}
+
+	// Test non-panicking accessors agree with the supported service list
+	for _, serviceType := range allServices {
+		supported := s.isServiceSupported(serviceType)
+		if s.client.Supports(serviceType) != supported {
+			s.t.Errorf("Client.Supports(%s) = %v, want %v", serviceType, !supported, supported)
+		}
+
+		var err error
+		switch serviceType {
+		case cloudsdk.ServiceCompute:
+			_, err = s.client.TryCompute()
+		case cloudsdk.ServiceStorage:
+			_, err = s.client.TryStorage()
+		case cloudsdk.ServiceDatabase:
+			_, err = s.client.TryDatabase()
+		}
+
+		if supported {
+			if err != nil {
+				s.t.Errorf("Try accessor for supported service %s failed: %v", serviceType, err)
+			}
+			continue
+		}
+
+		var cloudErr *cloudsdk.CloudError
+		if !errors.As(err, &cloudErr) || cloudErr.Code != cloudsdk.ErrServiceNotSupported {
+			s.t.Errorf("Try accessor for unsupported service %s returned %v, want %s", serviceType, err, cloudsdk.ErrServiceNotSupported)
+		}
+	}
+}
+
+// TestCapabilities tests that the provider's Capabilities agree with its
+// supported services
+func (s *ProviderContractSuite) TestCapabilities() {
+	caps := s.provider.Capabilities()
+
+	allServices := []cloudsdk.ServiceType{
+		cloudsdk.ServiceCompute,
+		cloudsdk.ServiceStorage,
+		cloudsdk.ServiceDatabase,
+	}
+	for _, serviceType := range allServices {
+		if s.isServiceSupported(serviceType) {
+			continue
+		}
+		for _, operation := range cloudsdk.ServiceOperations(serviceType) {
+			if caps.SupportsOperation(operation) {
+				s.t.Errorf("Capabilities report %s as supported, but service %s is not", operation, serviceType)
+			}
+		}
+	}
 }
 
 // TestComputeService tests the compute service contract
@@ -134,6 +229,8 @@ func (s *ProviderContractSuite) TestComputeService() {
 		s.t.Skip("Compute service not supported by provider")
 	}
 
+	s.skipUnlessSupported(cloudsdk.OpListVMs)
+
 	compute := s.client.Compute()
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
@@ -160,6 +257,8 @@ func (s *ProviderContractSuite) TestStorageService() {
 		s.t.Skip("Storage service not supported by provider")
 	}
 
+	s.skipUnlessSupported(cloudsdk.OpListBuckets)
+
 	storage := s.client.Storage()
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
@@ -186,6 +285,8 @@ func (s *ProviderContractSuite) TestDatabaseService() {
 		s.t.Skip("Database service not supported by provider")
 	}
 
+	s.skipUnlessSupported(cloudsdk.OpListDBs)
+
 	database := s.client.Database()
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
@@ -210,6 +311,11 @@ func (s *ProviderContractSuite) TestDatabaseService() {
 func (s *ProviderContractSuite) testVMLifecycle(compute services.Compute, ctx context.Context) {
 	// Create VM
 	config := GenerateVMConfig("contract-test-vm")
+	if !s.supportsTags(cloudsdk.OpCreateVM) {
+		config.Tags = nil
+	}
+	s.skipUnlessSupported(cloudsdk.OpGetVM, cloudsdk.OpDeleteVM)
+	s.skipUnlessConfigSupported(cloudsdk.OpCreateVM, config)
 	vm, err := compute.CreateVM(ctx, config)
 	if err != nil {
 		s.t.Errorf("CreateVM failed: %v", err)
@@ -270,7 +376,13 @@ func (s *ProviderContractSuite) testVMLifecycle(compute services.Compute, ctx co
 
 // testVMStateOperations tests VM start/stop operations
 func (s *ProviderContractSuite) testVMStateOperations(compute services.Compute, ctx context.Context, vmID string) {
-	// Try to stop VM (may not be supported by all providers)
+	caps := s.provider.Capabilities()
+	if !caps.SupportsOperation(cloudsdk.OpStopVM) || !caps.SupportsOperation(cloudsdk.OpStartVM) {
+		s.t.Log("StopVM and StartVM not supported by provider")
+		return
+	}
+
+	// Try to stop VM
 	if err := compute.StopVM(ctx, vmID); err != nil {
 		s.t.Logf("StopVM not supported or failed: %v", err)
 		return
@@ -288,6 +400,12 @@ func (s *ProviderContractSuite) testStorageLifecycle(storage services.Storage, c
 
 	// Create bucket
 	config := GenerateBucketConfig(bucketName)
+	if !s.supportsTags(cloudsdk.OpCreateBucket) {
+		config.Tags = nil
+	}
+	s.skipUnlessSupported(cloudsdk.OpDeleteBucket, cloudsdk.OpPutObject, cloudsdk.OpGetObject,
+		cloudsdk.OpListObjects, cloudsdk.OpDeleteObject)
+	s.skipUnlessConfigSupported(cloudsdk.OpCreateBucket, config)
 	if err := storage.CreateBucket(ctx, config); err != nil {
 		s.t.Errorf("CreateBucket failed: %v", err)
 		return
@@ -376,6 +494,11 @@ func (s *ProviderContractSuite) testDatabaseLifecycle(database services.Database
 
 	// Create database
 	config := GenerateDBConfig(dbName)
+	if !s.supportsTags(cloudsdk.OpCreateDB) {
+		config.Tags = nil
+	}
+	s.skipUnlessSupported(cloudsdk.OpGetDB, cloudsdk.OpDeleteDB)
+	s.skipUnlessConfigSupported(cloudsdk.OpCreateDB, config)
 	db, err := database.CreateDB(ctx, config)
 	if err != nil {
 		s.t.Errorf("CreateDB failed: %v", err)
@@ -431,6 +554,118 @@ func (s *ProviderContractSuite) testDatabaseLifecycle(database services.Database
 	}
 }
 
+// TestPlacementGroups tests the placement groups sub-service contract
+func (s *ProviderContractSuite) TestPlacementGroups() {
+	s.skipUnlessSupported(cloudsdk.OpCreatePlacementGroup, cloudsdk.OpListPlacementGroups, cloudsdk.OpDeletePlacementGroup)
+
+	groups := s.client.Compute().PlacementGroups()
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	group, err := groups.Create(ctx, &services.PlacementGroupConfig{
+		GroupName: "contract-test-pg",
+		Strategy:  "spread",
+	})
+	if err != nil {
+		s.t.Errorf("CreatePlacementGroup failed: %v", err)
+		return
+	}
+	AssertEqual(s.t, "contract-test-pg", group.GroupName)
+
+	if _, err := groups.List(ctx); err != nil {
+		s.t.Errorf("ListPlacementGroups failed: %v", err)
+	}
+
+	if err := groups.Delete(ctx, group.GroupName); err != nil {
+		s.t.Errorf("DeletePlacementGroup failed: %v", err)
+	}
+}
+
+// TestSpotInstances tests the spot instances sub-service contract
+func (s *ProviderContractSuite) TestSpotInstances() {
+	s.skipUnlessSupported(cloudsdk.OpRequestSpotInstances, cloudsdk.OpCancelSpotInstanceRequests)
+
+	spot := s.client.Compute().SpotInstances()
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	request, err := spot.Request(ctx, &services.SpotInstanceConfig{
+		ImageID:      GenerateImageID(),
+		InstanceType: GenerateInstanceType(),
+	})
+	if err != nil {
+		s.t.Errorf("RequestSpotInstances failed: %v", err)
+		return
+	}
+	if request.SpotInstanceRequestId == "" {
+		s.t.Error("RequestSpotInstances returned an empty request ID")
+	}
+
+	if err := spot.Cancel(ctx, request.SpotInstanceRequestId); err != nil {
+		s.t.Errorf("CancelSpotInstanceRequests failed: %v", err)
+	}
+}
+
+// TestBucketReplication tests creating a bucket with a replication configuration
+func (s *ProviderContractSuite) TestBucketReplication() {
+	bucketName := GenerateBucketName("contract-repl")
+	config := GenerateBucketConfig(bucketName)
+	if !s.supportsTags(cloudsdk.OpCreateBucket) {
+		config.Tags = nil
+	}
+	versioning := true
+	config.Versioning = &versioning
+	config.ReplicationConfig = &services.ReplicationConfiguration{
+		Role: "arn:aws:iam::123456789012:role/contract-test-replication",
+		Rules: []services.ReplicationRule{{
+			ID:          "replicate-all",
+			Status:      "Enabled",
+			Destination: services.ReplicationDestination{Bucket: "arn:aws:s3:::" + bucketName + "-replica"},
+		}},
+	}
+	s.skipUnlessSupported(cloudsdk.OpDeleteBucket)
+	s.skipUnlessConfigSupported(cloudsdk.OpCreateBucket, config)
+
+	storage := s.client.Storage()
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := storage.CreateBucket(ctx, config); err != nil {
+		s.t.Errorf("CreateBucket with replication failed: %v", err)
+		return
+	}
+	if err := storage.DeleteBucket(ctx, bucketName); err != nil {
+		s.t.Errorf("DeleteBucket failed: %v", err)
+	}
+}
+
+// TestMultiAZDatabase tests creating a multi-AZ database
+func (s *ProviderContractSuite) TestMultiAZDatabase() {
+	config := GenerateDBConfig("contract-test-multi-az")
+	if !s.supportsTags(cloudsdk.OpCreateDB) {
+		config.Tags = nil
+	}
+	multiAZ := true
+	config.MultiAZ = &multiAZ
+	s.skipUnlessSupported(cloudsdk.OpDeleteDB)
+	s.skipUnlessConfigSupported(cloudsdk.OpCreateDB, config)
+
+	database := s.client.Database()
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	db, err := database.CreateDB(ctx, config)
+	if err != nil {
+		s.t.Errorf("CreateDB with MultiAZ failed: %v", err)
+		return
+	}
+	AssertDBValid(s.t, db)
+
+	if err := database.DeleteDB(ctx, db.ID); err != nil {
+		s.t.Errorf("DeleteDB failed: %v", err)
+	}
+}
+
 // isServiceSupported checks if a service is supported by the provider
 func (s *ProviderContractSuite) isServiceSupported(serviceType cloudsdk.ServiceType) bool {
 	supportedServices := s.provider.SupportedServices()
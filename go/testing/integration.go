@@ -187,60 +187,57 @@ func (s *IntegrationSuite) AssertDBAvailable(dbID string) {
 	}
 }
 
-// WaitForVMState waits for a VM to reach the specified state
+// WaitForVMState waits for a VM to reach the specified state, one of
+// "running", "stopped" or "terminated", using the services waiters
 func (s *IntegrationSuite) WaitForVMState(vmID string, expectedState string) {
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
 
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			s.t.Fatalf("Timeout waiting for VM %s to reach state %s", vmID, expectedState)
-		case <-ticker.C:
-			vm, err := s.client.Compute().GetVM(ctx, vmID)
-			if err != nil {
-				s.t.Logf("Error getting VM %s: %v", vmID, err)
-				continue
-			}
-
-			if vm.State == expectedState {
-				return // Success
-			}
-
-			s.t.Logf("VM %s current state: %s, waiting for: %s", vmID, vm.State, expectedState)
-		}
+	var err error
+	switch expectedState {
+	case "running":
+		err = services.WaitUntilVMRunning(ctx, s.client.Compute(), vmID, s.logProgress())
+	case "stopped":
+		err = services.WaitUntilVMStopped(ctx, s.client.Compute(), vmID, s.logProgress())
+	case "terminated":
+		err = services.WaitUntilVMTerminated(ctx, s.client.Compute(), vmID, s.logProgress())
+	default:
+		s.t.Fatalf("No waiter for VM state %s", expectedState)
+	}
+	if err != nil {
+		s.t.Fatalf("Failed waiting for VM %s to reach state %s: %v", vmID, expectedState, err)
 	}
 }
 
-// WaitForDBState waits for a database to reach the specified state
+// WaitForDBState waits for a database to reach the specified state, one of
+// "available" or "deleted", using the services waiters
 func (s *IntegrationSuite) WaitForDBState(dbID string, expectedState string) {
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
 
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			s.t.Fatalf("Timeout waiting for database %s to reach state %s", dbID, expectedState)
-		case <-ticker.C:
-			db, err := s.client.Database().GetDB(ctx, dbID)
-			if err != nil {
-				s.t.Logf("Error getting database %s: %v", dbID, err)
-				continue
-			}
-
-			if db.Status == expectedState {
-				return // Success
-			}
+	var err error
+	switch expectedState {
+	case "available":
+		err = services.WaitUntilDBAvailable(ctx, s.client.Database(), dbID, s.logProgress())
+	case "deleted":
+		err = services.WaitUntilDBDeleted(ctx, s.client.Database(), dbID, s.logProgress())
+	default:
+		s.t.Fatalf("No waiter for database state %s", expectedState)
+	}
+	if err != nil {
+		s.t.Fatalf("Failed waiting for database %s to reach state %s: %v", dbID, expectedState, err)
+	}
+}
 
-			s.t.Logf("Database %s current state: %s, waiting for: %s", dbID, db.Status, expectedState)
+// logProgress logs each poll of a waiter to the test log
+func (s *IntegrationSuite) logProgress() services.WaitOption {
+	return services.WithProgress(func(p services.WaitProgress) {
+		if p.Err != nil {
+			s.t.Logf("Error getting %s %s: %v", p.ResourceType, p.ResourceID, p.Err)
+			return
 		}
-	}
+		s.t.Logf("%s %s current state: %s, waiting for: %s", p.ResourceType, p.ResourceID, p.State, p.Target)
+	})
 }
 
 // Cleanup removes all created resources
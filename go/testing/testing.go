@@ -77,6 +77,7 @@
 package testing
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -116,7 +117,8 @@ func (h *TestHelper) AssertErrorCode(err error, expectedCode cloudsdk.ErrorCode)
 		h.t.Fatal("Expected an error, got nil")
 	}
 
-	if cloudErr, ok := err.(*cloudsdk.CloudError); ok {
+	var cloudErr *cloudsdk.CloudError
+	if errors.As(err, &cloudErr) {
 		if cloudErr.Code != expectedCode {
 			h.t.Fatalf("Expected error code %s, got %s", expectedCode, cloudErr.Code)
 		}
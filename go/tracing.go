@@ -0,0 +1,260 @@
+package cloudsdk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// Span attribute keys set by the Client and by providers.
+const (
+	AttrProvider   = "cloudsdk.provider"
+	AttrService    = "cloudsdk.service"
+	AttrOperation  = "cloudsdk.operation"
+	AttrResourceID = "cloudsdk.resource_id"
+	AttrRetryCount = "cloudsdk.retry_count"
+	AttrAttempt    = "cloudsdk.attempt"
+	AttrErrorCode  = "cloudsdk.error_code"
+)
+
+// Attribute is a key-value pair attached to a span.
+// Values are strings, bools, ints, int64s or float64s.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// StringAttr returns a string-valued attribute.
+func StringAttr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// IntAttr returns an int-valued attribute.
+func IntAttr(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a single traced unit of work, such as one service operation or one
+// provider API attempt.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+
+	// End completes the span. It is called exactly once.
+	End()
+}
+
+// Tracer starts spans. Set Config.Tracer to trace every operation made
+// through a Client; see the tracing/otel package for an OpenTelemetry adapter.
+//
+// The Client starts one span per service operation, named
+// "cloudsdk.<service>.<operation>", and passes the tracer down in the context
+// so providers can start a child span for each API attempt.
+type Tracer interface {
+	// Start begins a span that is a child of any span carried in ctx and
+	// returns a context carrying the new span.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type tracerKey struct{}
+
+// ContextWithTracer returns a copy of ctx that carries tracer.
+// The Client does this for every operation; providers don't need to.
+func ContextWithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// TracerFromContext returns the Tracer carried in ctx, or nil if there is none.
+func TracerFromContext(ctx context.Context) Tracer {
+	tracer, _ := ctx.Value(tracerKey{}).(Tracer)
+	return tracer
+}
+
+// StartSpan starts a span with the Tracer carried in ctx. If ctx carries no
+// tracer it returns ctx unchanged and a span that does nothing, so providers
+// can call it unconditionally.
+//
+// Example:
+//
+//	ctx, span := cloudsdk.StartSpan(ctx, "aws.compute.RunInstances",
+//	    cloudsdk.StringAttr(cloudsdk.AttrOperation, "RunInstances"),
+//	    cloudsdk.IntAttr(cloudsdk.AttrAttempt, attempt))
+//	defer span.End()
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	tracer := TracerFromContext(ctx)
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, name, attrs...)
+}
+
+// noopSpan is returned by StartSpan when no tracer is configured.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// operationStats collects what providers report about a single Client operation.
+type operationStats struct {
+	retries atomic.Int32
+}
+
+type operationStatsKey struct{}
+
+// RecordRetry records that the provider is retrying the operation running in ctx.
+// Providers call it from their retry loops before each attempt after the first;
+// the count is reported on the operation's span and to Config.Metrics.
+// It does nothing when ctx doesn't belong to a Client operation.
+func RecordRetry(ctx context.Context) {
+	if stats, ok := ctx.Value(operationStatsKey{}).(*operationStats); ok {
+		stats.retries.Add(1)
+	}
+}
+
+// withOperationStats returns a context that collects provider reports for one operation.
+func withOperationStats(ctx context.Context) (context.Context, *operationStats) {
+	stats := &operationStats{}
+	return context.WithValue(ctx, operationStatsKey{}, stats), stats
+}
+
+// startOperationSpan starts the span for a Client operation.
+func (c *Client) startOperationSpan(ctx context.Context, call *Call) (context.Context, Span) {
+	tracer := c.config.Tracer
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx = ContextWithTracer(ctx, tracer)
+	return tracer.Start(ctx, "cloudsdk."+string(call.Service)+"."+call.Operation,
+		StringAttr(AttrProvider, call.Provider),
+		StringAttr(AttrService, string(call.Service)),
+		StringAttr(AttrOperation, call.Operation),
+	)
+}
+
+// endOperationSpan records the outcome of a Client operation and ends its span.
+func endOperationSpan(span Span, call *Call, stats *operationStats, result interface{}, err error) {
+	attrs := []Attribute{IntAttr(AttrRetryCount, int(stats.retries.Load()))}
+	if id := resourceID(call, result); id != "" {
+		attrs = append(attrs, StringAttr(AttrResourceID, id))
+	}
+	if err != nil {
+		var cloudErr *CloudError
+		if errors.As(err, &cloudErr) {
+			attrs = append(attrs, StringAttr(AttrErrorCode, string(cloudErr.Code)))
+		}
+		span.RecordError(err)
+	}
+	span.SetAttributes(attrs...)
+	span.End()
+}
+
+// resourceID returns the ID of the resource an operation acted on, taken from
+// its result when it returns a resource and from its arguments otherwise.
+// Key pairs are identified by name, as KeyPairsService.Delete takes it, and
+// failed creates by the name in their config. Operations added to the
+// services need a case here for results and configs that carry the ID.
+func resourceID(call *Call, result interface{}) string {
+	switch r := result.(type) {
+	case *services.VM:
+		if r != nil {
+			return r.ID
+		}
+	case *services.DBInstance:
+		if r != nil {
+			return r.ID
+		}
+	case *services.PlacementGroup:
+		if r != nil {
+			return r.GroupName
+		}
+	case *services.SpotInstanceRequest:
+		if r != nil {
+			return r.SpotInstanceRequestId
+		}
+	case *services.Volume:
+		if r != nil {
+			return r.ID
+		}
+	case *services.VolumeSnapshot:
+		if r != nil {
+			return r.ID
+		}
+	case *services.Image:
+		if r != nil {
+			return r.ID
+		}
+	case *services.KeyPair:
+		if r != nil {
+			return r.Name
+		}
+	case *services.SecurityGroup:
+		if r != nil {
+			return r.ID
+		}
+	}
+
+	if len(call.Args) == 0 {
+		return ""
+	}
+	switch a := call.Args[0].(type) {
+	case string:
+		// Object operations take (bucket, key). Other operations' second
+		// argument, such as ResizeVM's instance type, isn't part of the ID.
+		switch call.Operation {
+		case OpPutObject, OpGetObject, OpDeleteObject:
+			if len(call.Args) > 1 {
+				if key, ok := call.Args[1].(string); ok {
+					return a + "/" + key
+				}
+			}
+		}
+		return a
+	case *services.VMConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.VolumeConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.SecurityGroupConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.CreateImageConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.BucketConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.DBConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.PlacementGroupConfig:
+		if a != nil {
+			return a.GroupName
+		}
+	case *services.VolumeSnapshotConfig:
+		if a != nil {
+			return a.VolumeID
+		}
+	case *services.KeyPairConfig:
+		if a != nil {
+			return a.Name
+		}
+	case *services.ImportKeyPairConfig:
+		if a != nil {
+			return a.Name
+		}
+	}
+	return ""
+}
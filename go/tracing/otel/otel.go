@@ -0,0 +1,90 @@
+// Package otel adapts an OpenTelemetry tracer to the cloudsdk.Tracer interface.
+//
+// Example:
+//
+//	client := cloudsdk.New(provider, &cloudsdk.Config{
+//	    Tracer: otel.NewTracer(otelapi.Tracer("github.com/VAIBHAVSING/Cloudsdk/go")),
+//	})
+//
+// Spans started through the adapter are ordinary OpenTelemetry spans, so they
+// nest under any span already in the context and are exported by whatever
+// TracerProvider the application has configured.
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer implements cloudsdk.Tracer on top of an OpenTelemetry trace.Tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a cloudsdk.Tracer that starts spans with tracer.
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// Start implements cloudsdk.Tracer.
+// Spans are started with kind Client since each one wraps calls to a cloud API.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...cloudsdk.Attribute) (context.Context, cloudsdk.Span) {
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convertAttributes(attrs)...),
+	)
+	return ctx, &Span{span: span}
+}
+
+// Span implements cloudsdk.Span on top of an OpenTelemetry trace.Span.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes implements cloudsdk.Span.
+func (s *Span) SetAttributes(attrs ...cloudsdk.Attribute) {
+	s.span.SetAttributes(convertAttributes(attrs)...)
+}
+
+// RecordError implements cloudsdk.Span. It records err as a span event and
+// sets the span status to Error.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements cloudsdk.Span.
+func (s *Span) End() {
+	s.span.End()
+}
+
+// convertAttributes converts SDK attributes to OpenTelemetry key-values.
+// Values of unsupported types are recorded as strings.
+func convertAttributes(attrs []cloudsdk.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			converted = append(converted, attribute.String(attr.Key, v))
+		case bool:
+			converted = append(converted, attribute.Bool(attr.Key, v))
+		case int:
+			converted = append(converted, attribute.Int(attr.Key, v))
+		case int64:
+			converted = append(converted, attribute.Int64(attr.Key, v))
+		case float64:
+			converted = append(converted, attribute.Float64(attr.Key, v))
+		default:
+			converted = append(converted, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return converted
+}
@@ -0,0 +1,157 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracer is an OpenTelemetry tracer that records the spans started
+// through it.
+type recordingTracer struct {
+	embedded.Tracer
+
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	parent, _ := trace.SpanFromContext(ctx).(*recordingSpan)
+	span := &recordingSpan{
+		name:   name,
+		kind:   config.SpanKind(),
+		parent: parent,
+		attrs:  make(map[attribute.Key]attribute.Value),
+	}
+	span.SetAttributes(config.Attributes()...)
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// recordingSpan records what is done to a span. Methods it doesn't override
+// are no-ops.
+type recordingSpan struct {
+	noop.Span
+
+	name          string
+	kind          trace.SpanKind
+	parent        *recordingSpan
+	attrs         map[attribute.Key]attribute.Value
+	errs          []error
+	status        codes.Code
+	statusMessage string
+	ended         bool
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...attribute.KeyValue) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.status, s.statusMessage = code, description
+}
+
+func (s *recordingSpan) End(opts ...trace.SpanEndOption) {
+	s.ended = true
+}
+
+func TestTracer(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	otelTracer := &recordingTracer{}
+	tracer := NewTracer(otelTracer)
+
+	ctx, span := tracer.Start(context.Background(), "cloudsdk.compute.CreateVM",
+		cloudsdk.StringAttr(cloudsdk.AttrProvider, "aws"),
+		cloudsdk.IntAttr(cloudsdk.AttrRetryCount, 2),
+	)
+	span.SetAttributes(
+		cloudsdk.Attribute{Key: "bool", Value: true},
+		cloudsdk.Attribute{Key: "int64", Value: int64(7)},
+		cloudsdk.Attribute{Key: "float64", Value: 0.5},
+		cloudsdk.Attribute{Key: "other", Value: []string{"a", "b"}},
+	)
+
+	// Spans nest under the span in the context
+	_, child := tracer.Start(ctx, "RunInstances")
+	child.End()
+	span.End()
+
+	helper.AssertEqual(2, len(otelTracer.spans))
+	parent := otelTracer.spans[0]
+	helper.AssertEqual("cloudsdk.compute.CreateVM", parent.name)
+	helper.AssertEqual(trace.SpanKindClient, parent.kind)
+	helper.AssertEqual("aws", parent.attrs[attribute.Key(cloudsdk.AttrProvider)].AsString())
+	helper.AssertEqual(int64(2), parent.attrs[attribute.Key(cloudsdk.AttrRetryCount)].AsInt64())
+	helper.AssertEqual(true, parent.attrs["bool"].AsBool())
+	helper.AssertEqual(int64(7), parent.attrs["int64"].AsInt64())
+	helper.AssertEqual(0.5, parent.attrs["float64"].AsFloat64())
+	// Values of other types are recorded as strings
+	helper.AssertEqual("[a b]", parent.attrs["other"].AsString())
+	helper.AssertEqual(true, parent.ended)
+
+	helper.AssertEqual(parent, otelTracer.spans[1].parent)
+	helper.AssertEqual(true, otelTracer.spans[1].ended)
+}
+
+func TestSpanRecordError(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	otelTracer := &recordingTracer{}
+	_, span := NewTracer(otelTracer).Start(context.Background(), "cloudsdk.compute.GetVM")
+
+	// A nil error leaves the span's status unset
+	span.RecordError(nil)
+	recorded := otelTracer.spans[0]
+	helper.AssertEqual(0, len(recorded.errs))
+	helper.AssertEqual(codes.Unset, recorded.status)
+
+	err := errors.New("instance not found")
+	span.RecordError(err)
+	helper.AssertEqual(1, len(recorded.errs))
+	helper.AssertEqual(err, recorded.errs[0])
+	helper.AssertEqual(codes.Error, recorded.status)
+	helper.AssertEqual("instance not found", recorded.statusMessage)
+}
+
+func TestTracerWithClient(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	otelTracer := &recordingTracer{}
+	client := cloudsdk.New(mock.New("us-east-1"), &cloudsdk.Config{Tracer: NewTracer(otelTracer)})
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("traced-vm"))
+	helper.AssertNoError(err)
+	_, err = client.Compute().GetVM(ctx, "i-missing")
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	helper.AssertEqual(2, len(otelTracer.spans))
+	create := otelTracer.spans[0]
+	helper.AssertEqual("cloudsdk.compute.CreateVM", create.name)
+	helper.AssertEqual(vm.ID, create.attrs[attribute.Key(cloudsdk.AttrResourceID)].AsString())
+	helper.AssertEqual(codes.Unset, create.status)
+	helper.AssertEqual(true, create.ended)
+
+	get := otelTracer.spans[1]
+	helper.AssertEqual(string(cloudsdk.ErrResourceNotFound), get.attrs[attribute.Key(cloudsdk.AttrErrorCode)].AsString())
+	helper.AssertEqual(codes.Error, get.status)
+	helper.AssertEqual(true, get.ended)
+}
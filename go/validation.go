@@ -0,0 +1,120 @@
+package cloudsdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+)
+
+// ValidationErrors reports every invalid field of a configuration, as one
+// CloudError with code ErrInvalidConfig and Field set per field.
+//
+// errors.As and errors.Is match the individual CloudErrors, so code that
+// only checks for ErrInvalidConfig works unchanged:
+//
+//	var cloudErr *cloudsdk.CloudError
+//	if errors.As(err, &cloudErr) && cloudErr.Code == cloudsdk.ErrInvalidConfig {
+//	    // cloudErr is the first invalid field
+//	}
+//
+//	var errs cloudsdk.ValidationErrors
+//	if errors.As(err, &errs) {
+//	    for _, fieldErr := range errs {
+//	        fmt.Println(fieldErr.Field, fieldErr.Message)
+//	    }
+//	}
+type ValidationErrors []*CloudError
+
+// Error implements the error interface, listing each invalid field.
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %d invalid configuration fields", ErrInvalidConfig, len(e))
+	for _, err := range e {
+		fmt.Fprintf(&b, "\n  - %s", err.Message)
+	}
+	return b.String()
+}
+
+// Unwrap returns the CloudError of each invalid field.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ValidateConfig checks config, such as a *services.VMConfig, against its
+// validate struct tags with services.Validate. It returns ValidationErrors
+// listing every invalid field, or nil if config is valid.
+//
+// Providers call ValidateConfig before sending a configuration to the cloud.
+func ValidateConfig(provider string, service string, config interface{}) error {
+	err := services.Validate(config)
+	var fieldErrs services.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	return invalidConfigErrors(provider, service, fieldErrs)
+}
+
+// ValidateFirewallRules checks the rules passed to
+// services.SecurityGroupsService AddRules and RevokeRules: each against its
+// validate struct tags, and that it names exactly one peer and a port range
+// that suits its protocol. Like ValidateConfig it returns ValidationErrors,
+// with fields such as "Rules[1].CIDR", or nil if every rule is valid.
+func ValidateFirewallRules(provider string, service string, rules []services.FirewallRule) error {
+	config := struct {
+		Rules []services.FirewallRule `validate:"required"`
+	}{Rules: rules}
+
+	var fieldErrs services.ValidationErrors
+	if err := services.Validate(config); err != nil && !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	for i, rule := range rules {
+		path := fmt.Sprintf("Rules[%d]", i)
+		if (rule.CIDR == "") == (rule.SourceGroupID == "") {
+			fieldErrs = append(fieldErrs, &services.FieldError{Field: path, Rule: "peer",
+				Message: "must set exactly one of CIDR and SourceGroupID"})
+		}
+		switch rule.Protocol {
+		case "tcp", "udp":
+			if rule.ToPort != 0 && rule.ToPort < rule.FromPort {
+				fieldErrs = append(fieldErrs, &services.FieldError{Field: path + ".ToPort", Rule: "port_range",
+					Message: "must be 0 or at least FromPort"})
+			}
+		case "icmp", "all":
+			if rule.FromPort != 0 || rule.ToPort != 0 {
+				fieldErrs = append(fieldErrs, &services.FieldError{Field: path + ".FromPort", Rule: "port_range",
+					Message: fmt.Sprintf("must be 0 for %s rules, which match every port", rule.Protocol)})
+			}
+		}
+	}
+
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	return invalidConfigErrors(provider, service, fieldErrs)
+}
+
+// invalidConfigErrors converts the FieldErrors of services.Validate to
+// ValidationErrors.
+func invalidConfigErrors(provider string, service string, fieldErrs services.ValidationErrors) ValidationErrors {
+	errs := make(ValidationErrors, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		metadata := map[string]string{"rule": fieldErr.Rule}
+		if fieldErr.Param != "" {
+			metadata["param"] = fieldErr.Param
+		}
+		errs[i] = NewInvalidConfigError(provider, service, fieldErr.Field, fieldErr.Message).
+			WithContext("", metadata)
+	}
+	return errs
+}
@@ -0,0 +1,120 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// assertFieldRules checks that err reports exactly the invalid fields in
+// want, each failing the given rule.
+func assertFieldRules(t *testing.T, err error, want map[string]string) {
+	t.Helper()
+	var errs services.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected services.ValidationErrors, got %v", err)
+	}
+	rules := make(map[string]string, len(errs))
+	for _, fieldErr := range errs {
+		rules[fieldErr.Field] = fieldErr.Rule
+	}
+	if !reflect.DeepEqual(want, rules) {
+		t.Errorf("expected invalid fields %v, got %v", want, rules)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+
+	// Generated configurations are valid
+	helper.AssertNoError(services.Validate(cloudsdktesting.GenerateVMConfig("web-server")))
+	helper.AssertNoError(services.Validate(cloudsdktesting.GenerateBucketConfig("my-bucket-2024")))
+	helper.AssertNoError(services.Validate(cloudsdktesting.GenerateDBConfig("app-db")))
+
+	vm := cloudsdktesting.GenerateVMConfig("-web_server")
+	vm.ImageID = ""
+	vm.Tags["owner"] = string(make([]byte, 256))
+	assertFieldRules(t, services.Validate(vm), map[string]string{
+		"Name":        "hostname",
+		"ImageID":     "required",
+		"Tags[owner]": "max",
+	})
+
+	bucket := cloudsdktesting.GenerateBucketConfig("xn--bucket")
+	bucket.ACL = "everyone"
+	bucket.ReplicationConfig = &services.ReplicationConfiguration{
+		Role:  "arn:aws:iam::123456789012:role/replication",
+		Rules: []services.ReplicationRule{{Status: "On"}},
+	}
+	assertFieldRules(t, services.Validate(bucket), map[string]string{
+		"Name":                              "bucket_name",
+		"ACL":                               "oneof",
+		"ReplicationConfig.Rules[0].ID":     "required",
+		"ReplicationConfig.Rules[0].Status": "oneof",
+		"ReplicationConfig.Rules[0].Destination.Bucket": "required",
+	})
+
+	retention, interval := int32(40), int32(2)
+	db := cloudsdktesting.GenerateDBConfig("app-db")
+	db.Engine = "cassandra"
+	db.MasterUsername = "1admin"
+	db.MasterPassword = "alllowercase1"
+	db.BackupRetentionPeriod = &retention
+	db.MonitoringInterval = &interval
+	db.BackupWindow = "03:00-03:15"
+	db.MaintenanceWindow = "sun:23:50-mon:00:30"
+	assertFieldRules(t, services.Validate(db), map[string]string{
+		"Engine":                "oneof",
+		"MasterUsername":        "starts_with_letter",
+		"MasterPassword":        "strong_password",
+		"BackupRetentionPeriod": "max",
+		"MonitoringInterval":    "oneof",
+		"BackupWindow":          "backup_window_format",
+	})
+
+	assertFieldRules(t, services.Validate((*services.VMConfig)(nil)), map[string]string{"config": "required"})
+}
+
+func TestClientValidatesConfigs(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, nil)
+
+	_, err := client.Compute().CreateVM(ctx, &services.VMConfig{Name: "web server"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	// Every invalid field is reported at once, with its path
+	var errs cloudsdk.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected cloudsdk.ValidationErrors, got %v", err)
+	}
+	helper.AssertEqual(3, len(errs))
+	helper.AssertEqual("Name", errs[0].Field)
+	helper.AssertEqual("ImageID", errs[1].Field)
+	helper.AssertEqual("InstanceType", errs[2].Field)
+	helper.AssertEqual("required", errs[1].Context.Metadata["rule"])
+	helper.AssertEqual("mock", errs[0].Provider)
+	helper.AssertContains(err.Error(), "3 invalid configuration fields")
+
+	// Nothing was created
+	vms, err := client.Compute().ListVMs(ctx)
+	helper.AssertNoError(err)
+	helper.AssertEqual(0, len(vms))
+
+	err = client.Storage().CreateBucket(ctx, &services.BucketConfig{Name: "My_Bucket"})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+	if !errors.Is(err, &cloudsdk.CloudError{Code: cloudsdk.ErrInvalidConfig}) {
+		t.Errorf("expected errors.Is to match ErrInvalidConfig, got %v", err)
+	}
+
+	_, err = client.Database().CreateDB(ctx, nil)
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+}
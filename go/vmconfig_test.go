@@ -0,0 +1,67 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestCreateVMConfigFields(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+
+	enabled, disabled := true, false
+	config := cloudsdktesting.GenerateVMConfig("web-server")
+	config.KeyName = "deploy-key"
+	config.SecurityGroups = []string{"sg-web", "sg-ssh"}
+	config.SubnetID = "subnet-12345"
+	config.AssignPublicIP = &disabled
+	config.PlacementGroup = "web-spread"
+	config.IamInstanceProfile = "web-role"
+	config.Monitoring = &enabled
+	config.EbsOptimized = &enabled
+
+	created, err := client.Compute().CreateVM(ctx, config)
+	helper.AssertNoError(err)
+
+	// The fields are stored, and returned by later reads
+	vm, err := client.Compute().GetVM(ctx, created.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(config.InstanceType, vm.InstanceType)
+	helper.AssertEqual("deploy-key", vm.KeyName)
+	helper.AssertEqual(2, len(vm.SecurityGroups))
+	helper.AssertEqual("subnet-12345", vm.SubnetID)
+	helper.AssertEqual("", vm.PublicIP)
+	helper.AssertEqual("web-spread", vm.PlacementGroup)
+	helper.AssertEqual("web-role", vm.IamInstanceProfile)
+	helper.AssertEqual(true, vm.Monitoring)
+	helper.AssertEqual(true, vm.EbsOptimized)
+	helper.AssertEqual(len(config.Tags), len(vm.Tags))
+
+	// Changing the config afterwards doesn't change the VM
+	config.SecurityGroups[0] = "sg-other"
+	for key := range config.Tags {
+		config.Tags[key] = "changed"
+	}
+	vm, err = client.Compute().GetVM(ctx, created.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual("sg-web", vm.SecurityGroups[0])
+	for _, value := range vm.Tags {
+		if value == "changed" {
+			t.Errorf("expected the VM's tags to be a copy of the config's")
+		}
+	}
+
+	// Unset options keep the provider defaults
+	vm, err = client.Compute().CreateVM(ctx, &services.VMConfig{Name: "plain", ImageID: "ami-12345678", InstanceType: "t3.micro"})
+	helper.AssertNoError(err)
+	helper.AssertEqual(false, vm.Monitoring)
+	if vm.PublicIP == "" {
+		t.Errorf("expected a public IP by default")
+	}
+}
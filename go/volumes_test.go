@@ -0,0 +1,130 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+func TestVolumes(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), &cloudsdk.Config{
+		DefaultTags: map[string]string{"Team": "data"},
+	})
+	volumes := client.Compute().Volumes()
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("db-server"))
+	helper.AssertNoError(err)
+
+	volume, err := volumes.Create(ctx, &services.VolumeConfig{
+		Name:             "data",
+		AvailabilityZone: "us-east-1a",
+		SizeGiB:          100,
+		VolumeType:       "gp3",
+	})
+	helper.AssertNoError(err)
+	helper.AssertEqual("available", volume.State)
+	helper.AssertEqual("data", volume.Tags["Team"])
+	helper.AssertNoError(services.WaitUntilVolumeAvailable(ctx, client.Compute(), volume.ID, fastPolls))
+
+	_, err = volumes.Create(ctx, &services.VolumeConfig{SizeGiB: 100})
+	helper.AssertErrorCode(err, cloudsdk.ErrInvalidConfig)
+
+	// Attaching shows the volume on the VM
+	helper.AssertNoError(volumes.Attach(ctx, volume.ID, vm.ID, "/dev/sdf"))
+	helper.AssertNoError(services.WaitUntilVolumeAttached(ctx, client.Compute(), volume.ID, fastPolls))
+	vm, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(vm.Volumes))
+	helper.AssertEqual(volume.ID, vm.Volumes[0].VolumeID)
+	helper.AssertEqual("/dev/sdf", vm.Volumes[0].Device)
+
+	attached, err := volumes.List(ctx, &services.VolumeFilter{VMID: vm.ID})
+	helper.AssertNoError(err)
+	helper.AssertEqual(1, len(attached))
+	helper.AssertEqual("in-use", attached[0].State)
+
+	// An attached volume can't be attached again or deleted
+	helper.AssertErrorCode(volumes.Attach(ctx, volume.ID, vm.ID, "/dev/sdg"), cloudsdk.ErrInvalidState)
+	helper.AssertErrorCode(volumes.Delete(ctx, volume.ID), cloudsdk.ErrInvalidState)
+
+	// and neither can a device be used twice
+	other, err := volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SizeGiB: 50})
+	helper.AssertNoError(err)
+	helper.AssertErrorCode(volumes.Attach(ctx, other.ID, vm.ID, "/dev/sdf"), cloudsdk.ErrResourceConflict)
+
+	// Volumes grow but never shrink
+	helper.AssertNoError(volumes.Resize(ctx, volume.ID, 200))
+	helper.AssertErrorCode(volumes.Resize(ctx, volume.ID, 150), cloudsdk.ErrInvalidConfig)
+	volume, err = volumes.Get(ctx, volume.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(int32(200), volume.SizeGiB)
+
+	// A snapshot can seed a new volume of the same size
+	snapshot, err := volumes.Snapshot(ctx, &services.VolumeSnapshotConfig{VolumeID: volume.ID, Description: "before upgrade"})
+	helper.AssertNoError(err)
+	helper.AssertEqual(volume.ID, snapshot.VolumeID)
+	helper.AssertEqual("data", snapshot.Tags["Team"])
+	restored, err := volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SnapshotID: snapshot.ID})
+	helper.AssertNoError(err)
+	helper.AssertEqual(int32(200), restored.SizeGiB)
+
+	helper.AssertNoError(volumes.Detach(ctx, volume.ID))
+	helper.AssertErrorCode(volumes.Detach(ctx, volume.ID), cloudsdk.ErrInvalidState)
+	vm, err = client.Compute().GetVM(ctx, vm.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual(0, len(vm.Volumes))
+
+	helper.AssertNoError(volumes.Delete(ctx, volume.ID))
+	_, err = volumes.Get(ctx, volume.ID)
+	helper.AssertErrorCode(err, cloudsdk.ErrResourceNotFound)
+
+	available, err := volumes.List(ctx, &services.VolumeFilter{States: []string{"available"}})
+	helper.AssertNoError(err)
+	helper.AssertEqual(2, len(available))
+}
+
+func TestVolumesDeleteVMDetaches(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+	volumes := client.Compute().Volumes()
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("db-server"))
+	helper.AssertNoError(err)
+	volume, err := volumes.Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SizeGiB: 100})
+	helper.AssertNoError(err)
+	helper.AssertNoError(volumes.Attach(ctx, volume.ID, vm.ID, "/dev/sdf"))
+
+	helper.AssertNoError(client.Compute().DeleteVM(ctx, vm.ID))
+	volume, err = volumes.Get(ctx, volume.ID)
+	helper.AssertNoError(err)
+	helper.AssertEqual("available", volume.State)
+	helper.AssertEqual(0, len(volume.Attachments))
+}
+
+func TestVolumesDryRun(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	provider := mock.New("us-east-1")
+	client := cloudsdk.New(provider, &cloudsdk.Config{DryRun: true})
+
+	_, err := client.Compute().Volumes().Create(ctx, &services.VolumeConfig{AvailabilityZone: "us-east-1a", SizeGiB: 100})
+	if !errors.Is(err, cloudsdk.ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+	helper.AssertEqual(false, provider.WasCalled(cloudsdk.OpCreateVolume))
+	helper.AssertEqual(1, len(client.Plan()))
+	helper.AssertEqual(cloudsdk.OpCreateVolume, client.Plan()[0].Operation)
+
+	// Reads still go to the provider
+	_, err = client.Compute().Volumes().List(ctx, nil)
+	helper.AssertNoError(err)
+	helper.AssertEqual(true, provider.WasCalled(cloudsdk.OpListVolumes))
+}
@@ -0,0 +1,126 @@
+package cloudsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudsdk "github.com/VAIBHAVSING/Cloudsdk/go"
+	"github.com/VAIBHAVSING/Cloudsdk/go/providers/mock"
+	"github.com/VAIBHAVSING/Cloudsdk/go/services"
+	cloudsdktesting "github.com/VAIBHAVSING/Cloudsdk/go/testing"
+)
+
+// fastPolls keeps waiter tests quick.
+var fastPolls = services.WithPollInterval(time.Millisecond, 2*time.Millisecond)
+
+// scriptedCompute returns the next of its VM states, or errors, from each
+// GetVM call, repeating the last one.
+type scriptedCompute struct {
+	services.Compute
+	states []string
+	errs   []error
+	calls  int
+}
+
+func (c *scriptedCompute) GetVM(ctx context.Context, id string) (*services.VM, error) {
+	i := c.calls
+	if i >= len(c.states) {
+		i = len(c.states) - 1
+	}
+	c.calls++
+	if i < len(c.errs) && c.errs[i] != nil {
+		return nil, c.errs[i]
+	}
+	return &services.VM{ID: id, State: c.states[i]}, nil
+}
+
+func TestWaitUntilVMRunning(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+
+	// Not found and throttled polls are retried; progress is reported
+	compute := &scriptedCompute{
+		states: []string{"", "", "pending", "running"},
+		errs: []error{
+			cloudsdk.NewResourceNotFoundError("mock", "compute", "VM", "i-1"),
+			cloudsdk.NewRateLimitError("mock", "compute", "GetVM", 0),
+		},
+	}
+	var progress []services.WaitProgress
+	err := services.WaitUntilVMRunning(ctx, compute, "i-1", fastPolls,
+		services.WithProgress(func(p services.WaitProgress) { progress = append(progress, p) }))
+	helper.AssertNoError(err)
+	helper.AssertEqual(4, len(progress))
+	helper.AssertEqual(true, services.IsNotFound(progress[0].Err))
+	helper.AssertEqual("pending", progress[2].State)
+	helper.AssertEqual(4, progress[3].Attempt)
+	helper.AssertEqual("running", progress[3].Target)
+
+	// Terminal states fail early
+	compute = &scriptedCompute{states: []string{"pending", "shutting-down"}}
+	err = services.WaitUntilVMRunning(ctx, compute, "i-1", fastPolls)
+	if !errors.Is(err, services.ErrTerminalState) {
+		t.Fatalf("expected ErrTerminalState, got %v", err)
+	}
+	var waitErr *services.WaitError
+	if !errors.As(err, &waitErr) {
+		t.Fatalf("expected *services.WaitError, got %v", err)
+	}
+	helper.AssertEqual("shutting-down", waitErr.State)
+	helper.AssertEqual(2, compute.calls)
+
+	// So do errors that polling again won't fix
+	compute = &scriptedCompute{
+		states: []string{""},
+		errs:   []error{cloudsdk.NewAuthorizationError("mock", "compute", "GetVM", nil)},
+	}
+	err = services.WaitUntilVMRunning(ctx, compute, "i-1", fastPolls)
+	helper.AssertErrorCode(err, cloudsdk.ErrAuthorization)
+	helper.AssertEqual(1, compute.calls)
+
+	// The context bounds the wait
+	compute = &scriptedCompute{states: []string{"pending"}}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err = services.WaitUntilVMRunning(timeoutCtx, compute, "i-1", fastPolls)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	helper.AssertContains(err.Error(), `last state "pending"`)
+}
+
+func TestWaitersWithMockProvider(t *testing.T) {
+	helper := cloudsdktesting.NewTestHelper(t)
+	ctx := context.Background()
+	client := cloudsdk.New(mock.New("us-east-1"), nil)
+
+	vm, err := client.Compute().CreateVM(ctx, cloudsdktesting.GenerateVMConfig("web-server"))
+	helper.AssertNoError(err)
+	helper.AssertNoError(services.WaitUntilVMRunning(ctx, client.Compute(), vm.ID, fastPolls))
+	helper.AssertNoError(client.Compute().StopVM(ctx, vm.ID))
+	helper.AssertNoError(services.WaitUntilVMStopped(ctx, client.Compute(), vm.ID, fastPolls))
+	helper.AssertNoError(client.Compute().DeleteVM(ctx, vm.ID))
+	helper.AssertNoError(services.WaitUntilVMTerminated(ctx, client.Compute(), vm.ID, fastPolls))
+
+	db, err := client.Database().CreateDB(ctx, cloudsdktesting.GenerateDBConfig("app-db"))
+	helper.AssertNoError(err)
+	helper.AssertNoError(services.WaitUntilDBAvailable(ctx, client.Database(), db.ID, fastPolls))
+	helper.AssertNoError(client.Database().DeleteDB(ctx, db.ID))
+	helper.AssertNoError(services.WaitUntilDBDeleted(ctx, client.Database(), db.ID, fastPolls))
+
+	spot := client.Compute().SpotInstances()
+	request, err := spot.Request(ctx, &services.SpotInstanceConfig{ImageID: "ami-0abcdef1234567890", InstanceType: "t3.micro"})
+	helper.AssertNoError(err)
+	fulfilled, err := services.WaitUntilSpotFulfilled(ctx, client.Compute(), request.SpotInstanceRequestId, fastPolls)
+	helper.AssertNoError(err)
+	helper.AssertEqual(request.InstanceId, fulfilled.InstanceId)
+
+	// A cancelled request will never be fulfilled
+	helper.AssertNoError(spot.Cancel(ctx, request.SpotInstanceRequestId))
+	_, err = services.WaitUntilSpotFulfilled(ctx, client.Compute(), request.SpotInstanceRequestId, fastPolls)
+	if !errors.Is(err, services.ErrTerminalState) {
+		t.Fatalf("expected ErrTerminalState, got %v", err)
+	}
+}